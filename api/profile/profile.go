@@ -90,6 +90,27 @@ type Profile struct {
 	// TLSRoutingEnabled indicates that proxy supports ALPN SNI server where
 	// all proxy services are exposed on a single TLS listener (Proxy Web Listener).
 	TLSRoutingEnabled bool `yaml:"tls_routing_enabled,omitempty"`
+
+	// DatabaseLocalProxies is this profile's persisted local proxy
+	// configuration for databases, keyed by database service name. It lets
+	// tools that are configured once (e.g. GUI database clients) keep
+	// connecting to the same fixed local port across tsh sessions.
+	DatabaseLocalProxies map[string]DatabaseLocalProxyProfile `yaml:"database_local_proxies,omitempty"`
+}
+
+// DatabaseLocalProxyProfile is a named local proxy configuration persisted
+// for a specific database in the user's profile.
+type DatabaseLocalProxyProfile struct {
+	// Port is the fixed local port the proxy should listen on. If 0, a
+	// random available port is chosen, same as if no profile were saved.
+	Port int `yaml:"port,omitempty"`
+	// NoTLS starts the local proxy without TLS termination, for clients
+	// that provide their own mutual TLS handling.
+	NoTLS bool `yaml:"no_tls,omitempty"`
+	// AutoStart indicates this local proxy should be started automatically
+	// by commands that establish database connections for this database,
+	// rather than requiring an explicit `tsh proxy db` invocation.
+	AutoStart bool `yaml:"auto_start,omitempty"`
 }
 
 // Name returns the name of the profile.
@@ -256,6 +256,23 @@ const (
 	// KindSessionTracker is a resource that tracks a live session.
 	KindSessionTracker = "session_tracker"
 
+	// KindStaticHostUser is a resource that describes a static host user
+	// that should be created and managed by nodes.
+	KindStaticHostUser = "static_host_user"
+
+	// KindHostUserRecord is a resource reported by a node describing a
+	// Teleport-created host user present on that node.
+	KindHostUserRecord = "host_user_record"
+
+	// KindHostUserGCPolicy is the singleton resource configuring how long
+	// Teleport-created host users may remain on a node after their last
+	// session ends.
+	KindHostUserGCPolicy = "host_user_gc_policy"
+
+	// MetaNameHostUserGCPolicy is the exact name of the singleton resource
+	// holding the host user garbage collection policy.
+	MetaNameHostUserGCPolicy = "host-user-gc-policy"
+
 	// V5 is the fifth version of resources.
 	V5 = "v5"
 
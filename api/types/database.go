@@ -71,6 +71,11 @@ type Database interface {
 	GetAzure() Azure
 	// GetAD returns Active Directory database configuration.
 	GetAD() AD
+	// GetAdminUser returns the database admin user for automatic user provisioning.
+	GetAdminUser() DatabaseAdminUser
+	// SupportsAutoUsers returns true if the database is configured to automatically
+	// provision database users for access.
+	SupportsAutoUsers() bool
 	// GetType returns the database authentication type: self-hosted, RDS, Redshift or Cloud SQL.
 	GetType() string
 	// GetIAMPolicy returns AWS IAM policy for the database.
@@ -284,6 +289,17 @@ func (d *DatabaseV3) GetAD() AD {
 	return d.Spec.AD
 }
 
+// GetAdminUser returns the database admin user for automatic user provisioning.
+func (d *DatabaseV3) GetAdminUser() DatabaseAdminUser {
+	return d.Spec.AdminUser
+}
+
+// SupportsAutoUsers returns true if the database is configured to automatically
+// provision database users for access.
+func (d *DatabaseV3) SupportsAutoUsers() bool {
+	return d.Spec.AdminUser.Name != ""
+}
+
 // IsRDS returns true if this is an AWS RDS/Aurora instance.
 func (d *DatabaseV3) IsRDS() bool {
 	return d.GetType() == DatabaseTypeRDS
@@ -0,0 +1,389 @@
+/*
+Copyright 2022 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package types
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+
+	"github.com/gravitational/trace"
+
+	"github.com/gravitational/teleport/api/utils"
+)
+
+// StaticHostUser is a declarative host user that Teleport nodes matching
+// NodeLabels should create and keep up to date, independent of any
+// interactive SSH session.
+type StaticHostUser interface {
+	ResourceWithLabels
+
+	// GetLogin returns the OS login to create on matching nodes.
+	GetLogin() string
+	// GetGroups returns the list of OS groups the login should belong to.
+	GetGroups() []string
+	// GetSudoers returns sudoers entries that should be provisioned for the
+	// login.
+	GetSudoers() []string
+	// GetNodeLabels returns the label selector of nodes that should
+	// provision this host user.
+	GetNodeLabels() Labels
+	// GetHomeDirMode returns the octal permission mode, e.g. "0700", that
+	// the login's home directory should be created with, or "" to use the
+	// node's default.
+	GetHomeDirMode() string
+	// GetMaxPasswordAgeDays returns the maximum number of days the login's
+	// password may be used before it must be changed, or 0 if unset.
+	GetMaxPasswordAgeDays() int
+	// IsSystemAccount returns true if the login should be created as a
+	// system account (useradd -r) for a machine/automation identity.
+	IsSystemAccount() bool
+	// GetCPUQuota returns the systemd CPUQuota (e.g. "20%") to apply to the
+	// login's user slice, or "" for no limit.
+	GetCPUQuota() string
+	// GetMemoryMax returns the systemd MemoryMax (e.g. "512M") to apply to
+	// the login's user slice, or "" for no limit.
+	GetMemoryMax() string
+	// GetChrootDir returns the absolute path of a directory the login's
+	// home should be created under and its sessions confined to via
+	// chroot, or "" for no chroot confinement.
+	GetChrootDir() string
+}
+
+// NewStaticHostUser creates a new StaticHostUser resource.
+func NewStaticHostUser(name string, spec StaticHostUserSpecV1) (StaticHostUser, error) {
+	u := &StaticHostUserV1{
+		ResourceHeader: ResourceHeader{
+			Metadata: Metadata{
+				Name: name,
+			},
+		},
+		Spec: spec,
+	}
+	if err := u.CheckAndSetDefaults(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return u, nil
+}
+
+// StaticHostUserV1 implements StaticHostUser.
+type StaticHostUserV1 struct {
+	ResourceHeader
+	// Spec is the static host user spec.
+	Spec StaticHostUserSpecV1 `json:"spec"`
+}
+
+// StaticHostUserSpecV1 is the static host user spec.
+type StaticHostUserSpecV1 struct {
+	// Login is the OS login to create on matching nodes.
+	Login string `json:"login"`
+	// Groups is the list of OS groups the login should belong to.
+	Groups []string `json:"groups,omitempty"`
+	// Sudoers is a list of sudoers entries that should be provisioned for
+	// the login, one entry per line.
+	Sudoers []string `json:"sudoers,omitempty"`
+	// NodeLabels selects the nodes that should provision this host user.
+	NodeLabels Labels `json:"node_labels,omitempty"`
+	// HomeDirMode is the octal permission mode, e.g. "0700", that the
+	// login's home directory should be created with. If empty, the node's
+	// default (typically from /etc/login.defs) is used.
+	HomeDirMode string `json:"home_dir_mode,omitempty"`
+	// MaxPasswordAgeDays sets the maximum number of days the login's
+	// password may be used before it must be changed, applied via
+	// `chage -M` at creation. If 0, no maximum is set. Ignored when
+	// SystemAccount is true, since system accounts aren't subject to
+	// password aging.
+	MaxPasswordAgeDays int `json:"max_password_age_days,omitempty"`
+	// SystemAccount creates the login as a system account (useradd -r),
+	// for machine/automation identities rather than humans. System
+	// accounts are assigned a low UID from the system range and are not
+	// subject to password aging.
+	SystemAccount bool `json:"system_account,omitempty"`
+	// CPUQuota sets a systemd CPUQuota (e.g. "20%") on the login's user
+	// slice, for resource containment of ephemeral accounts. If empty, no
+	// limit is applied.
+	CPUQuota string `json:"cpu_quota,omitempty"`
+	// MemoryMax sets a systemd MemoryMax (e.g. "512M") on the login's user
+	// slice, for resource containment of ephemeral accounts. If empty, no
+	// limit is applied.
+	MemoryMax string `json:"memory_max,omitempty"`
+	// ChrootDir, if set, is an absolute path on the node's filesystem that
+	// becomes the login's home directory, and that its sessions are
+	// confined to via chroot. Confining a temporary account to a restricted
+	// filesystem view is the caller's responsibility to populate (shells,
+	// libraries, device nodes) before this host user is reconciled; this
+	// only provisions the account and wires sessions to chroot into it.
+	ChrootDir string `json:"chroot_dir,omitempty"`
+}
+
+func (h *StaticHostUserV1) setStaticFields() {
+	h.Kind = KindStaticHostUser
+	h.Version = V1
+}
+
+// CheckAndSetDefaults checks and sets default values for any missing fields.
+func (h *StaticHostUserV1) CheckAndSetDefaults() error {
+	h.setStaticFields()
+	if err := h.ResourceHeader.CheckAndSetDefaults(); err != nil {
+		return trace.Wrap(err)
+	}
+	if h.Spec.Login == "" {
+		return trace.BadParameter("StaticHostUser.Spec.Login is required")
+	}
+	if h.Spec.HomeDirMode != "" {
+		if _, err := strconv.ParseUint(h.Spec.HomeDirMode, 8, 32); err != nil {
+			return trace.BadParameter("StaticHostUser.Spec.HomeDirMode must be an octal permission mode, e.g. \"0700\": %v", err)
+		}
+	}
+	if h.Spec.MaxPasswordAgeDays < 0 {
+		return trace.BadParameter("StaticHostUser.Spec.MaxPasswordAgeDays must not be negative")
+	}
+	if h.Spec.ChrootDir != "" && !filepath.IsAbs(h.Spec.ChrootDir) {
+		return trace.BadParameter("StaticHostUser.Spec.ChrootDir must be an absolute path")
+	}
+	return nil
+}
+
+// GetLogin returns the OS login to create on matching nodes.
+func (h *StaticHostUserV1) GetLogin() string {
+	return h.Spec.Login
+}
+
+// GetGroups returns the list of OS groups the login should belong to.
+func (h *StaticHostUserV1) GetGroups() []string {
+	return h.Spec.Groups
+}
+
+// GetSudoers returns sudoers entries that should be provisioned for the
+// login.
+func (h *StaticHostUserV1) GetSudoers() []string {
+	return h.Spec.Sudoers
+}
+
+// GetNodeLabels returns the label selector of nodes that should provision
+// this host user.
+func (h *StaticHostUserV1) GetNodeLabels() Labels {
+	return h.Spec.NodeLabels
+}
+
+// GetHomeDirMode returns the octal permission mode the login's home
+// directory should be created with, or "" to use the node's default.
+func (h *StaticHostUserV1) GetHomeDirMode() string {
+	return h.Spec.HomeDirMode
+}
+
+// GetMaxPasswordAgeDays returns the maximum number of days the login's
+// password may be used before it must be changed, or 0 if unset.
+func (h *StaticHostUserV1) GetMaxPasswordAgeDays() int {
+	return h.Spec.MaxPasswordAgeDays
+}
+
+// IsSystemAccount returns true if the login should be created as a system
+// account (useradd -r) for a machine/automation identity.
+func (h *StaticHostUserV1) IsSystemAccount() bool {
+	return h.Spec.SystemAccount
+}
+
+// GetCPUQuota returns the systemd CPUQuota to apply to the login's user
+// slice, or "" for no limit.
+func (h *StaticHostUserV1) GetCPUQuota() string {
+	return h.Spec.CPUQuota
+}
+
+// GetMemoryMax returns the systemd MemoryMax to apply to the login's user
+// slice, or "" for no limit.
+func (h *StaticHostUserV1) GetMemoryMax() string {
+	return h.Spec.MemoryMax
+}
+
+// GetChrootDir returns the absolute path of the directory the login's home
+// should be created under and its sessions confined to via chroot, or ""
+// for no chroot confinement.
+func (h *StaticHostUserV1) GetChrootDir() string {
+	return h.Spec.ChrootDir
+}
+
+// Origin returns the origin value of the resource.
+func (h *StaticHostUserV1) Origin() string {
+	return h.Metadata.Origin()
+}
+
+// SetOrigin sets the origin value of the resource.
+func (h *StaticHostUserV1) SetOrigin(origin string) {
+	h.Metadata.SetOrigin(origin)
+}
+
+// GetAllLabels returns the resource's labels.
+func (h *StaticHostUserV1) GetAllLabels() map[string]string {
+	return h.Metadata.Labels
+}
+
+// String returns the host user's string representation.
+func (h *StaticHostUserV1) String() string {
+	return h.GetName()
+}
+
+// MatchSearch returns true if the given search values matches this host
+// user.
+func (h *StaticHostUserV1) MatchSearch(values []string) bool {
+	fieldVals := append(utils.MapToStrings(h.GetAllLabels()), h.GetName(), h.Spec.Login)
+	return MatchSearch(fieldVals, values, nil)
+}
+
+// HostUserRecord is a report, sent by a node, of a Teleport-created host
+// user present on that node.
+type HostUserRecord interface {
+	Resource
+
+	// GetHostID returns the ID of the node reporting this host user.
+	GetHostID() string
+	// GetLogin returns the reported OS login.
+	GetLogin() string
+	// GetUID returns the OS user ID of the reported login.
+	GetUID() string
+	// GetGroups returns the OS groups the reported login belongs to.
+	GetGroups() []string
+	// GetCreatedBy returns the identity of whichever static host user
+	// resource caused the login to be created.
+	GetCreatedBy() string
+	// GetRequestedLogin returns the login as originally requested, before
+	// the reporting node's username template, if any, was applied to
+	// produce GetLogin.
+	GetRequestedLogin() string
+	// IsDeletionRequested returns true if an operator has asked the
+	// reporting node to remove this host user.
+	IsDeletionRequested() bool
+	// SetDeletionRequested marks this host user for removal by the
+	// reporting node.
+	SetDeletionRequested(requested bool)
+}
+
+// NewHostUserRecord creates a new HostUserRecord resource.
+func NewHostUserRecord(hostID string, spec HostUserRecordSpecV1) (HostUserRecord, error) {
+	r := &HostUserRecordV1{
+		ResourceHeader: ResourceHeader{
+			Metadata: Metadata{
+				Name: fmt.Sprintf("%s/%s", hostID, spec.Login),
+			},
+		},
+		Spec: spec,
+	}
+	if err := r.CheckAndSetDefaults(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return r, nil
+}
+
+// HostUserRecordV1 implements HostUserRecord.
+type HostUserRecordV1 struct {
+	ResourceHeader
+	// Spec is the host user record spec.
+	Spec HostUserRecordSpecV1 `json:"spec"`
+}
+
+// HostUserRecordSpecV1 is the host user record spec.
+type HostUserRecordSpecV1 struct {
+	// HostID is the ID of the node reporting this host user.
+	HostID string `json:"host_id"`
+	// Login is the reported OS login.
+	Login string `json:"login"`
+	// UID is the OS user ID of the reported login.
+	UID string `json:"uid,omitempty"`
+	// Groups are the OS groups the reported login belongs to.
+	Groups []string `json:"groups,omitempty"`
+	// CreatedBy identifies the static host user resource that caused the
+	// login to be created.
+	CreatedBy string `json:"created_by,omitempty"`
+	// RequestedLogin is the login as originally requested, before the
+	// reporting node's username template, if any, was applied to produce
+	// Login. It equals Login unless the node is configured with a template.
+	RequestedLogin string `json:"requested_login,omitempty"`
+	// DeletionRequested is set when an operator has asked the reporting
+	// node to remove this host user. The node clears the record once it
+	// has done so.
+	DeletionRequested bool `json:"deletion_requested,omitempty"`
+}
+
+func (r *HostUserRecordV1) setStaticFields() {
+	r.Kind = KindHostUserRecord
+	r.Version = V1
+}
+
+// CheckAndSetDefaults checks and sets default values for any missing fields.
+func (r *HostUserRecordV1) CheckAndSetDefaults() error {
+	r.setStaticFields()
+	if err := r.ResourceHeader.CheckAndSetDefaults(); err != nil {
+		return trace.Wrap(err)
+	}
+	if r.Spec.HostID == "" {
+		return trace.BadParameter("HostUserRecord.Spec.HostID is required")
+	}
+	if r.Spec.Login == "" {
+		return trace.BadParameter("HostUserRecord.Spec.Login is required")
+	}
+	return nil
+}
+
+// GetHostID returns the ID of the node reporting this host user.
+func (r *HostUserRecordV1) GetHostID() string {
+	return r.Spec.HostID
+}
+
+// GetLogin returns the reported OS login.
+func (r *HostUserRecordV1) GetLogin() string {
+	return r.Spec.Login
+}
+
+// GetUID returns the OS user ID of the reported login.
+func (r *HostUserRecordV1) GetUID() string {
+	return r.Spec.UID
+}
+
+// GetGroups returns the OS groups the reported login belongs to.
+func (r *HostUserRecordV1) GetGroups() []string {
+	return r.Spec.Groups
+}
+
+// GetCreatedBy returns the identity of whichever static host user resource
+// caused the login to be created.
+func (r *HostUserRecordV1) GetCreatedBy() string {
+	return r.Spec.CreatedBy
+}
+
+// GetRequestedLogin returns the login as originally requested, before the
+// reporting node's username template, if any, was applied to produce
+// GetLogin.
+func (r *HostUserRecordV1) GetRequestedLogin() string {
+	return r.Spec.RequestedLogin
+}
+
+// IsDeletionRequested returns true if an operator has asked the reporting
+// node to remove this host user.
+func (r *HostUserRecordV1) IsDeletionRequested() bool {
+	return r.Spec.DeletionRequested
+}
+
+// SetDeletionRequested marks this host user for removal by the reporting
+// node.
+func (r *HostUserRecordV1) SetDeletionRequested(requested bool) {
+	r.Spec.DeletionRequested = requested
+}
+
+// String returns the host user record's string representation.
+func (r *HostUserRecordV1) String() string {
+	return r.GetName()
+}
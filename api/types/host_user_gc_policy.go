@@ -0,0 +1,159 @@
+/*
+Copyright 2023 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package types
+
+import (
+	"github.com/gravitational/trace"
+
+	"github.com/gravitational/teleport/api/utils"
+)
+
+const (
+	// HostUserGCActionDelete removes the local account, its home directory
+	// and any Teleport-managed configuration for it once MaxAge has elapsed
+	// since its last session ended. This is the historical behavior, now
+	// applied after MaxAge instead of immediately.
+	HostUserGCActionDelete = "delete"
+	// HostUserGCActionKeep leaves the local account in place indefinitely
+	// after its last session ends; nodes never remove it on their own.
+	HostUserGCActionKeep = "keep"
+	// HostUserGCActionArchive locks the account's password once MaxAge has
+	// elapsed, but leaves the account, its login shell and its home
+	// directory in place for later inspection instead of removing them.
+	HostUserGCActionArchive = "archive"
+)
+
+// HostUserGCPolicy is the cluster-level singleton resource governing how
+// long a Teleport-created host user may remain on a node after its last
+// session ends, and what happens to it once that time has passed.
+type HostUserGCPolicy interface {
+	ResourceWithLabels
+
+	// GetMaxAge returns how long a host user may remain on a node after its
+	// last session ends before Action is applied to it, or 0 to apply Action
+	// immediately (the historical behavior).
+	GetMaxAge() Duration
+	// GetAction returns what a node should do to a host user once MaxAge has
+	// elapsed since its last session: one of HostUserGCActionDelete,
+	// HostUserGCActionKeep or HostUserGCActionArchive.
+	GetAction() string
+}
+
+// NewHostUserGCPolicy creates a new HostUserGCPolicy resource with the
+// given spec, defaulting its name to MetaNameHostUserGCPolicy since it's a
+// cluster-level singleton.
+func NewHostUserGCPolicy(spec HostUserGCPolicySpecV1) (HostUserGCPolicy, error) {
+	p := &HostUserGCPolicyV1{
+		ResourceHeader: ResourceHeader{
+			Metadata: Metadata{
+				Name: MetaNameHostUserGCPolicy,
+			},
+		},
+		Spec: spec,
+	}
+	if err := p.CheckAndSetDefaults(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return p, nil
+}
+
+// HostUserGCPolicyV1 implements HostUserGCPolicy.
+type HostUserGCPolicyV1 struct {
+	ResourceHeader
+	// Spec is the host user garbage collection policy spec.
+	Spec HostUserGCPolicySpecV1 `json:"spec"`
+}
+
+// HostUserGCPolicySpecV1 is the host user garbage collection policy spec.
+type HostUserGCPolicySpecV1 struct {
+	// MaxAge is how long a Teleport-created host user may remain on a node
+	// after its last session ends before Action is applied to it. If 0,
+	// Action is applied immediately, matching the historical behavior of
+	// removing the account as soon as its last session ends.
+	MaxAge Duration `json:"max_age,omitempty"`
+	// Action is what a node should do to a host user once MaxAge has
+	// elapsed since its last session: one of HostUserGCActionDelete (the
+	// default), HostUserGCActionKeep or HostUserGCActionArchive.
+	Action string `json:"action,omitempty"`
+}
+
+func (p *HostUserGCPolicyV1) setStaticFields() {
+	p.Kind = KindHostUserGCPolicy
+	p.Version = V1
+}
+
+// CheckAndSetDefaults checks and sets default values for any missing fields.
+func (p *HostUserGCPolicyV1) CheckAndSetDefaults() error {
+	p.setStaticFields()
+	if p.Metadata.Name == "" {
+		p.Metadata.Name = MetaNameHostUserGCPolicy
+	}
+	if err := p.ResourceHeader.CheckAndSetDefaults(); err != nil {
+		return trace.Wrap(err)
+	}
+	if p.Spec.MaxAge < 0 {
+		return trace.BadParameter("HostUserGCPolicy.Spec.MaxAge must not be negative")
+	}
+	if p.Spec.Action == "" {
+		p.Spec.Action = HostUserGCActionDelete
+	}
+	switch p.Spec.Action {
+	case HostUserGCActionDelete, HostUserGCActionKeep, HostUserGCActionArchive:
+	default:
+		return trace.BadParameter("HostUserGCPolicy.Spec.Action must be one of %q, %q or %q, got %q",
+			HostUserGCActionDelete, HostUserGCActionKeep, HostUserGCActionArchive, p.Spec.Action)
+	}
+	return nil
+}
+
+// GetMaxAge returns how long a host user may remain on a node after its
+// last session ends before Action is applied to it.
+func (p *HostUserGCPolicyV1) GetMaxAge() Duration {
+	return p.Spec.MaxAge
+}
+
+// GetAction returns what a node should do to a host user once MaxAge has
+// elapsed since its last session.
+func (p *HostUserGCPolicyV1) GetAction() string {
+	return p.Spec.Action
+}
+
+// Origin returns the origin value of the resource.
+func (p *HostUserGCPolicyV1) Origin() string {
+	return p.Metadata.Origin()
+}
+
+// SetOrigin sets the origin value of the resource.
+func (p *HostUserGCPolicyV1) SetOrigin(origin string) {
+	p.Metadata.SetOrigin(origin)
+}
+
+// GetAllLabels returns the resource's labels.
+func (p *HostUserGCPolicyV1) GetAllLabels() map[string]string {
+	return p.Metadata.Labels
+}
+
+// String returns the policy's string representation.
+func (p *HostUserGCPolicyV1) String() string {
+	return p.GetName()
+}
+
+// MatchSearch returns true if the given search values match this policy.
+func (p *HostUserGCPolicyV1) MatchSearch(values []string) bool {
+	fieldVals := append(utils.MapToStrings(p.GetAllLabels()), p.GetName(), p.Spec.Action)
+	return MatchSearch(fieldVals, values, nil)
+}
@@ -126,6 +126,11 @@ type Role interface {
 	// SetDatabaseUsers sets a list of database users this role is allowed or denied access to.
 	SetDatabaseUsers(RoleConditionType, []string)
 
+	// GetDatabaseRoles gets a list of database roles to grant to auto-provisioned database users.
+	GetDatabaseRoles(RoleConditionType) []string
+	// SetDatabaseRoles sets a list of database roles to grant to auto-provisioned database users.
+	SetDatabaseRoles(RoleConditionType, []string)
+
 	// GetImpersonateConditions returns conditions this role is allowed or denied to impersonate.
 	GetImpersonateConditions(rct RoleConditionType) ImpersonateConditions
 	// SetImpersonateConditions returns conditions this role is allowed or denied to impersonate.
@@ -508,6 +513,25 @@ func (r *RoleV5) SetDatabaseUsers(rct RoleConditionType, values []string) {
 	}
 }
 
+// GetDatabaseRoles gets a list of database roles to grant to auto-provisioned
+// database users, for roles this role is allowed or denied access to.
+func (r *RoleV5) GetDatabaseRoles(rct RoleConditionType) []string {
+	if rct == Allow {
+		return r.Spec.Allow.DatabaseRoles
+	}
+	return r.Spec.Deny.DatabaseRoles
+}
+
+// SetDatabaseRoles sets a list of database roles to grant to auto-provisioned
+// database users, for roles this role is allowed or denied access to.
+func (r *RoleV5) SetDatabaseRoles(rct RoleConditionType, values []string) {
+	if rct == Allow {
+		r.Spec.Allow.DatabaseRoles = values
+	} else {
+		r.Spec.Deny.DatabaseRoles = values
+	}
+}
+
 // GetImpersonateConditions returns conditions this role is allowed or denied to impersonate.
 func (r *RoleV5) GetImpersonateConditions(rct RoleConditionType) ImpersonateConditions {
 	cond := r.Spec.Deny.Impersonate
@@ -87,6 +87,13 @@ type Server interface {
 
 	// DeepCopy creates a clone of this server value
 	DeepCopy() Server
+
+	// GetHostUserProvisioning returns this node's host user provisioning
+	// capability, as determined by its startup preflight.
+	GetHostUserProvisioning() HostUserProvisioningCapability
+	// SetHostUserProvisioning sets this node's host user provisioning
+	// capability.
+	SetHostUserProvisioning(HostUserProvisioningCapability)
 }
 
 // NewServer creates an instance of Server.
@@ -211,6 +218,18 @@ func (s *ServerV2) GetUseTunnel() bool {
 	return s.Spec.UseTunnel
 }
 
+// GetHostUserProvisioning returns this node's host user provisioning
+// capability, as determined by its startup preflight.
+func (s *ServerV2) GetHostUserProvisioning() HostUserProvisioningCapability {
+	return s.Spec.HostUserProvisioning
+}
+
+// SetHostUserProvisioning sets this node's host user provisioning
+// capability.
+func (s *ServerV2) SetHostUserProvisioning(c HostUserProvisioningCapability) {
+	s.Spec.HostUserProvisioning = c
+}
+
 // SetUseTunnel sets if a reverse tunnel should be used to connect to this node.
 func (s *ServerV2) SetUseTunnel(useTunnel bool) {
 	s.Spec.UseTunnel = useTunnel
@@ -883,12 +883,28 @@ type DatabaseSpecV3 struct {
 	// Allows to provide custom CA cert or override server name.
 	TLS DatabaseTLS `protobuf:"bytes,8,opt,name=TLS,proto3" json:"tls,omitempty"`
 	// AD is the Active Directory configuration for the database.
-	AD                   AD       `protobuf:"bytes,9,opt,name=AD,proto3" json:"ad,omitempty"`
+	AD AD `protobuf:"bytes,9,opt,name=AD,proto3" json:"ad,omitempty"`
+	// AdminUser is the database admin user for automatic user provisioning.
+	AdminUser            DatabaseAdminUser `protobuf:"bytes,10,opt,name=AdminUser,proto3" json:"admin_user,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+// DatabaseAdminUser contains information about the database admin user
+// used to automatically provision database users for access.
+type DatabaseAdminUser struct {
+	// Name is the database admin username, e.g. postgres or admin.
+	Name                 string   `protobuf:"bytes,1,opt,name=Name,proto3" json:"name,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
+func (m *DatabaseAdminUser) Reset()         { *m = DatabaseAdminUser{} }
+func (m *DatabaseAdminUser) String() string { return proto.CompactTextString(m) }
+func (*DatabaseAdminUser) ProtoMessage()    {}
+
 func (m *DatabaseSpecV3) Reset()         { *m = DatabaseSpecV3{} }
 func (m *DatabaseSpecV3) String() string { return proto.CompactTextString(m) }
 func (*DatabaseSpecV3) ProtoMessage()    {}
@@ -1405,10 +1421,24 @@ type ServerSpecV2 struct {
 	// Important: jsontag must not be "kubernetes_clusters", because a
 	// different field with that jsontag existed in 4.4:
 	// https://github.com/gravitational/teleport/issues/4862
-	KubernetesClusters   []*KubernetesCluster `protobuf:"bytes,10,rep,name=KubernetesClusters,proto3" json:"kube_clusters,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
-	XXX_unrecognized     []byte               `json:"-"`
-	XXX_sizecache        int32                `json:"-"`
+	KubernetesClusters []*KubernetesCluster `protobuf:"bytes,10,rep,name=KubernetesClusters,proto3" json:"kube_clusters,omitempty"`
+	// HostUserProvisioning reports this node's ability to provision local OS
+	// users, determined by a capability preflight run at agent startup.
+	HostUserProvisioning HostUserProvisioningCapability `protobuf:"bytes,11,opt,name=HostUserProvisioning,proto3" json:"host_user_provisioning,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                       `json:"-"`
+	XXX_unrecognized     []byte                         `json:"-"`
+	XXX_sizecache        int32                          `json:"-"`
+}
+
+// HostUserProvisioningCapability reports whether a node is able to create
+// and manage local OS users, as determined by a startup capability
+// preflight.
+type HostUserProvisioningCapability struct {
+	// Capable is true if the node has everything it needs to provision host
+	// users: the required binaries, permissions, and NSS configuration.
+	Capable bool `protobuf:"varint,1,opt,name=Capable,proto3" json:"capable,omitempty"`
+	// Error describes why provisioning is impossible, if Capable is false.
+	Error string `protobuf:"bytes,2,opt,name=Error,proto3" json:"error,omitempty"`
 }
 
 func (m *ServerSpecV2) Reset()         { *m = ServerSpecV2{} }
@@ -4396,10 +4426,15 @@ type RoleOptions struct {
 	// false.
 	DesktopClipboard *BoolOption `protobuf:"bytes,16,opt,name=DesktopClipboard,proto3,customtype=BoolOption" json:"desktop_clipboard"`
 	// CertExtensions specifies the key/values
-	CertExtensions       []*CertExtension `protobuf:"bytes,17,rep,name=CertExtensions,proto3" json:"cert_extensions,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
-	XXX_unrecognized     []byte           `json:"-"`
-	XXX_sizecache        int32            `json:"-"`
+	CertExtensions []*CertExtension `protobuf:"bytes,17,rep,name=CertExtensions,proto3" json:"cert_extensions,omitempty"`
+	// HostGroups specifies additional groups to add to local OS users created
+	// on nodes matching this role. Each entry may contain trait templates,
+	// e.g. "dev-{{external.team}}", interpolated against the user's traits
+	// before the host user is created.
+	HostGroups           []string `protobuf:"bytes,18,rep,name=HostGroups,proto3" json:"host_groups,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *RoleOptions) Reset()         { *m = RoleOptions{} }
@@ -4573,10 +4608,13 @@ type RoleConditions struct {
 	// RequireSessionJoin specifies policies for required users to start a session.
 	RequireSessionJoin []*SessionRequirePolicy `protobuf:"bytes,19,rep,name=RequireSessionJoin,proto3" json:"require_session_join,omitempty"`
 	// JoinSessions specifies policies to allow users to join other sessions.
-	JoinSessions         []*SessionJoinPolicy `protobuf:"bytes,20,rep,name=JoinSessions,proto3" json:"join_sessions,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
-	XXX_unrecognized     []byte               `json:"-"`
-	XXX_sizecache        int32                `json:"-"`
+	JoinSessions []*SessionJoinPolicy `protobuf:"bytes,20,rep,name=JoinSessions,proto3" json:"join_sessions,omitempty"`
+	// DatabaseRoles is a list of the database roles (e.g. Postgres or MySQL
+	// roles/grants) to grant to auto-provisioned database users.
+	DatabaseRoles        []string `protobuf:"bytes,21,rep,name=DatabaseRoles,proto3" json:"db_roles,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *RoleConditions) Reset()         { *m = RoleConditions{} }
@@ -230,6 +230,35 @@ func DatabaseCertPath(baseDir, proxy, username, cluster, dbname string) string {
 	return filepath.Join(DatabaseCertDir(baseDir, proxy, username, cluster), dbname+fileExtTLSCert)
 }
 
+// DatabaseKeyPath returns the path to the user's private key issued
+// specifically for the given database route, for the given proxy and
+// cluster.
+//
+// <baseDir>/keys/<proxy>/<username>-db/<cluster>/<dbname>
+func DatabaseKeyPath(baseDir, proxy, username, cluster, dbname string) string {
+	return filepath.Join(DatabaseCertDir(baseDir, proxy, username, cluster), dbname)
+}
+
+// DatabaseCertChainPath returns the path to the user's TLS certificate
+// chain (leaf certificate followed by any intermediate issuing CAs) for
+// the given proxy, cluster, and database.
+//
+// <baseDir>/keys/<proxy>/<username>-db/<cluster>/<dbname>-chain-x509.pem
+func DatabaseCertChainPath(baseDir, proxy, username, cluster, dbname string) string {
+	return filepath.Join(DatabaseCertDir(baseDir, proxy, username, cluster), dbname+"-chain"+fileExtTLSCert)
+}
+
+// DatabaseCredentialFilePath returns the path to a generated native-client
+// credential file (e.g. a .pgpass or .my.cnf login-path entry) holding the
+// password for the given database route's password-auth login, for the
+// given proxy and cluster. suffix distinguishes the file format, e.g.
+// "pgpass" or "my.cnf".
+//
+// <baseDir>/keys/<proxy>/<username>-db/<cluster>/<dbname>-<suffix>
+func DatabaseCredentialFilePath(baseDir, proxy, username, cluster, dbname, suffix string) string {
+	return filepath.Join(DatabaseCertDir(baseDir, proxy, username, cluster), dbname+"-"+suffix)
+}
+
 // KubeDir returns the path to the user's kube directory
 // for the given proxy.
 //
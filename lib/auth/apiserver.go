@@ -103,6 +103,21 @@ func NewAPIServer(config *APIConfig) (http.Handler, error) {
 	srv.GET("/:version/authorities/:type/:domain", srv.withAuth(srv.getCertAuthority))
 	srv.GET("/:version/authorities/:type", srv.withAuth(srv.getCertAuthorities))
 
+	// Operations on static host users
+	srv.POST("/:version/static-host-users", srv.withAuth(srv.upsertStaticHostUser))
+	srv.GET("/:version/static-host-users", srv.withAuth(srv.getStaticHostUsers))
+	srv.GET("/:version/static-host-users/:name", srv.withAuth(srv.getStaticHostUser))
+	srv.DELETE("/:version/static-host-users", srv.withAuth(srv.deleteAllStaticHostUsers))
+	srv.DELETE("/:version/static-host-users/:name", srv.withAuth(srv.deleteStaticHostUser))
+
+	// Operations on host user records reported by nodes
+	srv.POST("/:version/host-user-records", srv.withAuth(srv.upsertHostUserRecord))
+	srv.GET("/:version/host-user-records", srv.withAuth(srv.getHostUserRecords))
+	srv.DELETE("/:version/host-user-records/:host_id", srv.withAuth(srv.deleteAllHostUserRecordsForHost))
+	srv.DELETE("/:version/host-user-records/:host_id/:login", srv.withAuth(srv.deleteHostUserRecord))
+	srv.GET("/:version/host-user-gc-policy", srv.withAuth(srv.getHostUserGCPolicy))
+	srv.POST("/:version/host-user-gc-policy", srv.withAuth(srv.setHostUserGCPolicy))
+
 	// Generating certificates for user and host authorities
 	srv.POST("/:version/ca/host/certs", srv.withAuth(srv.generateHostCert))
 	srv.POST("/:version/ca/user/certs", srv.withAuth(srv.generateUserCert)) // DELETE IN: 4.2.0
@@ -1064,6 +1079,139 @@ func (s *APIServer) getCertAuthorities(auth ClientI, w http.ResponseWriter, r *h
 	return items, nil
 }
 
+type upsertStaticHostUserRawReq struct {
+	HostUser json.RawMessage `json:"host_user"`
+}
+
+func (s *APIServer) upsertStaticHostUser(auth ClientI, w http.ResponseWriter, r *http.Request, p httprouter.Params, version string) (interface{}, error) {
+	var req *upsertStaticHostUserRawReq
+	if err := httplib.ReadJSON(r, &req); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	user, err := services.UnmarshalStaticHostUser(req.HostUser)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if err := auth.UpsertStaticHostUser(r.Context(), user); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return message("ok"), nil
+}
+
+func (s *APIServer) getStaticHostUsers(auth ClientI, w http.ResponseWriter, r *http.Request, p httprouter.Params, version string) (interface{}, error) {
+	users, err := auth.GetStaticHostUsers(r.Context())
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	items := make([]json.RawMessage, len(users))
+	for i, user := range users {
+		data, err := services.MarshalStaticHostUser(user, services.WithVersion(version), services.PreserveResourceID())
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		items[i] = data
+	}
+	return items, nil
+}
+
+func (s *APIServer) getStaticHostUser(auth ClientI, w http.ResponseWriter, r *http.Request, p httprouter.Params, version string) (interface{}, error) {
+	user, err := auth.GetStaticHostUser(r.Context(), p.ByName("name"))
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return rawMessage(services.MarshalStaticHostUser(user, services.WithVersion(version), services.PreserveResourceID()))
+}
+
+func (s *APIServer) deleteStaticHostUser(auth ClientI, w http.ResponseWriter, r *http.Request, p httprouter.Params, version string) (interface{}, error) {
+	if err := auth.DeleteStaticHostUser(r.Context(), p.ByName("name")); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return message("ok"), nil
+}
+
+func (s *APIServer) deleteAllStaticHostUsers(auth ClientI, w http.ResponseWriter, r *http.Request, p httprouter.Params, version string) (interface{}, error) {
+	if err := auth.DeleteAllStaticHostUsers(r.Context()); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return message("ok"), nil
+}
+
+type upsertHostUserRecordRawReq struct {
+	Record json.RawMessage `json:"record"`
+}
+
+func (s *APIServer) upsertHostUserRecord(auth ClientI, w http.ResponseWriter, r *http.Request, p httprouter.Params, version string) (interface{}, error) {
+	var req *upsertHostUserRecordRawReq
+	if err := httplib.ReadJSON(r, &req); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	record, err := services.UnmarshalHostUserRecord(req.Record)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if err := auth.UpsertHostUserRecord(r.Context(), record); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return message("ok"), nil
+}
+
+func (s *APIServer) getHostUserRecords(auth ClientI, w http.ResponseWriter, r *http.Request, p httprouter.Params, version string) (interface{}, error) {
+	records, err := auth.GetHostUserRecords(r.Context(), r.URL.Query().Get("host_id"))
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	items := make([]json.RawMessage, len(records))
+	for i, record := range records {
+		data, err := services.MarshalHostUserRecord(record, services.WithVersion(version), services.PreserveResourceID())
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		items[i] = data
+	}
+	return items, nil
+}
+
+func (s *APIServer) deleteHostUserRecord(auth ClientI, w http.ResponseWriter, r *http.Request, p httprouter.Params, version string) (interface{}, error) {
+	if err := auth.DeleteHostUserRecord(r.Context(), p.ByName("host_id"), p.ByName("login")); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return message("ok"), nil
+}
+
+func (s *APIServer) deleteAllHostUserRecordsForHost(auth ClientI, w http.ResponseWriter, r *http.Request, p httprouter.Params, version string) (interface{}, error) {
+	if err := auth.DeleteAllHostUserRecordsForHost(r.Context(), p.ByName("host_id")); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return message("ok"), nil
+}
+
+func (s *APIServer) getHostUserGCPolicy(auth ClientI, w http.ResponseWriter, r *http.Request, p httprouter.Params, version string) (interface{}, error) {
+	policy, err := auth.GetHostUserGCPolicy(r.Context())
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return rawMessage(services.MarshalHostUserGCPolicy(policy, services.WithVersion(version), services.PreserveResourceID()))
+}
+
+type setHostUserGCPolicyRawReq struct {
+	Policy json.RawMessage `json:"policy"`
+}
+
+func (s *APIServer) setHostUserGCPolicy(auth ClientI, w http.ResponseWriter, r *http.Request, p httprouter.Params, version string) (interface{}, error) {
+	var req *setHostUserGCPolicyRawReq
+	if err := httplib.ReadJSON(r, &req); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	policy, err := services.UnmarshalHostUserGCPolicy(req.Policy)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if err := auth.SetHostUserGCPolicy(r.Context(), policy); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return message("ok"), nil
+}
+
 func (s *APIServer) getCertAuthority(auth ClientI, w http.ResponseWriter, r *http.Request, p httprouter.Params, version string) (interface{}, error) {
 	loadKeys, _, err := httplib.ParseBool(r.URL.Query(), "load_keys")
 	if err != nil {
@@ -128,6 +128,15 @@ func NewServer(cfg *InitConfig, opts ...ServerOption) (*Server, error) {
 	if cfg.Databases == nil {
 		cfg.Databases = local.NewDatabasesService(cfg.Backend)
 	}
+	if cfg.StaticHostUsers == nil {
+		cfg.StaticHostUsers = local.NewStaticHostUserService(cfg.Backend)
+	}
+	if cfg.HostUserRecords == nil {
+		cfg.HostUserRecords = local.NewHostUserRecordService(cfg.Backend)
+	}
+	if cfg.HostUserGCPolicies == nil {
+		cfg.HostUserGCPolicies = local.NewHostUserGCPolicyService(cfg.Backend)
+	}
 	if cfg.Events == nil {
 		cfg.Events = local.NewEventsService(cfg.Backend)
 	}
@@ -193,6 +202,9 @@ func NewServer(cfg *InitConfig, opts ...ServerOption) (*Server, error) {
 			Restrictions:          cfg.Restrictions,
 			Apps:                  cfg.Apps,
 			Databases:             cfg.Databases,
+			StaticHostUsers:       cfg.StaticHostUsers,
+			HostUserRecords:       cfg.HostUserRecords,
+			HostUserGCPolicies:    cfg.HostUserGCPolicies,
 			IAuditLog:             cfg.AuditLog,
 			Events:                cfg.Events,
 			WindowsDesktops:       cfg.WindowsDesktops,
@@ -221,6 +233,9 @@ type Services struct {
 	services.Restrictions
 	services.Apps
 	services.Databases
+	services.StaticHostUsers
+	services.HostUserRecords
+	services.HostUserGCPolicies
 	services.WindowsDesktops
 	services.SessionTrackerService
 	types.Events
@@ -3667,6 +3667,122 @@ func (a *ServerWithRoles) DeleteNetworkRestrictions(ctx context.Context) error {
 	return a.authServer.DeleteNetworkRestrictions(ctx)
 }
 
+// GetStaticHostUser returns a static host user by name.
+func (a *ServerWithRoles) GetStaticHostUser(ctx context.Context, name string) (types.StaticHostUser, error) {
+	if err := a.action(apidefaults.Namespace, types.KindStaticHostUser, types.VerbRead); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return a.authServer.GetStaticHostUser(ctx, name)
+}
+
+// GetStaticHostUsers returns all static host users.
+func (a *ServerWithRoles) GetStaticHostUsers(ctx context.Context) ([]types.StaticHostUser, error) {
+	if err := a.action(apidefaults.Namespace, types.KindStaticHostUser, types.VerbRead, types.VerbList); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return a.authServer.GetStaticHostUsers(ctx)
+}
+
+// CreateStaticHostUser creates a new static host user.
+func (a *ServerWithRoles) CreateStaticHostUser(ctx context.Context, user types.StaticHostUser) error {
+	if err := a.action(apidefaults.Namespace, types.KindStaticHostUser, types.VerbCreate); err != nil {
+		return trace.Wrap(err)
+	}
+	return a.authServer.CreateStaticHostUser(ctx, user)
+}
+
+// UpdateStaticHostUser updates an existing static host user.
+func (a *ServerWithRoles) UpdateStaticHostUser(ctx context.Context, user types.StaticHostUser) error {
+	if err := a.action(apidefaults.Namespace, types.KindStaticHostUser, types.VerbUpdate); err != nil {
+		return trace.Wrap(err)
+	}
+	return a.authServer.UpdateStaticHostUser(ctx, user)
+}
+
+// UpsertStaticHostUser creates or updates a static host user.
+func (a *ServerWithRoles) UpsertStaticHostUser(ctx context.Context, user types.StaticHostUser) error {
+	if err := a.action(apidefaults.Namespace, types.KindStaticHostUser, types.VerbCreate, types.VerbUpdate); err != nil {
+		return trace.Wrap(err)
+	}
+	return a.authServer.UpsertStaticHostUser(ctx, user)
+}
+
+// DeleteStaticHostUser removes the named static host user.
+func (a *ServerWithRoles) DeleteStaticHostUser(ctx context.Context, name string) error {
+	if err := a.action(apidefaults.Namespace, types.KindStaticHostUser, types.VerbDelete); err != nil {
+		return trace.Wrap(err)
+	}
+	return a.authServer.DeleteStaticHostUser(ctx, name)
+}
+
+// DeleteAllStaticHostUsers removes all static host users.
+func (a *ServerWithRoles) DeleteAllStaticHostUsers(ctx context.Context) error {
+	if err := a.action(apidefaults.Namespace, types.KindStaticHostUser, types.VerbDelete); err != nil {
+		return trace.Wrap(err)
+	}
+	return a.authServer.DeleteAllStaticHostUsers(ctx)
+}
+
+// GetHostUserRecords returns host user records reported by hostID, or by
+// every host if hostID is empty.
+func (a *ServerWithRoles) GetHostUserRecords(ctx context.Context, hostID string) ([]types.HostUserRecord, error) {
+	if err := a.action(apidefaults.Namespace, types.KindHostUserRecord, types.VerbRead, types.VerbList); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return a.authServer.GetHostUserRecords(ctx, hostID)
+}
+
+// UpsertHostUserRecord creates or updates a host user record. Callers holding
+// the builtin Node role (nodes reporting the host users they have created)
+// are always allowed; any other caller needs create and update access to
+// host user records.
+func (a *ServerWithRoles) UpsertHostUserRecord(ctx context.Context, record types.HostUserRecord) error {
+	if !a.hasBuiltinRole(string(types.RoleNode)) {
+		if err := a.action(apidefaults.Namespace, types.KindHostUserRecord, types.VerbCreate, types.VerbUpdate); err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	return a.authServer.UpsertHostUserRecord(ctx, record)
+}
+
+// DeleteHostUserRecord removes a single host user record.
+func (a *ServerWithRoles) DeleteHostUserRecord(ctx context.Context, hostID, login string) error {
+	if err := a.action(apidefaults.Namespace, types.KindHostUserRecord, types.VerbDelete); err != nil {
+		return trace.Wrap(err)
+	}
+	return a.authServer.DeleteHostUserRecord(ctx, hostID, login)
+}
+
+// DeleteAllHostUserRecordsForHost removes all host user records reported by
+// hostID.
+func (a *ServerWithRoles) DeleteAllHostUserRecordsForHost(ctx context.Context, hostID string) error {
+	if err := a.action(apidefaults.Namespace, types.KindHostUserRecord, types.VerbDelete); err != nil {
+		return trace.Wrap(err)
+	}
+	return a.authServer.DeleteAllHostUserRecordsForHost(ctx, hostID)
+}
+
+// GetHostUserGCPolicy returns the cluster's host user garbage collection
+// policy. Callers holding the builtin Node role may always read it, since
+// nodes need it to run ApplyHostUserGC regardless of their assigned roles.
+func (a *ServerWithRoles) GetHostUserGCPolicy(ctx context.Context) (types.HostUserGCPolicy, error) {
+	if !a.hasBuiltinRole(string(types.RoleNode)) {
+		if err := a.action(apidefaults.Namespace, types.KindHostUserGCPolicy, types.VerbRead); err != nil {
+			return nil, trace.Wrap(err)
+		}
+	}
+	return a.authServer.GetHostUserGCPolicy(ctx)
+}
+
+// SetHostUserGCPolicy sets the cluster's host user garbage collection
+// policy.
+func (a *ServerWithRoles) SetHostUserGCPolicy(ctx context.Context, policy types.HostUserGCPolicy) error {
+	if err := a.action(apidefaults.Namespace, types.KindHostUserGCPolicy, types.VerbCreate, types.VerbUpdate); err != nil {
+		return trace.Wrap(err)
+	}
+	return a.authServer.SetHostUserGCPolicy(ctx, policy)
+}
+
 // GetMFADevices returns a list of MFA devices.
 func (a *ServerWithRoles) GetMFADevices(ctx context.Context, req *proto.GetMFADevicesRequest) (*proto.GetMFADevicesResponse, error) {
 	return a.authServer.GetMFADevices(ctx, req)
@@ -521,6 +521,141 @@ func (c *Client) DeactivateCertAuthority(id types.CertAuthID) error {
 	return trace.NotImplemented(notImplementedMessage)
 }
 
+// GetStaticHostUser returns a static host user by name.
+func (c *Client) GetStaticHostUser(ctx context.Context, name string) (types.StaticHostUser, error) {
+	out, err := c.Get(ctx, c.Endpoint("static-host-users", name), url.Values{})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return services.UnmarshalStaticHostUser(out.Bytes())
+}
+
+// GetStaticHostUsers returns all static host users.
+func (c *Client) GetStaticHostUsers(ctx context.Context) ([]types.StaticHostUser, error) {
+	out, err := c.Get(ctx, c.Endpoint("static-host-users"), url.Values{})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var items []json.RawMessage
+	if err := json.Unmarshal(out.Bytes(), &items); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	users := make([]types.StaticHostUser, len(items))
+	for i, raw := range items {
+		user, err := services.UnmarshalStaticHostUser(raw)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		users[i] = user
+	}
+	return users, nil
+}
+
+// CreateStaticHostUser creates a new static host user.
+func (c *Client) CreateStaticHostUser(ctx context.Context, user types.StaticHostUser) error {
+	data, err := services.MarshalStaticHostUser(user)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	_, err = c.PostJSON(ctx, c.Endpoint("static-host-users"), &upsertStaticHostUserRawReq{HostUser: data})
+	return trace.Wrap(err)
+}
+
+// UpdateStaticHostUser updates an existing static host user.
+func (c *Client) UpdateStaticHostUser(ctx context.Context, user types.StaticHostUser) error {
+	return c.UpsertStaticHostUser(ctx, user)
+}
+
+// UpsertStaticHostUser creates or updates a static host user.
+func (c *Client) UpsertStaticHostUser(ctx context.Context, user types.StaticHostUser) error {
+	data, err := services.MarshalStaticHostUser(user)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	_, err = c.PostJSON(ctx, c.Endpoint("static-host-users"), &upsertStaticHostUserRawReq{HostUser: data})
+	return trace.Wrap(err)
+}
+
+// DeleteStaticHostUser removes the named static host user.
+func (c *Client) DeleteStaticHostUser(ctx context.Context, name string) error {
+	_, err := c.Delete(ctx, c.Endpoint("static-host-users", name))
+	return trace.Wrap(err)
+}
+
+// DeleteAllStaticHostUsers removes all static host users.
+func (c *Client) DeleteAllStaticHostUsers(ctx context.Context) error {
+	_, err := c.Delete(ctx, c.Endpoint("static-host-users"))
+	return trace.Wrap(err)
+}
+
+// GetHostUserRecords returns host user records reported by hostID, or by
+// every host if hostID is empty.
+func (c *Client) GetHostUserRecords(ctx context.Context, hostID string) ([]types.HostUserRecord, error) {
+	out, err := c.Get(ctx, c.Endpoint("host-user-records"), url.Values{
+		"host_id": []string{hostID},
+	})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var items []json.RawMessage
+	if err := json.Unmarshal(out.Bytes(), &items); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	records := make([]types.HostUserRecord, len(items))
+	for i, raw := range items {
+		record, err := services.UnmarshalHostUserRecord(raw)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// UpsertHostUserRecord creates or updates a host user record.
+func (c *Client) UpsertHostUserRecord(ctx context.Context, record types.HostUserRecord) error {
+	data, err := services.MarshalHostUserRecord(record)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	_, err = c.PostJSON(ctx, c.Endpoint("host-user-records"), &upsertHostUserRecordRawReq{Record: data})
+	return trace.Wrap(err)
+}
+
+// DeleteHostUserRecord removes a single host user record.
+func (c *Client) DeleteHostUserRecord(ctx context.Context, hostID, login string) error {
+	_, err := c.Delete(ctx, c.Endpoint("host-user-records", hostID, login))
+	return trace.Wrap(err)
+}
+
+// DeleteAllHostUserRecordsForHost removes all host user records reported by
+// hostID.
+func (c *Client) DeleteAllHostUserRecordsForHost(ctx context.Context, hostID string) error {
+	_, err := c.Delete(ctx, c.Endpoint("host-user-records", hostID))
+	return trace.Wrap(err)
+}
+
+// GetHostUserGCPolicy returns the cluster's host user garbage collection
+// policy.
+func (c *Client) GetHostUserGCPolicy(ctx context.Context) (types.HostUserGCPolicy, error) {
+	out, err := c.Get(ctx, c.Endpoint("host-user-gc-policy"), url.Values{})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return services.UnmarshalHostUserGCPolicy(out.Bytes())
+}
+
+// SetHostUserGCPolicy sets the cluster's host user garbage collection
+// policy.
+func (c *Client) SetHostUserGCPolicy(ctx context.Context, policy types.HostUserGCPolicy) error {
+	data, err := services.MarshalHostUserGCPolicy(policy)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	_, err = c.PostJSON(ctx, c.Endpoint("host-user-gc-policy"), &setHostUserGCPolicyRawReq{Policy: data})
+	return trace.Wrap(err)
+}
+
 // GenerateToken creates a special provisioning token for a new SSH server
 // that is valid for ttl period seconds.
 //
@@ -1936,6 +2071,9 @@ type ClientI interface {
 	services.Restrictions
 	services.Apps
 	services.Databases
+	services.StaticHostUsers
+	services.HostUserRecords
+	services.HostUserGCPolicies
 	services.WindowsDesktops
 	WebService
 	session.Service
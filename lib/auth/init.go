@@ -128,6 +128,17 @@ type InitConfig struct {
 	// Databases is a service that manages database resources.
 	Databases services.Databases
 
+	// StaticHostUsers is a service that manages static host user resources.
+	StaticHostUsers services.StaticHostUsers
+
+	// HostUserRecords is a service that manages host user records reported
+	// by nodes.
+	HostUserRecords services.HostUserRecords
+
+	// HostUserGCPolicies is a service that manages the cluster-level
+	// HostUserGCPolicy singleton.
+	HostUserGCPolicies services.HostUserGCPolicies
+
 	// Roles is a set of roles to create
 	Roles []types.Role
 
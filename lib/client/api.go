@@ -455,6 +455,14 @@ type ProfileStatus struct {
 
 	// AWSRoleARNs is a list of allowed AWS role ARNs user can assume.
 	AWSRolesARNs []string
+
+	// DatabaseUsers is a list of database users this profile is allowed to
+	// connect as, as enforced by the auth server at login time.
+	DatabaseUsers []string
+
+	// DatabaseNames is a list of database names this profile is allowed to
+	// connect to, as enforced by the auth server at login time.
+	DatabaseNames []string
 }
 
 // IsExpired returns true if profile is not expired yet
@@ -490,6 +498,63 @@ func (p *ProfileStatus) DatabaseCertPathForCluster(clusterName string, databaseN
 	return keypaths.DatabaseCertPath(p.Dir, p.Name, p.Username, clusterName, databaseName)
 }
 
+// DatabaseKeyPathForCluster returns path to the private key issued for the
+// specified database route, for the specified cluster.
+//
+// It's kept in <profile-dir>/keys/<proxy>/<user>-db/<cluster>/<name>
+//
+// Database routes that were never issued a dedicated keypair don't have a
+// file at this path; callers should fall back to KeyPath() in that case.
+//
+// If the input cluster name is an empty string, the selected cluster in the
+// profile will be used.
+func (p *ProfileStatus) DatabaseKeyPathForCluster(clusterName string, databaseName string) string {
+	if clusterName == "" {
+		clusterName = p.Cluster
+	}
+	return keypaths.DatabaseKeyPath(p.Dir, p.Name, p.Username, clusterName, databaseName)
+}
+
+// DatabaseCertChainPathForCluster returns the path to a combined
+// certificate chain (the database access leaf certificate followed by any
+// intermediate issuing CAs) for this profile, for the specified cluster.
+//
+// It's kept in <profile-dir>/keys/<proxy>/<user>-db/<cluster>/<name>-chain-x509.pem
+//
+// If the input cluster name is an empty string, the selected cluster in the
+// profile will be used.
+func (p *ProfileStatus) DatabaseCertChainPathForCluster(clusterName string, databaseName string) string {
+	if clusterName == "" {
+		clusterName = p.Cluster
+	}
+	return keypaths.DatabaseCertChainPath(p.Dir, p.Name, p.Username, clusterName, databaseName)
+}
+
+// DatabaseCredentialFilePathForCluster returns the path to a generated
+// native-client credential file (e.g. a .pgpass or .my.cnf login-path entry)
+// for the specified database route's password-auth login, for the specified
+// cluster. suffix distinguishes the file format, e.g. "pgpass" or "my.cnf".
+//
+// It's kept in <profile-dir>/keys/<proxy>/<user>-db/<cluster>/<name>-<suffix>
+//
+// If the input cluster name is an empty string, the selected cluster in the
+// profile will be used.
+func (p *ProfileStatus) DatabaseCredentialFilePathForCluster(clusterName, databaseName, suffix string) string {
+	if clusterName == "" {
+		clusterName = p.Cluster
+	}
+	return keypaths.DatabaseCredentialFilePath(p.Dir, p.Name, p.Username, clusterName, databaseName, suffix)
+}
+
+// GeneratedDatabaseFilesPath returns the path to the manifest of helper
+// files (e.g. certificate chains) dbcmd has written to the profile
+// directory on behalf of database connections, for this profile.
+//
+// It's kept in <profile-dir>/keys/<proxy>/<user>-db/generated_files.json
+func (p *ProfileStatus) GeneratedDatabaseFilesPath() string {
+	return filepath.Join(keypaths.DatabaseDir(p.Dir, p.Name, p.Username), "generated_files.json")
+}
+
 // AppCertPath returns path to the specified app access certificate
 // for this profile.
 //
@@ -727,6 +792,8 @@ func ReadProfileStatus(profileDir string, profileName string) (*ProfileStatus, e
 		Databases:      databases,
 		Apps:           apps,
 		AWSRolesARNs:   tlsID.AWSRoleARNs,
+		DatabaseUsers:  tlsID.DatabaseUsers,
+		DatabaseNames:  tlsID.DatabaseNames,
 	}, nil
 }
 
@@ -983,7 +1050,8 @@ func ParseProxyHost(proxyHost string) (*ParsedProxyHost, error) {
 // ParseProxyHost parses the proxyHost string and updates the config.
 //
 // Format of proxyHost string:
-//   proxy_web_addr:<proxy_web_port>,<proxy_ssh_port>
+//
+//	proxy_web_addr:<proxy_web_port>,<proxy_ssh_port>
 func (c *Config) ParseProxyHost(proxyHost string) error {
 	parsedAddrs, err := ParseProxyHost(proxyHost)
 	if err != nil {
@@ -2313,11 +2381,11 @@ func (tc *TeleportClient) connectToProxy(ctx context.Context) (*ProxyClient, err
 }
 
 // makeProxySSHClient creates an SSH client by following steps:
-// 1) If the current proxy supports TLS Routing and JumpHost address was not provided use TLSWrapper.
-// 2) Check JumpHost raw SSH port or Teleport proxy address.
-//    In case of proxy web address check if the proxy supports TLS Routing and connect to the proxy with TLSWrapper
-// 3) Dial sshProxyAddr with raw SSH Dialer where sshProxyAddress is proxy ssh address or JumpHost address if
-//    JumpHost address was provided.
+//  1. If the current proxy supports TLS Routing and JumpHost address was not provided use TLSWrapper.
+//  2. Check JumpHost raw SSH port or Teleport proxy address.
+//     In case of proxy web address check if the proxy supports TLS Routing and connect to the proxy with TLSWrapper
+//  3. Dial sshProxyAddr with raw SSH Dialer where sshProxyAddress is proxy ssh address or JumpHost address if
+//     JumpHost address was provided.
 func makeProxySSHClient(ctx context.Context, tc *TeleportClient, sshConfig *ssh.ClientConfig) (*ssh.Client, error) {
 	// Use TLS Routing dialer only if proxy support TLS Routing and JumpHost was not set.
 	if tc.Config.TLSRoutingEnabled && len(tc.JumpHosts) == 0 {
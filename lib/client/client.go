@@ -39,6 +39,7 @@ import (
 	apidefaults "github.com/gravitational/teleport/api/defaults"
 	"github.com/gravitational/teleport/api/types"
 	"github.com/gravitational/teleport/lib/auth"
+	"github.com/gravitational/teleport/lib/auth/native"
 	"github.com/gravitational/teleport/lib/defaults"
 	"github.com/gravitational/teleport/lib/events"
 	"github.com/gravitational/teleport/lib/sshutils"
@@ -268,7 +269,20 @@ func (proxy *ProxyClient) reissueUserCerts(ctx context.Context, cachePolicy Cert
 		}
 	}
 
-	req, err := proxy.prepareUserCertsRequest(params, key)
+	// Database routes get their own keypair instead of reusing the
+	// profile's private key, so that compromising one database's key
+	// material doesn't expose every other database and the SSH identity
+	// tied to the profile key.
+	var dbPriv, dbPub []byte
+	if params.usage() == proto.UserCertsRequest_Database {
+		var err error
+		dbPriv, dbPub, err = native.GenerateKeyPair("")
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+	}
+
+	req, err := proxy.prepareUserCertsRequest(params, key, dbPub)
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
@@ -309,8 +323,16 @@ func (proxy *ProxyClient) reissueUserCerts(ctx context.Context, cachePolicy Cert
 	case proto.UserCertsRequest_App:
 		key.AppTLSCerts[params.RouteToApp.Name] = certs.TLS
 	case proto.UserCertsRequest_Database:
+		dbKeyPriv := key.Priv
+		if dbPriv != nil {
+			dbKeyPriv = dbPriv
+			if key.DBTLSKeys == nil {
+				key.DBTLSKeys = make(map[string][]byte)
+			}
+			key.DBTLSKeys[params.RouteToDatabase.ServiceName] = dbPriv
+		}
 		key.DBTLSCerts[params.RouteToDatabase.ServiceName] = makeDatabaseClientPEM(
-			params.RouteToDatabase.Protocol, certs.TLS, key.Priv)
+			params.RouteToDatabase.Protocol, certs.TLS, dbKeyPriv)
 	case proto.UserCertsRequest_Kubernetes:
 		key.KubeTLSCerts[params.KubernetesCluster] = certs.TLS
 	case proto.UserCertsRequest_WindowsDesktop:
@@ -415,7 +437,15 @@ func (proxy *ProxyClient) IssueUserCertsWithMFA(ctx context.Context, params Reis
 	}
 	defer stream.CloseSend()
 
-	initReq, err := proxy.prepareUserCertsRequest(params, key)
+	var dbPriv, dbPub []byte
+	if params.usage() == proto.UserCertsRequest_Database {
+		dbPriv, dbPub, err = native.GenerateKeyPair("")
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+	}
+
+	initReq, err := proxy.prepareUserCertsRequest(params, key, dbPub)
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
@@ -459,8 +489,16 @@ func (proxy *ProxyClient) IssueUserCertsWithMFA(ctx context.Context, params Reis
 		case proto.UserCertsRequest_Kubernetes:
 			key.KubeTLSCerts[initReq.KubernetesCluster] = crt.TLS
 		case proto.UserCertsRequest_Database:
+			dbKeyPriv := key.Priv
+			if dbPriv != nil {
+				dbKeyPriv = dbPriv
+				if key.DBTLSKeys == nil {
+					key.DBTLSKeys = make(map[string][]byte)
+				}
+				key.DBTLSKeys[params.RouteToDatabase.ServiceName] = dbPriv
+			}
 			key.DBTLSCerts[params.RouteToDatabase.ServiceName] = makeDatabaseClientPEM(
-				params.RouteToDatabase.Protocol, crt.TLS, key.Priv)
+				params.RouteToDatabase.Protocol, crt.TLS, dbKeyPriv)
 		case proto.UserCertsRequest_WindowsDesktop:
 			key.WindowsDesktopCerts[params.RouteToWindowsDesktop.WindowsDesktop] = crt.TLS
 		default:
@@ -474,7 +512,7 @@ func (proxy *ProxyClient) IssueUserCertsWithMFA(ctx context.Context, params Reis
 	return key, nil
 }
 
-func (proxy *ProxyClient) prepareUserCertsRequest(params ReissueParams, key *Key) (*proto.UserCertsRequest, error) {
+func (proxy *ProxyClient) prepareUserCertsRequest(params ReissueParams, key *Key, publicKey []byte) (*proto.UserCertsRequest, error) {
 	tlsCert, err := key.TeleportTLSCertificate()
 	if err != nil {
 		return nil, trace.Wrap(err)
@@ -492,8 +530,12 @@ func (proxy *ProxyClient) prepareUserCertsRequest(params ReissueParams, key *Key
 		params.AccessRequests = activeRequests.AccessRequests
 	}
 
+	if len(publicKey) == 0 {
+		publicKey = key.Pub
+	}
+
 	return &proto.UserCertsRequest{
-		PublicKey:             key.Pub,
+		PublicKey:             publicKey,
 		Username:              tlsCert.Subject.CommonName,
 		Expires:               tlsCert.NotAfter,
 		RouteToCluster:        params.RouteToCluster,
@@ -0,0 +1,119 @@
+/*
+
+ Copyright 2023 Gravitational, Inc.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+
+*/
+
+package dbcmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gravitational/trace"
+)
+
+const activateScriptName = "activate"
+
+// WriteActivationScripts writes a virtualenv-style "activate" script to
+// dir, along with a PATH shim for this database's CLI client under
+// dir/bin, creating both as needed. Once written, a user can
+// "source <dir>/activate" to export this database's connection
+// environment (the same one GetConnectCommandEnv sets) and put the shim
+// ahead of the real client on PATH, so the bare client command (psql,
+// mysql, redis-cli, ...) connects without repeating any flags; sourcing
+// the script's "deactivate" function afterwards restores the prior PATH
+// and unsets whatever activate exported.
+//
+// Uses the same protocol support as GetConnectCommandEnv: Postgres, MySQL,
+// and Redis. Other protocols return trace.BadParameter.
+func (c *CLICommandBuilder) WriteActivationScripts(dir string) error {
+	binary, args, env, err := c.getConnectCommandEnv()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	binDir := filepath.Join(dir, "bin")
+	if err := os.MkdirAll(binDir, 0700); err != nil {
+		return trace.ConvertSystemError(err)
+	}
+
+	shimName := filepath.Base(binary)
+	shimPath := filepath.Join(binDir, shimName)
+	if err := os.WriteFile(shimPath, []byte(shimScript(binary, args)), 0700); err != nil {
+		return trace.ConvertSystemError(err)
+	}
+
+	activatePath := filepath.Join(dir, activateScriptName)
+	if err := os.WriteFile(activatePath, []byte(activateScript(binDir, env)), 0600); err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	return nil
+}
+
+// shimScript returns a shell script that execs binary with args, forwarding
+// any additional arguments the user passes to the shim itself. It's what
+// turns a bare client invocation like "psql" into the fully-flagged
+// Teleport connect command.
+func shimScript(binary string, args []string) string {
+	var b strings.Builder
+	fmt.Fprintln(&b, "#!/bin/sh")
+	fmt.Fprintf(&b, "exec %s", shellQuote(binary))
+	for _, arg := range args {
+		fmt.Fprintf(&b, " %s", shellQuote(arg))
+	}
+	fmt.Fprintln(&b, ` "$@"`)
+	return b.String()
+}
+
+// activateScript returns the contents of a virtualenv-style "activate"
+// script: it saves and prepends binDir to PATH, exports env, and defines a
+// "deactivate" function that undoes both.
+func activateScript(binDir string, env map[string]string) string {
+	var b strings.Builder
+	fmt.Fprintln(&b, "# Generated by tsh db activate. This file must be sourced, not executed:")
+	fmt.Fprintf(&b, "#   source %s\n\n", filepath.Join(filepath.Dir(binDir), activateScriptName))
+	fmt.Fprintln(&b, `_TELEPORT_DB_OLD_PATH="$PATH"`)
+	fmt.Fprintf(&b, "export PATH=%s:\"$PATH\"\n", shellQuote(binDir))
+
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(&b, "export %s=%s\n", key, shellQuote(env[key]))
+	}
+
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "deactivate() {")
+	fmt.Fprintln(&b, `  export PATH="$_TELEPORT_DB_OLD_PATH"`)
+	fmt.Fprintln(&b, "  unset _TELEPORT_DB_OLD_PATH")
+	for _, key := range keys {
+		fmt.Fprintf(&b, "  unset %s\n", key)
+	}
+	fmt.Fprintln(&b, "  unset -f deactivate")
+	fmt.Fprintln(&b, "}")
+	return b.String()
+}
+
+// shellQuote wraps s in single quotes for safe use in a POSIX shell script,
+// escaping any single quotes it contains.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,102 @@
+/*
+
+ Copyright 2023 Gravitational, Inc.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+
+*/
+
+package dbcmd
+
+import "github.com/gravitational/teleport/lib/defaults"
+
+// TLSMode describes how a database connection validates the server's
+// certificate.
+type TLSMode string
+
+const (
+	// TLSModeDisabled means the connection isn't using TLS at all (e.g.
+	// WithNoTLS, for clients that terminate TLS at a local proxy already
+	// speaking plaintext to the client).
+	TLSModeDisabled TLSMode = "disabled"
+	// TLSModeInsecure means TLS is used but the server certificate isn't
+	// validated.
+	TLSModeInsecure TLSMode = "insecure"
+	// TLSModeVerifyFull means TLS is used and the server certificate is
+	// fully validated, including hostname.
+	TLSModeVerifyFull TLSMode = "verify-full"
+)
+
+// ConnectionParams is a typed description of the resolved connection
+// details for a database, for GUI callers (e.g. Teleport Connect) that want
+// to build their own UI instead of re-parsing GetConnectCommand's argv or
+// GetConnectionURI's string.
+type ConnectionParams struct {
+	// Protocol is the Teleport database protocol being connected to.
+	Protocol string `json:"protocol"`
+	// Host is the address the native client should dial, e.g. a local
+	// proxy's listening address.
+	Host string `json:"host"`
+	// Port is the port the native client should dial.
+	Port int `json:"port"`
+	// User is the database user to authenticate as, if any.
+	User string `json:"user,omitempty"`
+	// Database is the database name to connect to, if any.
+	Database string `json:"database,omitempty"`
+	// TLSMode describes how the server certificate is validated.
+	TLSMode TLSMode `json:"tls_mode"`
+	// CACertPath is the CA certificate path, empty when TLSMode is
+	// TLSModeDisabled.
+	CACertPath string `json:"ca_cert_path,omitempty"`
+	// CertPath is the client certificate path, empty when TLSMode is
+	// TLSModeDisabled.
+	CertPath string `json:"cert_path,omitempty"`
+	// KeyPath is the client private key path, empty when TLSMode is
+	// TLSModeDisabled.
+	KeyPath string `json:"key_path,omitempty"`
+	// Options carries settings specific to Protocol that don't have a
+	// common field above, e.g. Redis's cluster mode.
+	Options map[string]string `json:"options,omitempty"`
+}
+
+// ConnectionParams returns the resolved connection details for c's database
+// as a typed struct, instead of a ready-to-run command or URI.
+func (c *CLICommandBuilder) ConnectionParams() ConnectionParams {
+	params := ConnectionParams{
+		Protocol: c.db.Protocol,
+		Host:     c.host,
+		Port:     c.port,
+		User:     c.db.Username,
+		Database: c.databaseName(),
+		TLSMode:  TLSModeVerifyFull,
+	}
+
+	switch {
+	case c.options.noTLS:
+		params.TLSMode = TLSModeDisabled
+	case c.tc.InsecureSkipVerify:
+		params.TLSMode = TLSModeInsecure
+	}
+
+	if params.TLSMode != TLSModeDisabled {
+		params.CACertPath = c.getCAPath()
+		params.CertPath = c.getCertPath()
+		params.KeyPath = c.getKeyPath()
+	}
+
+	if c.db.Protocol == defaults.ProtocolRedis && c.options.redisClusterMode {
+		params.Options = map[string]string{"clusterMode": "true"}
+	}
+
+	return params
+}
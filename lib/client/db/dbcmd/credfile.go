@@ -0,0 +1,96 @@
+/*
+
+ Copyright 2023 Gravitational, Inc.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+
+*/
+
+package dbcmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gravitational/trace"
+)
+
+// pgpassEscaper replaces the two characters libpq's .pgpass format treats
+// specially (the field separator ":" and the escape character "\") with
+// their backslash-escaped form.
+//
+// See https://www.postgresql.org/docs/current/libpq-pgpass.html.
+var pgpassEscaper = strings.NewReplacer(`\`, `\\`, `:`, `\:`)
+
+// writePgpassFile writes a single-entry .pgpass credential file at path,
+// scoped to host/port/database/username so it doesn't leak the password to
+// any other connection, and tracks it in profile's generated files manifest
+// so a later "tsh logout" cleans it up. psql (and libpq-based clients) read
+// the password from this file via PGPASSFILE instead of taking it as an
+// argument, so it never appears in argv or shell history.
+func (c *CLICommandBuilder) writePgpassFile(path, host string, port int, database, username, password string) error {
+	entry := strings.Join([]string{
+		pgpassEscaper.Replace(host),
+		pgpassEscaper.Replace(fmt.Sprintf("%d", port)),
+		pgpassEscaper.Replace(database),
+		pgpassEscaper.Replace(username),
+		pgpassEscaper.Replace(password),
+	}, ":") + "\n"
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return trace.Wrap(err, "creating directory for .pgpass credential file %q", path)
+	}
+	if err := os.WriteFile(path, []byte(entry), 0600); err != nil {
+		return trace.Wrap(err, "writing .pgpass credential file %q", path)
+	}
+	if err := trackGeneratedFile(c.profile, path); err != nil {
+		c.options.logger.Debugf("Failed to track generated file %q for cleanup: %v.", path, err)
+	}
+	return nil
+}
+
+// writeMyCnfFile writes a [client] login-path credential file at path for
+// the mysql/mariadb CLI's --defaults-extra-file flag, and tracks it in
+// profile's generated files manifest so a later "tsh logout" cleans it up.
+// Like writePgpassFile, this keeps the password out of argv and shell
+// history; mysql/mariadb read it from the file instead.
+func (c *CLICommandBuilder) writeMyCnfFile(path, username, password string) error {
+	cnf := fmt.Sprintf("[client]\nuser=%s\npassword=%s\n", myCnfEscape(username), myCnfEscape(password))
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return trace.Wrap(err, "creating directory for .my.cnf credential file %q", path)
+	}
+	if err := os.WriteFile(path, []byte(cnf), 0600); err != nil {
+		return trace.Wrap(err, "writing .my.cnf credential file %q", path)
+	}
+	if err := trackGeneratedFile(c.profile, path); err != nil {
+		c.options.logger.Debugf("Failed to track generated file %q for cleanup: %v.", path, err)
+	}
+	return nil
+}
+
+// myCnfEscape quotes value for a mysql options-file entry if needed, per
+// mysql's options-file syntax: an unquoted value ends at the first
+// whitespace or "#", so any value containing either is double-quoted, with
+// embedded double quotes and backslashes backslash-escaped.
+//
+// See https://dev.mysql.com/doc/refman/8.0/en/option-files.html.
+func myCnfEscape(value string) string {
+	if !strings.ContainsAny(value, " \t#\"") {
+		return value
+	}
+	escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value)
+	return `"` + escaped + `"`
+}
@@ -0,0 +1,2265 @@
+/*
+
+ Copyright 2022 Gravitational, Inc.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+
+*/
+
+// Package dbcmd builds the native database client command line (psql,
+// mysql, mongosh, redis-cli, etc.) used to connect to a Teleport-proxied
+// database. It is a stable, semver-versioned API so that external
+// automation (e.g. an internal developer portal) can embed Teleport
+// connection generation without shelling out to tsh.
+package dbcmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gravitational/teleport"
+	"github.com/gravitational/teleport/api/types"
+	"github.com/gravitational/teleport/lib/client"
+	"github.com/gravitational/teleport/lib/client/db"
+	"github.com/gravitational/teleport/lib/client/db/mysql"
+	"github.com/gravitational/teleport/lib/client/db/postgres"
+	"github.com/gravitational/teleport/lib/client/db/profile"
+	"github.com/gravitational/teleport/lib/defaults"
+	"github.com/gravitational/teleport/lib/tlsca"
+	"github.com/gravitational/teleport/lib/utils"
+
+	"github.com/coreos/go-semver/semver"
+	"github.com/gravitational/trace"
+	"github.com/sirupsen/logrus"
+)
+
+// log is the component logger for CLI database client command generation.
+var log = logrus.WithFields(logrus.Fields{trace.Component: teleport.ComponentClient})
+
+// TraceSpan marks the start of a named unit of work (command building,
+// binary detection, cert checks, client launch) and returns a function to
+// call when it finishes; the span's duration is logged through logger at
+// debug level so a slow connect can be narrowed down from debug logs. It
+// stands in for a real OpenTelemetry span until tsh grows a trace provider
+// to report to; the name/duration shape is deliberately span-like so a
+// later migration is a mechanical swap. logger defaults to the package
+// logger when nil.
+func TraceSpan(logger logrus.FieldLogger, name string) func() {
+	if logger == nil {
+		logger = log
+	}
+	start := time.Now()
+	return func() {
+		logger.Debugf("%s: completed in %s", name, time.Since(start))
+	}
+}
+
+const (
+	// postgresBin is the Postgres client binary name.
+	postgresBin = "psql"
+	// pgcliBin is the pgcli client binary name, an optional psql
+	// alternative with autocomplete selectable via WithPreferredClient.
+	pgcliBin = "pgcli"
+	// cockroachBin is the Cockroach client binary name.
+	cockroachBin = "cockroach"
+	// mysqlBin is the MySQL client binary name.
+	mysqlBin = "mysql"
+	// mariadbBin is the MariaDB client binary name.
+	mariadbBin = "mariadb"
+	// mysqlConfigEditorBin is the MySQL login-path credential store binary
+	// name, used by WithMySQLLoginPath to create encrypted login-path
+	// entries consumed via the mysql/mariadb client's --login-path flag.
+	mysqlConfigEditorBin = "mysql_config_editor"
+	// mysqlshBin is the MySQL Shell client binary name.
+	mysqlshBin = "mysqlsh"
+	// mycliBin is the mycli client binary name, an optional mysql/mariadb
+	// alternative with autocomplete selectable via WithPreferredClient.
+	mycliBin = "mycli"
+	// mongoshBin is the Mongo Shell client binary name.
+	mongoshBin = "mongosh"
+	// mongoBin is the Mongo client binary name.
+	mongoBin = "mongo"
+	// redisBin is the Redis client binary name.
+	redisBin = "redis-cli"
+	// sqlcmdBin is the official Microsoft SQL Server CLI binary name,
+	// shipped either as part of mssql-tools or as the newer Go-based
+	// go-sqlcmd. Preferred over mssqlBin when available.
+	sqlcmdBin = "sqlcmd"
+	// mssqlBin is the SQL Server client program name.
+	mssqlBin = "mssql-cli"
+	// cqlshBin is the Cassandra/ScyllaDB client binary name.
+	cqlshBin = "cqlsh"
+	// elasticsearchSQLCliBin is the Elasticsearch SQL CLI binary name.
+	elasticsearchSQLCliBin = "elasticsearch-sql-cli"
+	// curlBin is the curl binary name, used as a fallback Elasticsearch
+	// client when elasticsearch-sql-cli isn't installed.
+	curlBin = "curl"
+	// sqlclBin is Oracle's modern SQLcl client binary name.
+	sqlclBin = "sql"
+	// sqlplusBin is the legacy Oracle SQL*Plus client binary name, used as a
+	// fallback when sqlcl isn't installed.
+	sqlplusBin = "sqlplus"
+	// clickhouseBin is the ClickHouse client binary name.
+	clickhouseBin = "clickhouse-client"
+	// snowsqlBin is the Snowflake client binary name.
+	snowsqlBin = "snowsql"
+	// awsBin is the AWS CLI binary name, used as the DynamoDB client.
+	awsBin = "aws"
+	// cypherShellBin is the Neo4j client binary name.
+	cypherShellBin = "cypher-shell"
+	// usqlBin is the universal SQL CLI client binary name, tried as a last
+	// resort for postgres/mysql/sqlserver when no protocol-native client is
+	// available in $PATH.
+	usqlBin = "usql"
+)
+
+// Execer is an abstraction of Go's exec module, as this one doesn't specify any interfaces.
+// This interface exists only to enable mocking.
+type Execer interface {
+	// RunCommand runs a system command.
+	RunCommand(name string, arg ...string) ([]byte, error)
+	// LookPath returns a full path to a binary if this one is found in system PATH,
+	// error otherwise.
+	LookPath(file string) (string, error)
+}
+
+// SystemExecer implements the Execer interface by using Go's exec module.
+type SystemExecer struct{}
+
+// RunCommand is a wrapper for exec.Command(...).Output()
+func (s SystemExecer) RunCommand(name string, arg ...string) ([]byte, error) {
+	return exec.Command(name, arg...).Output()
+}
+
+// LookPath is a wrapper for exec.LookPath(...)
+func (s SystemExecer) LookPath(file string) (string, error) {
+	return exec.LookPath(file)
+}
+
+// CLICommandBuilder builds native database client commands for a given
+// database route.
+type CLICommandBuilder struct {
+	tc          *client.TeleportClient
+	rootCluster string
+	profile     *client.ProfileStatus
+	db          *tlsca.RouteToDatabase
+	host        string
+	port        int
+	options     connectionCommandOpts
+	uid         utils.UID
+
+	exe Execer
+
+	// versionCache caches client versions already probed via clientVersion,
+	// keyed by binary name.
+	versionCache map[string]*semver.Version
+}
+
+// NewCmdBuilder creates a CLICommandBuilder that generates native database
+// client commands for routing to db via tc's active proxy, using opts to
+// customize the generated command.
+func NewCmdBuilder(tc *client.TeleportClient, profile *client.ProfileStatus,
+	routeToDatabase *tlsca.RouteToDatabase, rootClusterName string, opts ...ConnectCommandFunc,
+) *CLICommandBuilder {
+	var options connectionCommandOpts
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.dbNamePolicy == nil {
+		options.dbNamePolicy = DefaultDatabaseNamePolicy
+	}
+	if options.logger == nil {
+		options.logger = log
+	}
+
+	// In TLS routing mode a local proxy is started on demand so connect to it.
+	host, port := tc.DatabaseProxyHostPort(*routeToDatabase)
+	if options.localProxyPort != 0 && options.localProxyHost != "" {
+		host = options.localProxyHost
+		port = options.localProxyPort
+	}
+
+	// The client is expected to run inside a container alongside the local
+	// proxy on the host, so localhost from the container's point of view
+	// won't reach it.
+	if options.containerGateway != "" && (host == "localhost" || host == "127.0.0.1") {
+		host = options.containerGateway
+		options.localProxyHost = options.containerGateway
+	}
+
+	return &CLICommandBuilder{
+		tc:          tc,
+		profile:     profile,
+		db:          routeToDatabase,
+		host:        host,
+		port:        port,
+		options:     options,
+		rootCluster: rootClusterName,
+		uid:         utils.NewRealUID(),
+
+		exe: &SystemExecer{},
+	}
+}
+
+// getKeyPath returns the private key path to present to the database client.
+// Database routes that were issued a dedicated keypair use it instead of
+// the shared profile key, so that compromising one database's key material
+// doesn't expose every other database and the SSH identity tied to the
+// profile key.
+func (c *CLICommandBuilder) getKeyPath() string {
+	if c.options.keyPath != "" {
+		return c.options.keyPath
+	}
+	dbKeyPath := c.profile.DatabaseKeyPathForCluster(c.tc.SiteName, c.db.ServiceName)
+	if _, err := os.Stat(dbKeyPath); err == nil {
+		return dbKeyPath
+	}
+	return c.profile.KeyPath()
+}
+
+// getCertPath returns the path to the certificate the database client
+// should present. When the cluster's database CA is an intermediate
+// issuing CA, several clients (e.g. redis-cli, mysql) reject a leaf-only
+// certificate, so a combined chain file (leaf + issuing CAs) is built on
+// demand and returned instead.
+func (c *CLICommandBuilder) getCertPath() string {
+	defer TraceSpan(c.options.logger, "dbcmd.cert_check")()
+
+	if c.options.certPath != "" {
+		return c.options.certPath
+	}
+	leafPath := c.profile.DatabaseCertPathForCluster(c.tc.SiteName, c.db.ServiceName)
+	caPath := c.getCAPath()
+
+	leaf, err := os.ReadFile(leafPath)
+	if err != nil {
+		return leafPath
+	}
+	ca, err := os.ReadFile(caPath)
+	if err != nil || !hasIntermediateCA(ca) {
+		return leafPath
+	}
+	chainPath := c.profile.DatabaseCertChainPathForCluster(c.tc.SiteName, c.db.ServiceName)
+	chain := append(bytes.TrimSpace(leaf), '\n')
+	chain = append(chain, bytes.TrimSpace(ca)...)
+	chain = append(chain, '\n')
+	if err := os.WriteFile(chainPath, chain, 0600); err != nil {
+		c.options.logger.Debugf("Failed to write certificate chain file %q: %v.", chainPath, err)
+		return leafPath
+	}
+	c.options.logger.Debugf("Wrote certificate chain file %q for intermediate CA.", chainPath)
+	if err := trackGeneratedFile(c.profile, chainPath); err != nil {
+		c.options.logger.Debugf("Failed to track generated file %q for cleanup: %v.", chainPath, err)
+	}
+	return chainPath
+}
+
+// generatedFilesManifest lists the helper files dbcmd has written to the
+// profile directory on behalf of database connections (e.g. certificate
+// chains built from an intermediate issuing CA), so they can be removed in
+// bulk later, for example by "tsh logout".
+type generatedFilesManifest struct {
+	Files []string `json:"files"`
+}
+
+// trackGeneratedFile records path in profile's generated files manifest so
+// a later call to CleanupGeneratedFiles will remove it. It's best-effort:
+// failing to update the manifest leaves a stray file on disk but shouldn't
+// fail the connection that's in progress.
+func trackGeneratedFile(profile *client.ProfileStatus, path string) error {
+	manifestPath := profile.GeneratedDatabaseFilesPath()
+
+	manifest, err := readGeneratedFilesManifest(manifestPath)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	for _, f := range manifest.Files {
+		if f == path {
+			return nil
+		}
+	}
+	manifest.Files = append(manifest.Files, path)
+
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(manifestPath), 0700); err != nil {
+		return trace.Wrap(err)
+	}
+	return trace.ConvertSystemError(os.WriteFile(manifestPath, data, 0600))
+}
+
+func readGeneratedFilesManifest(manifestPath string) (*generatedFilesManifest, error) {
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &generatedFilesManifest{}, nil
+		}
+		return nil, trace.ConvertSystemError(err)
+	}
+	var manifest generatedFilesManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &manifest, nil
+}
+
+// CleanupGeneratedFiles removes every helper file dbcmd has written to the
+// profile directory on behalf of database connections (e.g. combined
+// certificate chains for clusters with an intermediate database CA), along
+// with the manifest itself. It's safe to call even if nothing was ever
+// generated, so callers like "tsh logout" can invoke it unconditionally.
+func CleanupGeneratedFiles(profile *client.ProfileStatus) error {
+	manifestPath := profile.GeneratedDatabaseFilesPath()
+
+	manifest, err := readGeneratedFilesManifest(manifestPath)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	var errs []error
+	for _, f := range manifest.Files {
+		if err := os.Remove(f); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+		}
+	}
+	if err := os.Remove(manifestPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		errs = append(errs, err)
+	}
+	return trace.NewAggregate(errs...)
+}
+
+// getCAPath returns the CA certificate path the database client should use
+// to verify the server. An explicit override takes precedence (e.g. for
+// certificates issued by external tooling such as Machine ID or Vault into
+// a custom location), otherwise it falls back to the path derived from the
+// profile layout.
+func (c *CLICommandBuilder) getCAPath() string {
+	if c.options.caPath != "" {
+		return c.options.caPath
+	}
+	return db.CACertPath(c.tc, *c.profile, c.rootCluster)
+}
+
+// checkCredentialFile verifies that a database client credential file
+// computed from the profile actually exists on disk, returning a clear
+// error naming the missing file and how to fetch it. Without this check,
+// a stale or missing profile produces a command that looks correct but
+// fails deep inside the native database client's own TLS handshake.
+func (c *CLICommandBuilder) checkCredentialFile(path, kind string) error {
+	if _, err := os.Stat(path); err != nil {
+		return trace.NotFound("missing %s file %q, run `tsh db login %s` to fetch database access credentials", kind, path, c.db.ServiceName)
+	}
+	return nil
+}
+
+// databaseName returns the database name to present to the native client,
+// falling back to the configured DatabaseNamePolicy when the route didn't
+// specify one explicitly.
+func (c *CLICommandBuilder) databaseName() string {
+	if c.db.Database != "" {
+		return c.db.Database
+	}
+	return c.options.dbNamePolicy(c.db.Protocol, c.db.Username)
+}
+
+// hasIntermediateCA returns true if the given PEM-encoded CA bundle
+// contains more than one certificate, indicating the cluster uses an
+// intermediate issuing CA rather than a single self-signed root.
+func hasIntermediateCA(ca []byte) bool {
+	count := 0
+	for {
+		var block *pem.Block
+		block, ca = pem.Decode(ca)
+		if block == nil {
+			break
+		}
+		count++
+		if count > 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// GetConnectCommand returns the native command to connect to the database.
+func (c *CLICommandBuilder) GetConnectCommand() (*exec.Cmd, error) {
+	defer TraceSpan(c.options.logger, "dbcmd.build_command")()
+
+	var cmd *exec.Cmd
+	var err error
+	switch c.db.Protocol {
+	case defaults.ProtocolPostgres:
+		cmd, err = c.getPostgresCommand()
+
+	case defaults.ProtocolCockroachDB:
+		cmd, err = c.getCockroachCommand()
+
+	case defaults.ProtocolMySQL:
+		cmd, err = c.getMySQLCommand()
+
+	case defaults.ProtocolMongoDB:
+		cmd, err = c.getMongoCommand()
+
+	case defaults.ProtocolRedis:
+		cmd, err = c.getRedisCommand()
+
+	case defaults.ProtocolSQLServer:
+		cmd, err = c.getSQLServerCommand()
+
+	case defaults.ProtocolCassandra:
+		cmd, err = c.getCassandraCommand()
+
+	case defaults.ProtocolElasticsearch:
+		cmd, err = c.getElasticsearchCommand()
+
+	case defaults.ProtocolOracle:
+		cmd, err = c.getOracleCommand()
+
+	case defaults.ProtocolClickHouse:
+		cmd, err = c.getClickHouseCommand(false)
+
+	case defaults.ProtocolClickHouseHTTP:
+		cmd, err = c.getClickHouseCommand(true)
+
+	case defaults.ProtocolSnowflake:
+		cmd, err = c.getSnowflakeCommand()
+
+	case defaults.ProtocolDynamoDB:
+		cmd, err = c.getDynamoDBCommand()
+
+	case defaults.ProtocolNeo4j:
+		cmd = c.getNeo4jCommand()
+
+	default:
+		return nil, trace.BadParameter("unsupported database protocol: %v", c.db)
+	}
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	if extraArgs := c.options.extraArgs[c.db.Protocol]; len(extraArgs) > 0 {
+		c.options.logger.Debugf("Appending admin-configured extra flags for protocol %q: %v.", c.db.Protocol, extraArgs)
+		cmd.Args = append(cmd.Args, extraArgs...)
+	}
+
+	// Mongo inserts passthroughArgs itself, ahead of the trailing database
+	// name positional argument mongosh/mongo expect.
+	if len(c.options.passthroughArgs) > 0 && c.db.Protocol != defaults.ProtocolMongoDB {
+		cmd.Args = append(cmd.Args, c.options.passthroughArgs...)
+	}
+
+	if c.options.commandPolicy != nil {
+		if err := c.options.commandPolicy(cmd); err != nil {
+			return nil, trace.AccessDenied("database client command rejected by policy: %v", err)
+		}
+	}
+
+	if !c.options.pipedIO {
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+	}
+	return cmd, nil
+}
+
+// GetConnectCommandNoAbsolutePath returns the same *exec.Cmd as
+// GetConnectCommand but with Path reduced to the bare binary name (e.g.
+// "psql" instead of "/usr/local/bin/psql"). It's meant for display purposes,
+// such as printing the command for a user to copy and run themselves, where
+// an absolute path is noise at best and misleading at worst (it reflects
+// this host's filesystem layout, not the layout wherever the user runs it).
+// The returned command's Args are unaffected, so it remains otherwise
+// equivalent for inspection; starting it directly would re-resolve the
+// binary from PATH since exec.Command doesn't cache Path across mutation.
+func (c *CLICommandBuilder) GetConnectCommandNoAbsolutePath() (*exec.Cmd, error) {
+	cmd, err := c.GetConnectCommand()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	cmd.Path = filepath.Base(cmd.Path)
+	return cmd, nil
+}
+
+// ContainerImage returns the admin-approved container image configured via
+// WithContainerImages for this database's protocol, if any. Callers that run
+// the generated command inside a container use this to pick the image
+// instead of floating tags or a hardcoded default.
+func (c *CLICommandBuilder) ContainerImage() (string, bool) {
+	image, ok := c.options.containerImages[c.db.Protocol]
+	return image, ok
+}
+
+// Endpoint returns the alternate database endpoint (e.g. a reader endpoint)
+// configured via WithEndpoint, if any.
+func (c *CLICommandBuilder) Endpoint() (string, bool) {
+	return c.options.endpoint, c.options.endpoint != ""
+}
+
+// QueryAuditNotice returns the query-level-auditing notice configured via
+// WithQueryAuditNotice, if any, for inclusion in a structured description of
+// the generated command (e.g. "tsh db connect --format json").
+func (c *CLICommandBuilder) QueryAuditNotice() (string, bool) {
+	return c.options.queryAuditNotice, c.options.queryAuditNotice != ""
+}
+
+// IdleTimeout returns the client-side idle timeout configured via
+// WithIdleTimeout, if any.
+func (c *CLICommandBuilder) IdleTimeout() (time.Duration, bool) {
+	return c.options.idleTimeout, c.options.idleTimeout != 0
+}
+
+// SnowflakeAccount returns the Snowflake account name configured via
+// WithSnowflakeAccount, if any.
+func (c *CLICommandBuilder) SnowflakeAccount() (string, bool) {
+	return c.options.snowflakeAccount, c.options.snowflakeAccount != ""
+}
+
+// routeWithDefaultDatabase returns a copy of the route to the database with
+// Database resolved via the configured DatabaseNamePolicy if the route
+// didn't specify one explicitly.
+func (c *CLICommandBuilder) routeWithDefaultDatabase() tlsca.RouteToDatabase {
+	route := *c.db
+	route.Database = c.databaseName()
+	return route
+}
+
+func (c *CLICommandBuilder) checkPostgresCredentials() error {
+	if c.options.noTLS {
+		return nil
+	}
+	if err := c.checkCredentialFile(c.getKeyPath(), "private key"); err != nil {
+		return trace.Wrap(err)
+	}
+	if err := c.checkCredentialFile(c.getCertPath(), "certificate"); err != nil {
+		return trace.Wrap(err)
+	}
+	return trace.Wrap(c.checkCredentialFile(c.getCAPath(), "CA certificate"))
+}
+
+// psqlArgs returns the argv (minus the binary name) for invoking psql
+// against the given connection string, prefixed with a persistent --set
+// PROMPT1 notice when query-level auditing is enabled via
+// WithQueryAuditNotice, and with a "-c" batch query when WithExecQuery is
+// set. connString is always last, as psql requires.
+func (c *CLICommandBuilder) psqlArgs(connString string) []string {
+	var args []string
+	if notice, ok := c.QueryAuditNotice(); ok {
+		args = append(args, "--set", fmt.Sprintf("PROMPT1=[%v] %%/%%R%%# ", notice))
+	}
+	if c.options.execQuery != "" {
+		args = append(args, "-c", c.options.execQuery)
+	}
+	return append(args, connString)
+}
+
+// withPostgresOptionsEnv sets PGOPTIONS on cmd so psql (or cockroach's psql
+// fallback) carries any libpq startup parameters requested via
+// WithPostgresOptions (e.g. search_path, a role to SET on connect), and asks
+// the server to end the session after it's been idle for the configured
+// duration, ahead of the server-side idle timeout that would otherwise
+// terminate it uncleanly.
+func (c *CLICommandBuilder) withPostgresOptionsEnv(cmd *exec.Cmd) *exec.Cmd {
+	var pgOptions []string
+	if c.options.postgresOptions != "" {
+		pgOptions = append(pgOptions, c.options.postgresOptions)
+	}
+	if timeout, ok := c.IdleTimeout(); ok {
+		pgOptions = append(pgOptions, fmt.Sprintf("-c idle_session_timeout=%d", timeout.Milliseconds()))
+	}
+	if len(pgOptions) > 0 {
+		cmd.Env = append(os.Environ(), "PGOPTIONS="+strings.Join(pgOptions, " "))
+	}
+	return cmd
+}
+
+// withPostgresPasswordEnv writes a scoped .pgpass credential file and points
+// cmd at it via PGPASSFILE, if a password is configured in the secret store
+// for this database's password-auth login (self-hosted databases where
+// Teleport passes through password auth instead of terminating it via
+// mutual TLS). It's a no-op if no such secret is configured.
+func (c *CLICommandBuilder) withPostgresPasswordEnv(cmd *exec.Cmd) (*exec.Cmd, error) {
+	password, ok := c.getSecret(c.db.ServiceName + ".postgres-password")
+	if !ok {
+		return cmd, nil
+	}
+	path := c.profile.DatabaseCredentialFilePathForCluster(c.rootCluster, c.db.ServiceName, "pgpass")
+	if err := c.writePgpassFile(path, c.host, c.port, c.databaseName(), c.db.Username, password); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if cmd.Env == nil {
+		cmd.Env = os.Environ()
+	}
+	cmd.Env = append(cmd.Env, "PGPASSFILE="+path)
+	return cmd, nil
+}
+
+// pgcliCommand returns the pgcli command for connString, if pgcli was
+// explicitly requested via WithPreferredClient("pgcli") and is available in
+// PATH. pgcli accepts the same connection URL as psql, but (unlike psql)
+// has no --set flag for the query-audit PROMPT1 notice.
+func (c *CLICommandBuilder) pgcliCommand(connString string) (*exec.Cmd, error) {
+	if _, err := c.exe.LookPath(pgcliBin); err != nil {
+		return nil, trace.NotFound("%q CLI client was not found, please make sure it is available in $PATH", pgcliBin)
+	}
+	return c.withPostgresPasswordEnv(c.withPostgresOptionsEnv(exec.Command(pgcliBin, connString)))
+}
+
+// usqlCommand returns a usql invocation for dsn, used as a universal
+// fallback CLI client when no protocol-native client (psql, mysql/mariadb,
+// mssql-cli, etc.) is available in $PATH. usql accepts the same connection
+// URLs as the native clients for postgres, mysql and sqlserver, so no
+// protocol-specific argument translation is needed.
+func (c *CLICommandBuilder) usqlCommand(dsn string) (*exec.Cmd, error) {
+	if _, err := c.exe.LookPath(usqlBin); err != nil {
+		return nil, trace.NotFound("%q CLI client was not found, please make sure it is available in $PATH", usqlBin)
+	}
+	return exec.Command(usqlBin, dsn), nil
+}
+
+func (c *CLICommandBuilder) getPostgresCommand() (*exec.Cmd, error) {
+	if err := c.checkPostgresCredentials(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	connString := postgres.GetConnString(db.New(c.tc, c.routeWithDefaultDatabase(), *c.profile, c.rootCluster, c.host, c.port, c.options.keyPassphrase, c.options.caPath, c.options.certPath, c.options.keyPath, c.options.endpoint), c.options.noTLS, c.options.disableGSSAPI)
+	if c.options.postgresServiceFile {
+		// "tsh db login" has already written this profile's connection
+		// parameters to pg_service.conf (see lib/client/db.Add), so psql
+		// can be pointed at it by name instead of repeating the full
+		// connection string on the command line.
+		connString = "service=" + db.ProfileName(c.tc.SiteName, c.db.ServiceName)
+	}
+	if bin, ok := c.options.customBinaries[defaults.ProtocolPostgres]; ok {
+		c.options.logger.Debugf("Using custom binary %q, skipping client detection.", bin)
+		return c.withPostgresPasswordEnv(c.withPostgresOptionsEnv(exec.Command(bin, c.psqlArgs(connString)...)))
+	}
+	if c.options.preferredClient == pgcliBin {
+		return c.pgcliCommand(connString)
+	}
+	if _, err := c.exe.LookPath(postgresBin); err != nil {
+		c.options.logger.Debugf("Couldn't find %q client in PATH, falling back to %q: %v.", postgresBin, usqlBin, err)
+		if cmd, uErr := c.usqlCommand(connString); uErr == nil {
+			return cmd, nil
+		}
+		return nil, trace.NotFound("neither %q nor %q CLI clients were found, please make sure an appropriate CLI client is available in $PATH", postgresBin, usqlBin)
+	}
+	return c.withPostgresPasswordEnv(c.withPostgresOptionsEnv(exec.Command(postgresBin, c.psqlArgs(connString)...)))
+}
+
+func (c *CLICommandBuilder) getCockroachCommand() (*exec.Cmd, error) {
+	if err := c.checkPostgresCredentials(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	connString := postgres.GetConnString(db.New(c.tc, c.routeWithDefaultDatabase(), *c.profile, c.rootCluster, c.host, c.port, c.options.keyPassphrase, c.options.caPath, c.options.certPath, c.options.keyPath, c.options.endpoint), c.options.noTLS, c.options.disableGSSAPI)
+	if c.options.preferredClient == pgcliBin {
+		return c.pgcliCommand(connString)
+	}
+	// If cockroach CLI client is not available, fallback to psql.
+	if _, err := c.exe.LookPath(cockroachBin); err != nil {
+		c.options.logger.Debugf("Couldn't find %q client in PATH, falling back to %q: %v.",
+			cockroachBin, postgresBin, err)
+		return c.withPostgresPasswordEnv(c.withPostgresOptionsEnv(exec.Command(postgresBin, c.psqlArgs(connString)...)))
+	}
+	return exec.Command(cockroachBin, "sql", "--url", connString), nil
+}
+
+// mysqlCredentialFileArgs returns the --login-path or --defaults-extra-file
+// option pointing at a freshly created credential entry, if a password is
+// configured in the secret store for this database's password-auth login
+// (self-hosted databases where Teleport passes through password auth
+// instead of terminating it via mutual TLS). It's a no-op (nil, nil) if no
+// such secret is configured. mysql/mariadb require these options to be the
+// first option on the command line, ahead of even --defaults-group-suffix,
+// so callers must prepend the result.
+func (c *CLICommandBuilder) mysqlCredentialFileArgs() ([]string, error) {
+	password, ok := c.getSecret(c.db.ServiceName + ".mysql-password")
+	if !ok {
+		return nil, nil
+	}
+	if c.options.mysqlLoginPath {
+		loginPath := mysqlLoginPathName(c.db.ServiceName)
+		if err := c.createMySQLLoginPath(loginPath, password); err != nil {
+			return nil, trace.Wrap(err)
+		}
+		return []string{"--login-path=" + loginPath}, nil
+	}
+	path := c.profile.DatabaseCredentialFilePathForCluster(c.rootCluster, c.db.ServiceName, "my.cnf")
+	if err := c.writeMyCnfFile(path, c.db.Username, password); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return []string{"--defaults-extra-file=" + path}, nil
+}
+
+// getMySQLCommonCmdOpts returns common command line arguments for mysql and mariadb.
+// Currently, the common options are: user, database, host, port, protocol,
+// init-command and execute.
+func (c *CLICommandBuilder) getMySQLCommonCmdOpts() []string {
+	args := make([]string, 0)
+	if c.db.Username != "" {
+		args = append(args, "--user", c.db.Username)
+	}
+	if dbName := c.databaseName(); dbName != "" {
+		args = append(args, "--database", dbName)
+	}
+
+	if c.options.localProxyPort != 0 {
+		args = append(args, "--port", strconv.Itoa(c.options.localProxyPort))
+		args = append(args, "--host", c.options.localProxyHost)
+		// MySQL CLI treats localhost as a special value and tries to use Unix Domain Socket for connection
+		// To enforce TCP connection protocol needs to be explicitly specified.
+		if c.options.localProxyHost == "localhost" {
+			args = append(args, "--protocol", "TCP")
+		}
+	}
+
+	if initCommand, ok := c.mysqlInitCommand(); ok {
+		args = append(args, "--init-command="+initCommand)
+	}
+
+	if c.options.execQuery != "" {
+		args = append(args, "--execute", c.options.execQuery)
+	}
+
+	return args
+}
+
+// mysqlInitCommand returns the --init-command value combining, in order, the
+// client-side idle timeout's SET SESSION wait_timeout and any statements
+// requested via WithMySQLInitCommands (e.g. a SET ROLE required on connect),
+// or false if there's nothing to run.
+func (c *CLICommandBuilder) mysqlInitCommand() (string, bool) {
+	var statements []string
+	if timeout, ok := c.IdleTimeout(); ok {
+		// Ends the session from the client side once it's been idle for the
+		// configured duration, ahead of the server-side wait_timeout that
+		// would otherwise drop the connection uncleanly.
+		statements = append(statements, fmt.Sprintf("SET SESSION wait_timeout=%d", int(timeout.Seconds())))
+	}
+	statements = append(statements, c.options.mysqlInitCommands...)
+	if len(statements) == 0 {
+		return "", false
+	}
+	return strings.Join(statements, "; "), true
+}
+
+// mysqlDSN returns a mysql:// connection URL for the usql fallback client.
+// It doesn't carry TLS client certificate configuration the way the native
+// mysql/mariadb args do, since usql is only reached once none of those
+// clients are installed to apply them with.
+func (c *CLICommandBuilder) mysqlDSN() string {
+	return fmt.Sprintf("mysql://%s@%s:%d/%s", c.db.Username, c.host, c.port, c.databaseName())
+}
+
+// getMariaDBArgs returns arguments unique for mysql cmd shipped by MariaDB and mariadb cmd. Common options for mysql
+// between Oracle and MariaDB version are covered by getMySQLCommonCmdOpts().
+func (c *CLICommandBuilder) getMariaDBArgs() ([]string, error) {
+	credArgs, err := c.mysqlCredentialFileArgs()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	args := append(credArgs, c.getMySQLCommonCmdOpts()...)
+
+	if c.options.noTLS {
+		return args, nil
+	}
+
+	if err := c.checkCredentialFile(c.getKeyPath(), "private key"); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if err := c.checkCredentialFile(c.getCertPath(), "certificate"); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if err := c.checkCredentialFile(c.getCAPath(), "CA certificate"); err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	sslCertPath := c.getCertPath()
+
+	args = append(args, []string{"--ssl-key", c.getKeyPath()}...)
+	args = append(args, []string{"--ssl-ca", c.getCAPath()}...)
+	args = append(args, []string{"--ssl-cert", sslCertPath}...)
+
+	if c.options.crlPath != "" {
+		args = append(args, "--ssl-crl", c.options.crlPath)
+	}
+
+	// Flag below verifies "Common Name" check on the certificate provided by the server.
+	// This option is disabled by default.
+	if !c.tc.InsecureSkipVerify {
+		args = append(args, "--ssl-verify-server-cert")
+	}
+
+	return args, nil
+}
+
+// getMySQLShArgs returns arguments for the "mysqlsh" (MySQL Shell) CLI client.
+// MySQL Shell starts in its JS/Python shell mode by default, so --sql is
+// required to get a classic SQL prompt. It also uses a different set of
+// flags than the mysql/mariadb clients for selecting the default schema and
+// for controlling TLS verification.
+func (c *CLICommandBuilder) getMySQLShArgs() ([]string, error) {
+	args := []string{"--sql"}
+
+	if c.db.Username != "" {
+		args = append(args, "--user", c.db.Username)
+	}
+	if dbName := c.databaseName(); dbName != "" {
+		args = append(args, "--schema", dbName)
+	}
+
+	if c.options.localProxyPort != 0 {
+		args = append(args, "--port", strconv.Itoa(c.options.localProxyPort))
+		args = append(args, "--host", c.options.localProxyHost)
+	}
+
+	if c.options.noTLS {
+		return args, nil
+	}
+
+	if err := c.checkCredentialFile(c.getKeyPath(), "private key"); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if err := c.checkCredentialFile(c.getCertPath(), "certificate"); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if err := c.checkCredentialFile(c.getCAPath(), "CA certificate"); err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	args = append(args, "--ssl-key", c.getKeyPath())
+	args = append(args, "--ssl-cert", c.getCertPath())
+	args = append(args, "--ssl-ca", c.getCAPath())
+
+	// mysqlsh has no boolean "verify server cert" flag like mariadb's
+	// --ssl-verify-server-cert; it uses an enum ssl-mode instead.
+	if c.tc.InsecureSkipVerify {
+		args = append(args, "--ssl-mode=REQUIRED")
+	} else {
+		args = append(args, "--ssl-mode=VERIFY_CA")
+	}
+
+	return args, nil
+}
+
+// getMyCliArgs returns arguments for the "mycli" CLI client, an optional
+// mysql/mariadb alternative with autocomplete. mycli accepts the same
+// --user/--database/--host/--port and --ssl-ca/--ssl-cert/--ssl-key flags as
+// mariadb, but has no --protocol or --init-command equivalent, so it's kept
+// separate from getMySQLCommonCmdOpts/getMariaDBArgs rather than sharing them.
+func (c *CLICommandBuilder) getMyCliArgs() ([]string, error) {
+	var args []string
+
+	if c.db.Username != "" {
+		args = append(args, "--user", c.db.Username)
+	}
+	if dbName := c.databaseName(); dbName != "" {
+		args = append(args, "--database", dbName)
+	}
+	if c.options.localProxyPort != 0 {
+		args = append(args, "--port", strconv.Itoa(c.options.localProxyPort))
+		args = append(args, "--host", c.options.localProxyHost)
+	}
+
+	if c.options.noTLS {
+		return args, nil
+	}
+
+	if err := c.checkCredentialFile(c.getKeyPath(), "private key"); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if err := c.checkCredentialFile(c.getCertPath(), "certificate"); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if err := c.checkCredentialFile(c.getCAPath(), "CA certificate"); err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	args = append(args, "--ssl-ca", c.getCAPath())
+	args = append(args, "--ssl-cert", c.getCertPath())
+	args = append(args, "--ssl-key", c.getKeyPath())
+
+	return args, nil
+}
+
+// getMySQLOracleCommand returns arguments unique for mysql cmd shipped by Oracle. Common options between
+// Oracle and MariaDB version are covered by getMySQLCommonCmdOpts().
+func (c *CLICommandBuilder) getMySQLOracleCommand() (*exec.Cmd, error) {
+	credArgs, err := c.mysqlCredentialFileArgs()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	commonArgs := c.getMySQLCommonCmdOpts()
+
+	if c.options.noTLS {
+		return exec.Command(mysqlBin, append(credArgs, commonArgs...)...), nil
+	}
+
+	// defaults-extra-file (if any) and defaults-group-suffix must both come
+	// before any other option, in that order.
+	var args []string
+	if c.options.mysqlOptionFilePath != "" {
+		if err := c.writeMySQLOptionFile(c.options.mysqlOptionFilePath); err != nil {
+			return nil, trace.Wrap(err)
+		}
+		extraFile := "--defaults-extra-file=" + c.options.mysqlOptionFilePath
+		args = append(append(credArgs, extraFile), commonArgs...)
+	} else {
+		groupSuffix := fmt.Sprintf("--defaults-group-suffix=_%v-%v", c.tc.SiteName, c.db.ServiceName)
+		args = append(append(credArgs, groupSuffix), commonArgs...)
+	}
+
+	// override the ssl-mode from a config file is --insecure flag is provided to 'tsh db connect'.
+	if c.tc.InsecureSkipVerify {
+		args = append(args, fmt.Sprintf("--ssl-mode=%s", mysql.MySQLSSLModeVerifyCA))
+	}
+
+	if c.options.tlsMinVersion != "" {
+		args = append(args, fmt.Sprintf("--tls-version=TLSv%s", c.options.tlsMinVersion))
+	}
+
+	if c.options.crlPath != "" {
+		args = append(args, fmt.Sprintf("--ssl-crl=%s", c.options.crlPath))
+	}
+
+	return exec.Command(mysqlBin, args...), nil
+}
+
+// writeMySQLOptionFile writes this connection's host, port, and TLS
+// material to path's unsuffixed "[client]" section, for an isolated
+// option file passed to the mysql client via --defaults-extra-file instead
+// of relying on a --defaults-group-suffix section "tsh db login" already
+// wrote to the user's shared ~/.my.cnf. It verifies the written section is
+// complete before returning, since a partial file handed to
+// --defaults-extra-file fails with a confusing mysql-side error instead of
+// a clear Teleport one.
+func (c *CLICommandBuilder) writeMySQLOptionFile(path string) error {
+	optionFile, err := mysql.LoadFromPath(path)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	connectProfile := profile.ConnectProfile{
+		Host:       c.host,
+		Port:       c.port,
+		User:       c.db.Username,
+		Database:   c.databaseName(),
+		Insecure:   c.tc.InsecureSkipVerify,
+		CACertPath: c.getCAPath(),
+		CertPath:   c.getCertPath(),
+		KeyPath:    c.getKeyPath(),
+	}
+	if err := optionFile.UpsertClientSection(connectProfile); err != nil {
+		return trace.Wrap(err)
+	}
+	return trace.Wrap(optionFile.VerifyClientSection())
+}
+
+// getMySQLCommand returns mariadb command if the binary is on the path. Otherwise,
+// mysql command is returned. Both mysql versions (MariaDB and Oracle) are supported.
+func (c *CLICommandBuilder) getMySQLCommand() (*exec.Cmd, error) {
+	defer TraceSpan(c.options.logger, "dbcmd.binary_detection")()
+
+	if bin, ok := c.options.customBinaries[defaults.ProtocolMySQL]; ok {
+		c.options.logger.Debugf("Using custom binary %q, skipping client detection.", bin)
+		args, err := c.getMariaDBArgs()
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		return exec.Command(bin, args...), nil
+	}
+
+	if c.options.preferredClient == mysqlshBin {
+		if !c.isMySQLShBinAvailable() {
+			return nil, trace.NotFound("%q CLI client was not found, please make sure it is available in $PATH", mysqlshBin)
+		}
+		c.options.logger.Debugf("Using %q client as explicitly requested.", mysqlshBin)
+		args, err := c.getMySQLShArgs()
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		return exec.Command(mysqlshBin, args...), nil
+	}
+
+	// mycli is never auto-selected (unlike mysqlsh's last-resort fallback
+	// below) so that stock behavior is unchanged unless a caller opts in.
+	if c.options.preferredClient == mycliBin {
+		if !c.isMyCliBinAvailable() {
+			return nil, trace.NotFound("%q CLI client was not found, please make sure it is available in $PATH", mycliBin)
+		}
+		c.options.logger.Debugf("Using %q client as explicitly requested.", mycliBin)
+		args, err := c.getMyCliArgs()
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		return exec.Command(mycliBin, args...), nil
+	}
+
+	// Check if mariadb client is available. Prefer it over mysql client even if connecting to MySQL server.
+	if c.isMariaDBBinAvailable() {
+		c.options.logger.Debugf("Found %q client in PATH, using it.", mariadbBin)
+		args, err := c.getMariaDBArgs()
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		return exec.Command(mariadbBin, args...), nil
+	}
+
+	// Check for mysql binary. If it's also missing, fall back to mysqlsh
+	// when it's the only client installed, before giving up entirely.
+	if !c.isMySQLBinAvailable() {
+		if c.isMySQLShBinAvailable() {
+			c.options.logger.Debugf("Neither %q nor %q found in PATH, falling back to %q.", mysqlBin, mariadbBin, mysqlshBin)
+			args, err := c.getMySQLShArgs()
+			if err != nil {
+				return nil, trace.Wrap(err)
+			}
+			return exec.Command(mysqlshBin, args...), nil
+		}
+		c.options.logger.Debugf("Neither %q, %q nor %q found in PATH, falling back to %q.", mysqlBin, mariadbBin, mysqlshBin, usqlBin)
+		if cmd, uErr := c.usqlCommand(c.mysqlDSN()); uErr == nil {
+			return cmd, nil
+		}
+		return nil, trace.NotFound("none of %q, %q, %q or %q CLI clients were found, please make sure an appropriate CLI client is available in $PATH", mysqlBin, mariadbBin, mysqlshBin, usqlBin)
+	}
+
+	// Check which flavor is installed. Otherwise, we don't know which ssl flag to use.
+	// At the moment of writing mysql binary shipped by Oracle and MariaDB accept different ssl parameters and have the same name.
+	mySQLMariaDBFlavor, err := c.isMySQLBinMariaDBFlavor()
+	if mySQLMariaDBFlavor && err == nil {
+		c.options.logger.Debugf("Found %q client in PATH, detected MariaDB flavor, using MariaDB flags.", mysqlBin)
+		args, err := c.getMariaDBArgs()
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		return exec.Command(mysqlBin, args...), nil
+	}
+
+	// Either we failed to check the flavor or binary comes from Oracle. Regardless return mysql/Oracle command.
+	c.options.logger.Debugf("Found %q client in PATH, using Oracle MySQL flags (flavor detection error: %v).", mysqlBin, err)
+	return c.getMySQLOracleCommand()
+}
+
+// isMariaDBBinAvailable returns true if "mariadb" binary is found in the system PATH.
+func (c *CLICommandBuilder) isMariaDBBinAvailable() bool {
+	_, err := c.exe.LookPath(mariadbBin)
+	return err == nil
+}
+
+// isMySQLBinAvailable returns true if "mysql" binary is found in the system PATH.
+func (c *CLICommandBuilder) isMySQLBinAvailable() bool {
+	_, err := c.exe.LookPath(mysqlBin)
+	return err == nil
+}
+
+// isMySQLShBinAvailable returns true if "mysqlsh" binary is found in the system PATH.
+func (c *CLICommandBuilder) isMySQLShBinAvailable() bool {
+	_, err := c.exe.LookPath(mysqlshBin)
+	return err == nil
+}
+
+// isMyCliBinAvailable returns true if "mycli" binary is found in the system PATH.
+func (c *CLICommandBuilder) isMyCliBinAvailable() bool {
+	_, err := c.exe.LookPath(mycliBin)
+	return err == nil
+}
+
+// isMongoshBinAvailable returns true if "mongosh" binary is found in the system PATH.
+func (c *CLICommandBuilder) isMongoshBinAvailable() bool {
+	_, err := c.exe.LookPath(mongoshBin)
+	return err == nil
+}
+
+// minMongoshVersionWithTLSCAFile is the mongosh version starting with which
+// --tlsCAFile reliably accepts a custom CA file. Older installs fall back
+// to the system CA store instead.
+var minMongoshVersionWithTLSCAFile = semver.New("1.0.0")
+
+// mongoshSupportsTLSCAFile probes the installed mongosh's version and
+// reports whether it's new enough to accept a custom --tlsCAFile.
+func (c *CLICommandBuilder) mongoshSupportsTLSCAFile() bool {
+	return c.clientAtLeast(mongoshBin, extractMongoshVersion, *minMongoshVersionWithTLSCAFile)
+}
+
+// isElasticsearchSQLCliBinAvailable returns true if "elasticsearch-sql-cli"
+// binary is found in the system PATH.
+func (c *CLICommandBuilder) isElasticsearchSQLCliBinAvailable() bool {
+	_, err := c.exe.LookPath(elasticsearchSQLCliBin)
+	return err == nil
+}
+
+// isMySQLBinMariaDBFlavor checks if mysql binary comes from Oracle or MariaDB.
+// true is returned when binary comes from MariaDB, false when from Oracle.
+func (c *CLICommandBuilder) isMySQLBinMariaDBFlavor() (bool, error) {
+	// Check if mysql comes from Oracle or MariaDB
+	mysqlVer, err := c.exe.RunCommand(mysqlBin, "--version")
+	if err != nil {
+		// Looks like incorrect mysql installation.
+		return false, trace.Wrap(err)
+	}
+
+	// Check which flavor is installed. Otherwise, we don't know which ssl flag to use.
+	// Example output:
+	// Oracle:
+	// mysql  Ver 8.0.27-0ubuntu0.20.04.1 for Linux on x86_64 ((Ubuntu))
+	// MariaDB:
+	// mysql  Ver 15.1 Distrib 10.3.32-MariaDB, for debian-linux-gnu (x86_64) using readline 5.2
+	return strings.Contains(strings.ToLower(string(mysqlVer)), "mariadb"), nil
+}
+
+// minMongoDBServerVersionWithoutLegacyShell is the MongoDB server version
+// starting with which the legacy "mongo" shell is deprecated; Teleport
+// refuses to fall back to it against servers this new or newer rather than
+// risk a confusing handshake failure.
+var minMongoDBServerVersionWithoutLegacyShell = semver.New("5.0.0")
+
+func (c *CLICommandBuilder) getMongoCommand() (*exec.Cmd, error) {
+	customBin, hasCustomBin := c.options.customBinaries[defaults.ProtocolMongoDB]
+	if hasCustomBin {
+		c.options.logger.Debugf("Using custom binary %q, skipping client detection.", customBin)
+	}
+
+	// look for `mongosh`, assuming mongosh-compatible flags for a custom binary
+	hasMongosh := hasCustomBin || c.isMongoshBinAvailable()
+
+	if !hasCustomBin && !hasMongosh && c.options.mongoDBServerVersion != "" {
+		if serverVersion, err := semver.NewVersion(c.options.mongoDBServerVersion); err == nil {
+			if !serverVersion.LessThan(*minMongoDBServerVersionWithoutLegacyShell) {
+				return nil, trace.BadParameter("the legacy \"mongo\" shell is not supported against MongoDB %v; "+
+					"install \"mongosh\" to connect to this database", c.options.mongoDBServerVersion)
+			}
+		} else {
+			c.options.logger.Debugf("Couldn't parse MongoDB server version %q: %v.", c.options.mongoDBServerVersion, err)
+		}
+	}
+
+	args := []string{
+		"--host", c.host,
+		"--port", strconv.Itoa(c.port),
+	}
+
+	if !c.options.noTLS {
+		if err := c.checkCredentialFile(c.getCertPath(), "certificate"); err != nil {
+			return nil, trace.Wrap(err)
+		}
+		// Starting with Mongo 4.2 there is an updated set of flags.
+		// We are using them with `mongosh` as otherwise warnings will get displayed.
+		type tlsFlags struct {
+			tls            string
+			tlsCertKeyFile string
+			tlsCAFile      string
+		}
+
+		var flags tlsFlags
+
+		if hasMongosh {
+			flags = tlsFlags{tls: "--tls", tlsCertKeyFile: "--tlsCertificateKeyFile", tlsCAFile: "--tlsCAFile"}
+		} else {
+			flags = tlsFlags{tls: "--ssl", tlsCertKeyFile: "--sslPEMKeyFile", tlsCAFile: "--sslCAFile"}
+		}
+
+		args = append(args,
+			flags.tls,
+			flags.tlsCertKeyFile,
+			c.getCertPath())
+
+		if c.options.keyPassphrase != "" && hasMongosh {
+			args = append(args, "--tlsCertificateKeyFilePassword", c.options.keyPassphrase)
+		}
+
+		switch {
+		case c.options.caPath != "":
+			// caPath is set either by WithLocalProxy, when mongo connects to the Teleport Proxy
+			// via ALPN SNI Local Proxy and connection is terminated by proxy identity certificate,
+			// or explicitly by WithCAPath.
+			if err := c.checkCredentialFile(c.options.caPath, "CA certificate"); err != nil {
+				return nil, trace.Wrap(err)
+			}
+			args = append(args, []string{flags.tlsCAFile, c.options.caPath}...)
+		case hasMongosh && (hasCustomBin || c.mongoshSupportsTLSCAFile()):
+			// Prefer the cluster CA from the profile over mongosh's system CA
+			// store, which may not trust the cluster CA on hosts where it
+			// isn't installed system-wide. Fall back to the system CA store
+			// only if the profile doesn't have a CA file to offer.
+			if profileCAPath := c.getCAPath(); c.checkCredentialFile(profileCAPath, "CA certificate") == nil {
+				args = append(args, flags.tlsCAFile, profileCAPath)
+			} else {
+				args = append(args, "--tlsUseSystemCA")
+			}
+		case hasMongosh:
+			// mongosh older than minMongoshVersionWithTLSCAFile doesn't
+			// reliably accept a custom --tlsCAFile, so fall back to
+			// whatever CA store the host already trusts.
+			args = append(args, "--tlsUseSystemCA")
+		}
+	}
+
+	if len(c.options.passthroughArgs) > 0 {
+		args = append(args, c.options.passthroughArgs...)
+	}
+
+	if c.options.execQuery != "" {
+		args = append(args, "--eval", c.options.execQuery)
+	}
+
+	if dbName := c.databaseName(); dbName != "" {
+		args = append(args, dbName)
+	}
+
+	if hasCustomBin {
+		return exec.Command(customBin, args...), nil
+	}
+
+	// use `mongosh` if available
+	if hasMongosh {
+		return exec.Command(mongoshBin, args...), nil
+	}
+
+	// fall back to `mongo` if `mongosh` isn't found
+	return exec.Command(mongoBin, args...), nil
+}
+
+// GetConnectionURI returns a full mongodb:// connection URI equivalent to
+// the command GetConnectCommand would build, e.g.
+// "mongodb://alice@localhost:12345/admin?tls=true&tlsCertificateKeyFile=...".
+// Unlike the flag-based command, the URI is copy-pasteable into tools that
+// take a connection string instead of a CLI invocation (e.g. MongoDB
+// Compass), and can express options mongosh/mongo have no flag for, such as
+// replicaSet or readPreference, via WithMongoDBURIOptions.
+//
+// It's only implemented for MongoDB; other protocols return
+// trace.BadParameter.
+func (c *CLICommandBuilder) GetConnectionURI() (string, error) {
+	if c.db.Protocol != defaults.ProtocolMongoDB {
+		return "", trace.BadParameter("connection URI format is not supported for %q", c.db.Protocol)
+	}
+
+	uri := &url.URL{
+		Scheme: "mongodb",
+		Host:   fmt.Sprintf("%s:%d", c.host, c.port),
+		Path:   "/" + c.databaseName(),
+	}
+	if c.db.Username != "" {
+		uri.User = url.User(c.db.Username)
+	}
+
+	query := url.Values{}
+	if !c.options.noTLS {
+		if err := c.checkCredentialFile(c.getCertPath(), "certificate"); err != nil {
+			return "", trace.Wrap(err)
+		}
+		query.Set("tls", "true")
+		query.Set("tlsCertificateKeyFile", c.getCertPath())
+		if c.options.caPath != "" {
+			if err := c.checkCredentialFile(c.options.caPath, "CA certificate"); err != nil {
+				return "", trace.Wrap(err)
+			}
+			query.Set("tlsCAFile", c.options.caPath)
+		}
+	}
+	for k, v := range c.options.mongoDBURIOptions {
+		query.Set(k, v)
+	}
+	uri.RawQuery = query.Encode()
+
+	return uri.String(), nil
+}
+
+// getSecret looks up key in the configured SecretStore, if any, logging and
+// ignoring lookup failures since the secret is typically optional (e.g.
+// Redis AUTH).
+func (c *CLICommandBuilder) getSecret(key string) (string, bool) {
+	if c.options.secrets == nil {
+		return "", false
+	}
+	secret, err := c.options.secrets.GetSecret(context.Background(), key)
+	if err != nil {
+		log.WithError(err).Debugf("Failed to retrieve secret %q.", key)
+		return "", false
+	}
+	return secret, true
+}
+
+// minRedisCliVersionWithSNI is the redis-cli version starting with which
+// --sni is accepted for TLS connections. Older installs reject unknown
+// flags outright, so it's only added once the installed client is known to
+// support it.
+var minRedisCliVersionWithSNI = semver.New("6.2.0")
+
+// redisCliSupportsSNI probes the installed redis-cli's version and reports
+// whether it's new enough to accept --sni.
+func (c *CLICommandBuilder) redisCliSupportsSNI() bool {
+	return c.clientAtLeast(redisBin, extractRedisCliVersion, *minRedisCliVersionWithSNI)
+}
+
+// getRedisCommand returns redis-cli commands used by 'tsh db connect' when connecting to a Redis instance.
+func (c *CLICommandBuilder) getRedisCommand() (*exec.Cmd, error) {
+	bin, hasCustomBin := c.options.customBinaries[defaults.ProtocolRedis]
+	if hasCustomBin {
+		c.options.logger.Debugf("Using custom binary %q, skipping client detection.", bin)
+	} else {
+		bin = redisBin
+	}
+
+	// TODO(jakub): Add "-3" when Teleport adds support for Redis RESP3 protocol.
+	args := []string{
+		"-h", c.host,
+		"-p", strconv.Itoa(c.port),
+	}
+
+	if c.options.redisClusterMode {
+		// Without -c, redis-cli reports MOVED/ASK redirects as errors
+		// instead of following them, which breaks any command that doesn't
+		// happen to hash to a key owned by the node it connected to.
+		args = append(args, "-c")
+	}
+
+	if !c.options.noTLS {
+		args = append(args, "--tls")
+
+		if !c.options.noClientCert {
+			if err := c.checkCredentialFile(c.getKeyPath(), "private key"); err != nil {
+				return nil, trace.Wrap(err)
+			}
+			if err := c.checkCredentialFile(c.getCertPath(), "certificate"); err != nil {
+				return nil, trace.Wrap(err)
+			}
+			args = append(args, "--key", c.getKeyPath(), "--cert", c.getCertPath())
+		}
+
+		if c.tc.InsecureSkipVerify {
+			args = append(args, "--insecure")
+		} else if hasCustomBin || c.redisCliSupportsSNI() {
+			// Without an explicit SNI hostname, older redis-cli versions
+			// silently skip certificate hostname verification rather than
+			// failing closed. Setting it to the dialed host makes
+			// verification actually happen.
+			args = append(args, "--sni", c.host)
+		} else {
+			c.options.logger.Debugf("Installed %q does not support --sni; TLS hostname verification may be skipped.", redisBin)
+		}
+
+		if c.options.caPath != "" {
+			if err := c.checkCredentialFile(c.options.caPath, "CA certificate"); err != nil {
+				return nil, trace.Wrap(err)
+			}
+			args = append(args, []string{"--cacert", c.options.caPath}...)
+		}
+	}
+
+	// append database number if provided
+	if c.db.Database != "" {
+		args = append(args, []string{"-n", c.db.Database}...)
+	}
+
+	// Pass the ACL username along, so Redis 6+ ACL users can authenticate
+	// without a post-connect AUTH command.
+	if c.db.Username != "" {
+		args = append(args, "--user", c.db.Username)
+	}
+
+	if c.options.execQuery != "" {
+		// redis-cli takes the command to run non-interactively as trailing
+		// positional arguments, e.g. "redis-cli GET foo", rather than a
+		// single flag value.
+		args = append(args, strings.Fields(c.options.execQuery)...)
+	}
+
+	cmd := exec.Command(bin, args...)
+	// If the configured secret store has an AUTH token for this database,
+	// pass it along via REDISCLI_AUTH rather than -a, so it never shows up
+	// in the command line (and therefore in `ps` output or shell history).
+	if authToken, ok := c.getSecret(c.db.ServiceName + ".redis-auth-token"); ok {
+		cmd.Env = append(os.Environ(), "REDISCLI_AUTH="+authToken)
+	}
+
+	return cmd, nil
+}
+
+func (c *CLICommandBuilder) getSQLServerCommand() (*exec.Cmd, error) {
+	if c.options.adKRB5CCachePath != "" {
+		return c.getSQLServerADCommand()
+	}
+
+	// Password is required by the client but doesn't matter as we're
+	// connecting to local proxy, unless the configured secret store has
+	// one on file for this database (e.g. for tools that validate it).
+	password := c.uid.New()
+	if fromStore, ok := c.getSecret(c.db.ServiceName + ".sqlserver-password"); ok {
+		password = fromStore
+	}
+
+	args := []string{
+		// Host and port must be comma-separated.
+		"-S", fmt.Sprintf("%v,%v", c.host, c.port),
+		"-U", c.db.Username,
+		"-P", password,
+	}
+	if c.db.Database != "" {
+		args = append(args, "-d", c.db.Database)
+	}
+	if c.options.execQuery != "" {
+		args = append(args, "-Q", c.options.execQuery)
+	}
+
+	if _, err := c.exe.LookPath(sqlcmdBin); err == nil {
+		return exec.Command(sqlcmdBin, args...), nil
+	}
+	c.options.logger.Debugf("Couldn't find %q client in PATH, falling back to %q.", sqlcmdBin, mssqlBin)
+
+	if _, err := c.exe.LookPath(mssqlBin); err == nil {
+		return exec.Command(mssqlBin, args...), nil
+	}
+	c.options.logger.Debugf("Couldn't find %q client in PATH, falling back to %q.", mssqlBin, usqlBin)
+
+	dsn := fmt.Sprintf("sqlserver://%s:%s@%s:%d", c.db.Username, password, c.host, c.port)
+	if c.db.Database != "" {
+		dsn += "?database=" + c.db.Database
+	}
+	if cmd, uErr := c.usqlCommand(dsn); uErr == nil {
+		return cmd, nil
+	}
+	return nil, trace.NotFound("neither %q, %q nor %q CLI clients were found, please make sure an appropriate CLI client is available in $PATH", sqlcmdBin, mssqlBin, usqlBin)
+}
+
+// getSQLServerADCommand builds an sqlcmd/mssql-cli invocation that uses
+// Kerberos integrated auth ("-E") instead of a SQL auth fixture password,
+// for a domain-joined SQL Server database configured with Active Directory
+// authentication. usql has no integrated auth mode, so it isn't offered as
+// a fallback here.
+func (c *CLICommandBuilder) getSQLServerADCommand() (*exec.Cmd, error) {
+	args := []string{
+		// Host and port must be comma-separated.
+		"-S", fmt.Sprintf("%v,%v", c.host, c.port),
+		"-E",
+	}
+	if c.db.Database != "" {
+		args = append(args, "-d", c.db.Database)
+	}
+	if c.options.execQuery != "" {
+		args = append(args, "-Q", c.options.execQuery)
+	}
+	env := append(os.Environ(), "KRB5CCNAME="+c.options.adKRB5CCachePath)
+
+	for _, bin := range []string{sqlcmdBin, mssqlBin} {
+		if _, err := c.exe.LookPath(bin); err == nil {
+			cmd := exec.Command(bin, args...)
+			cmd.Env = env
+			return cmd, nil
+		}
+		c.options.logger.Debugf("Couldn't find %q client in PATH.", bin)
+	}
+	return nil, trace.NotFound("neither %q nor %q CLI clients were found, please make sure an appropriate CLI client is available in $PATH", sqlcmdBin, mssqlBin)
+}
+
+// getCassandraCommand builds a cqlsh invocation for the local proxy. cqlsh
+// doesn't accept client cert/key paths as flags; it only reads them from a
+// "[ssl]" section in ~/.cassandra/cqlshrc (userkey/usercert), so this only
+// wires up the CA cert cqlsh does accept via the SSL_CERTFILE environment
+// variable and leaves client cert configuration to the user's cqlshrc, same
+// as plain cqlsh usage against any TLS-enabled cluster.
+func (c *CLICommandBuilder) getCassandraCommand() (*exec.Cmd, error) {
+	args := []string{c.host, strconv.Itoa(c.port)}
+	if c.db.Username != "" {
+		args = append(args, "-u", c.db.Username)
+	}
+
+	cmd := exec.Command(cqlshBin, args...)
+	if !c.options.noTLS {
+		if err := c.checkCredentialFile(c.getCAPath(), "CA certificate"); err != nil {
+			return nil, trace.Wrap(err)
+		}
+		args = append(args, "--ssl")
+		cmd = exec.Command(cqlshBin, args...)
+		cmd.Env = append(os.Environ(), "SSL_CERTFILE="+c.getCAPath(), "SSL_VALIDATE=true")
+	}
+
+	return cmd, nil
+}
+
+// getElasticsearchCommand returns an elasticsearch-sql-cli command if the
+// binary is on the path, falling back to a curl invocation preconfigured
+// with the local proxy's client certs otherwise, following the same
+// execer/LookPath detection pattern as getMySQLCommand.
+func (c *CLICommandBuilder) getElasticsearchCommand() (*exec.Cmd, error) {
+	url := fmt.Sprintf("https://%v:%v/", c.host, c.port)
+
+	if c.isElasticsearchSQLCliBinAvailable() {
+		c.options.logger.Debugf("Found %q client in PATH, using it.", elasticsearchSQLCliBin)
+		return exec.Command(elasticsearchSQLCliBin, url), nil
+	}
+
+	if _, err := c.exe.LookPath(curlBin); err != nil {
+		return nil, trace.NotFound("neither %q nor %q were found, please make sure an appropriate CLI client is available in $PATH", elasticsearchSQLCliBin, curlBin)
+	}
+	c.options.logger.Debugf("%q not found in PATH, falling back to %q.", elasticsearchSQLCliBin, curlBin)
+
+	args := []string{url}
+	if !c.options.noTLS {
+		if err := c.checkCredentialFile(c.getKeyPath(), "private key"); err != nil {
+			return nil, trace.Wrap(err)
+		}
+		if err := c.checkCredentialFile(c.getCertPath(), "certificate"); err != nil {
+			return nil, trace.Wrap(err)
+		}
+		args = append(args, "--cert", c.getCertPath(), "--key", c.getKeyPath())
+		if c.options.caPath != "" {
+			if err := c.checkCredentialFile(c.options.caPath, "CA certificate"); err != nil {
+				return nil, trace.Wrap(err)
+			}
+			args = append(args, "--cacert", c.options.caPath)
+		}
+	}
+	return exec.Command(curlBin, args...), nil
+}
+
+// isSQLclBinAvailable returns true if the "sql" (SQLcl) binary is found in
+// the system PATH.
+func (c *CLICommandBuilder) isSQLclBinAvailable() bool {
+	_, err := c.exe.LookPath(sqlclBin)
+	return err == nil
+}
+
+// getOracleCommand builds an EZConnect-style TCPS connection string pointing
+// at the local proxy, preferring SQLcl ("sql") over the legacy "sqlplus"
+// client when both are on the PATH, following the same execer/LookPath
+// detection pattern as getMySQLCommand.
+//
+// Unlike the other protocols, Oracle clients authenticate TLS through an
+// Oracle wallet rather than raw PEM files, so the profile's cert/key/CA
+// paths can't be handed to sqlplus/SQLcl directly the way they can to e.g.
+// psql or mysql. This points TNS_ADMIN at the directory containing those
+// PEM files so a wallet generated there (e.g. with orapki or mkstore) is
+// picked up automatically; it's the same kind of documented gap as the
+// cqlshrc limitation on getCassandraCommand.
+func (c *CLICommandBuilder) getOracleCommand() (*exec.Cmd, error) {
+	bin := sqlplusBin
+	if c.isSQLclBinAvailable() {
+		c.options.logger.Debugf("Found %q client in PATH, using it.", sqlclBin)
+		bin = sqlclBin
+	}
+
+	descriptor := fmt.Sprintf("%v@tcps://%v:%v/%v", c.db.Username, c.host, c.port, c.databaseName())
+	cmd := exec.Command(bin, descriptor)
+
+	if !c.options.noTLS {
+		if err := c.checkCredentialFile(c.getCAPath(), "CA certificate"); err != nil {
+			return nil, trace.Wrap(err)
+		}
+		cmd.Env = append(os.Environ(), "TNS_ADMIN="+filepath.Dir(c.getCAPath()))
+	}
+
+	return cmd, nil
+}
+
+// getClickHouseCommand builds a clickhouse-client invocation for the local
+// proxy, using --protocol http for the HTTP interface and the native wire
+// protocol otherwise. clickhouse-client has no CLI flags for a client
+// cert/key (it only reads them from an "openSSL/client" section in its own
+// config.xml), so --secure is as far as this goes without generating that
+// config; mutual-TLS client-cert auth against the proxy still needs it, the
+// same kind of documented gap as getCassandraCommand's cqlshrc limitation.
+//
+// For the HTTP interface only, a missing clickhouse-client falls back to
+// curl -- a protocol-compatible alternate client, same fallback pattern
+// getCockroachCommand uses with psql. The native wire protocol has no such
+// widely available alternate client, so a missing clickhouse-client there
+// is a hard error.
+func (c *CLICommandBuilder) getClickHouseCommand(http bool) (*exec.Cmd, error) {
+	if _, err := c.exe.LookPath(clickhouseBin); err != nil {
+		if !http {
+			return nil, trace.NotFound("%q CLI client was not found, please make sure it is available in $PATH", clickhouseBin)
+		}
+		c.options.logger.Debugf("Couldn't find %q client in PATH, falling back to %q: %v.", clickhouseBin, curlBin, err)
+		return c.getClickHouseHTTPCurlCommand()
+	}
+
+	args := []string{"--host", c.host, "--port", strconv.Itoa(c.port)}
+	if c.db.Username != "" {
+		args = append(args, "--user", c.db.Username)
+	}
+	if database := c.databaseName(); database != "" {
+		args = append(args, "--database", database)
+	}
+	if http {
+		args = append(args, "--protocol", "http")
+	}
+	if !c.options.noTLS {
+		args = append(args, "--secure")
+	}
+
+	return exec.Command(clickhouseBin, args...), nil
+}
+
+// getClickHouseHTTPCurlCommand builds a curl invocation against ClickHouse's
+// HTTP interface, for when clickhouse-client isn't installed. Unlike the
+// native client, curl's --cert/--key/--cacert flags work fine for the
+// proxy's client certs, since ClickHouse's HTTP interface is plain HTTPS.
+func (c *CLICommandBuilder) getClickHouseHTTPCurlCommand() (*exec.Cmd, error) {
+	if _, err := c.exe.LookPath(curlBin); err != nil {
+		return nil, trace.NotFound("neither %q nor %q were found, please make sure an appropriate CLI client is available in $PATH", clickhouseBin, curlBin)
+	}
+
+	scheme := "http"
+	var args []string
+	if !c.options.noTLS {
+		scheme = "https"
+		if err := c.checkCredentialFile(c.getKeyPath(), "private key"); err != nil {
+			return nil, trace.Wrap(err)
+		}
+		if err := c.checkCredentialFile(c.getCertPath(), "certificate"); err != nil {
+			return nil, trace.Wrap(err)
+		}
+		args = append(args, "--cert", c.getCertPath(), "--key", c.getKeyPath())
+		if c.options.caPath != "" {
+			if err := c.checkCredentialFile(c.options.caPath, "CA certificate"); err != nil {
+				return nil, trace.Wrap(err)
+			}
+			args = append(args, "--cacert", c.options.caPath)
+		}
+	}
+	args = append(args, fmt.Sprintf("%v://%v:%v/", scheme, c.host, c.port))
+
+	return exec.Command(curlBin, args...), nil
+}
+
+// getSnowflakeCommand builds a snowsql invocation pointed at the local
+// proxy via --host/--port, authenticating with an OAuth access token when
+// one is available from the configured secret store (the same pattern
+// getSQLServerCommand uses for its password) and falling back to snowsql's
+// normal interactive password prompt otherwise.
+//
+// Unlike getRedisCommand's AUTH token, snowsql's --token has no documented
+// environment variable equivalent (SNOWSQL_PWD only covers the interactive
+// password prompt, not --authenticator oauth), so the token is passed as a
+// literal flag value the same way getSQLServerCommand's password is -- see
+// secretFlags in redact.go, which redacts it from both `tsh db config`
+// output and the debug log in onDatabaseConnect.
+//
+// The account name snowsql needs for its login request isn't part of
+// Teleport's database route (see tlsca.RouteToDatabase), so it comes from
+// WithSnowflakeAccount if the caller set one, or falls back to the
+// database's service name otherwise -- see the option's doc comment for
+// why that fallback is only a best guess.
+func (c *CLICommandBuilder) getSnowflakeCommand() (*exec.Cmd, error) {
+	account := c.db.ServiceName
+	if a, ok := c.SnowflakeAccount(); ok {
+		account = a
+	}
+
+	args := []string{
+		"--accountname", account,
+		"--host", c.host,
+		"--port", strconv.Itoa(c.port),
+		"--username", c.db.Username,
+	}
+	if c.databaseName() != "" {
+		args = append(args, "--dbname", c.databaseName())
+	}
+
+	if token, ok := c.getSecret(c.db.ServiceName + ".snowflake-token"); ok {
+		args = append(args, "--authenticator", "oauth", "--token", token)
+	}
+
+	return exec.Command(snowsqlBin, args...), nil
+}
+
+// getDynamoDBCommand builds an AWS CLI invocation pointed at the local
+// proxy's HTTPS endpoint.
+//
+// Unlike the SQL/NoSQL shells above, the AWS CLI has no interactive
+// "connect and issue queries" mode, so this runs "aws dynamodb list-tables"
+// as a connectivity smoke test the same way getElasticsearchCommand's curl
+// fallback hits Elasticsearch's base URL.
+//
+// The AWS CLI also has no flags for a client cert, since DynamoDB access is
+// authenticated via SigV4 request signing rather than mutual TLS; the local
+// proxy is expected to attach the caller's identity out of band, so this
+// only wires up the endpoint and CA trust, leaving AWS credentials (e.g.
+// AWS_ACCESS_KEY_ID/AWS_SECRET_ACCESS_KEY) to the AWS CLI's normal
+// credential chain, the same kind of documented gap as getOracleCommand's
+// wallet requirement.
+func (c *CLICommandBuilder) getDynamoDBCommand() (*exec.Cmd, error) {
+	if _, err := c.exe.LookPath(awsBin); err != nil {
+		return nil, trace.NotFound("%q CLI client was not found, please make sure it is available in $PATH", awsBin)
+	}
+
+	url := fmt.Sprintf("https://%v:%v", c.host, c.port)
+	args := []string{"dynamodb", "list-tables", "--endpoint-url", url}
+
+	cmd := exec.Command(awsBin, args...)
+	if !c.options.noTLS {
+		if err := c.checkCredentialFile(c.getCAPath(), "CA certificate"); err != nil {
+			return nil, trace.Wrap(err)
+		}
+		cmd.Env = append(os.Environ(), "AWS_CA_BUNDLE="+c.getCAPath())
+	}
+
+	return cmd, nil
+}
+
+// getNeo4jCommand builds a cypher-shell invocation pointed at the local
+// proxy, using the "neo4j+s" Bolt URI scheme for an encrypted connection or
+// plain "neo4j" under WithNoTLS.
+//
+// cypher-shell has no CLI flags for a client cert/key -- its TLS support is
+// limited to trusting (or not trusting) the server's certificate -- so
+// mutual-TLS client-cert auth against the proxy isn't available here, the
+// same kind of documented gap as getClickHouseCommand's --secure-only
+// limitation.
+func (c *CLICommandBuilder) getNeo4jCommand() *exec.Cmd {
+	scheme := "neo4j+s"
+	if c.options.noTLS {
+		scheme = "neo4j"
+	}
+	address := fmt.Sprintf("%s://%s:%d", scheme, c.host, c.port)
+
+	args := []string{"-a", address}
+	if c.db.Username != "" {
+		args = append(args, "-u", c.db.Username)
+	}
+
+	return exec.Command(cypherShellBin, args...)
+}
+
+// GetAutoUserProvisioningStatus returns a human-readable status line
+// describing whether the database has automatic user provisioning
+// configured, for inclusion in "tsh db connect" output. It returns an empty
+// string if the database doesn't support auto-user provisioning.
+//
+// Note: the specific database roles that will be granted are resolved from
+// the connecting user's roles at connection time by the database service,
+// and aren't known to the client ahead of time.
+func GetAutoUserProvisioningStatus(database types.Database) string {
+	if !database.SupportsAutoUsers() {
+		return ""
+	}
+	return fmt.Sprintf("Automatic user provisioning is enabled for %v: "+
+		"your database user will be created (or enabled) on connect and "+
+		"disabled on disconnect.", database.GetName())
+}
+
+// IsRedisClusterMode reports whether database's connection endpoint is
+// configured for Redis cluster mode, i.e. its URI has a "mode=cluster"
+// query parameter (see lib/srv/db/redis.ParseRedisAddress). It's meant to
+// be used to build the WithRedisClusterMode option from a database resource
+// the caller already has, since the route alone doesn't carry this.
+func IsRedisClusterMode(database types.Database) bool {
+	u, err := url.Parse(database.GetURI())
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Query().Get("mode"), "cluster")
+}
+
+// DatabaseNamePolicy computes the default database name to present to the
+// native client when the user didn't request one explicitly.
+type DatabaseNamePolicy func(protocol, databaseUser string) string
+
+// DefaultDatabaseNamePolicy is dbcmd's built-in default database name
+// policy. Postgres defaults to the database user's name, matching libpq's
+// own default and avoiding connection URLs with an empty path segment that
+// some clients reject. MySQL has no sensible default catalog, so an absent
+// one is left out of the command entirely. MongoDB defaults to "admin",
+// the database most deployments configure as their auth source.
+func DefaultDatabaseNamePolicy(protocol, databaseUser string) string {
+	switch protocol {
+	case defaults.ProtocolPostgres, defaults.ProtocolCockroachDB:
+		return databaseUser
+	case defaults.ProtocolMongoDB:
+		return "admin"
+	default:
+		return ""
+	}
+}
+
+// WithDatabaseNamePolicy is the connect command option that overrides
+// DefaultDatabaseNamePolicy, for callers that want different per-protocol
+// defaults than dbcmd's own.
+func WithDatabaseNamePolicy(policy DatabaseNamePolicy) ConnectCommandFunc {
+	return func(opts *connectionCommandOpts) {
+		opts.dbNamePolicy = policy
+	}
+}
+
+// WithSecretStore is the connect command option that tells the command
+// builder to resolve client secrets (e.g. SQL Server login password,
+// Redis AUTH token) from the given SecretStore instead of relying on
+// hard-coded placeholder values.
+func WithSecretStore(secrets SecretStore) ConnectCommandFunc {
+	return func(opts *connectionCommandOpts) {
+		opts.secrets = secrets
+	}
+}
+
+// connectionCommandOpts groups the customizable parts of the generated
+// native client command.
+type connectionCommandOpts struct {
+	localProxyPort       int
+	localProxyHost       string
+	caPath               string
+	noTLS                bool
+	noClientCert         bool
+	disableGSSAPI        bool
+	tlsMinVersion        string
+	crlPath              string
+	keyPath              string
+	certPath             string
+	keyPassphrase        string
+	secrets              SecretStore
+	dbNamePolicy         DatabaseNamePolicy
+	containerGateway     string
+	containerImages      map[string]string
+	logger               logrus.FieldLogger
+	extraArgs            map[string][]string
+	passthroughArgs      []string
+	mysqlLoginPath       bool
+	customBinaries       map[string]string
+	commandPolicy        CommandPolicy
+	pipedIO              bool
+	endpoint             string
+	mongoDBServerVersion string
+	mongoDBURIOptions    map[string]string
+	queryAuditNotice     string
+	idleTimeout          time.Duration
+	snowflakeAccount     string
+	preferredClient      string
+	redisClusterMode     bool
+	adKRB5CCachePath     string
+	postgresOptions      string
+	mysqlInitCommands    []string
+	postgresServiceFile  bool
+	mysqlOptionFilePath  string
+	execQuery            string
+}
+
+// ConnectCommandFunc customizes how CLICommandBuilder generates the
+// connect command.
+type ConnectCommandFunc func(*connectionCommandOpts)
+
+// WithLocalProxy is the connect command option that tells the database
+// client to connect to a local proxy instead of the database directly.
+func WithLocalProxy(host string, port int, caPath string) ConnectCommandFunc {
+	return func(opts *connectionCommandOpts) {
+		opts.localProxyPort = port
+		opts.localProxyHost = host
+		opts.caPath = caPath
+	}
+}
+
+// WithNoTLS is the connect command option that makes the command connect
+// without TLS.
+//
+// It is used when connecting through the local proxy that was started in
+// mutual TLS mode (i.e. with a client certificate).
+func WithNoTLS() ConnectCommandFunc {
+	return func(opts *connectionCommandOpts) {
+		opts.noTLS = true
+	}
+}
+
+// WithPipedIO is the connect command option that leaves the generated
+// command's standard streams unset instead of wiring them to the calling
+// process's own stdio. Without it (the default), the command is interactive:
+// its stdio is inherited so the native client can detect the terminal and
+// page output or prompt for input the same way it would if run directly.
+// With it, the command is meant for automation: the caller attaches its own
+// pipes (e.g. via cmd.StdinPipe()/StdoutPipe()) instead of sharing a real
+// terminal.
+//
+// Note: this does not allocate a dedicated pseudo-terminal (PTY on Unix,
+// ConPTY on Windows). Native database clients detect and use an inherited
+// terminal on their own; a real PTY would only be needed if the command were
+// run detached from the user's terminal, which "tsh db connect" never does.
+func WithPipedIO() ConnectCommandFunc {
+	return func(opts *connectionCommandOpts) {
+		opts.pipedIO = true
+	}
+}
+
+// WithNoClientCert is the connect command option that omits the client
+// certificate and key from the generated command while still requesting
+// TLS.
+//
+// It is used for database deployments (e.g. some managed Redis offerings)
+// that terminate TLS without requiring mutual TLS authentication.
+func WithNoClientCert() ConnectCommandFunc {
+	return func(opts *connectionCommandOpts) {
+		opts.noClientCert = true
+	}
+}
+
+// WithGSSAPIDisabled is the connect command option that adds
+// "gssencmode=disable" to the generated Postgres connection string so psql
+// doesn't stall negotiating GSS/SSPI encryption against proxies and servers
+// that don't support it. It has no effect on non-Postgres protocols.
+func WithGSSAPIDisabled() ConnectCommandFunc {
+	return func(opts *connectionCommandOpts) {
+		opts.disableGSSAPI = true
+	}
+}
+
+// WithADKerberosAuth is the connect command option that makes the generated
+// SQL Server command use Kerberos integrated auth ("-E") instead of
+// embedding a SQL auth fixture password, for domain-joined SQL Server
+// databases configured with Active Directory authentication. krb5CCachePath
+// is the path of the Kerberos credential cache the local proxy obtained on
+// the client's behalf; it's exported to the native client via KRB5CCNAME. It
+// has no effect on non-SQL Server protocols.
+func WithADKerberosAuth(krb5CCachePath string) ConnectCommandFunc {
+	return func(opts *connectionCommandOpts) {
+		opts.adKRB5CCachePath = krb5CCachePath
+	}
+}
+
+// WithPostgresOptions is the connect command option that carries libpq
+// startup parameters (the "options" connection parameter, e.g.
+// "-c search_path=myschema -c role=readonly") through to the generated
+// psql/pgcli command (including cockroach's psql fallback) via PGOPTIONS,
+// for orgs that require a particular search_path or a SET ROLE switch on
+// connect. The "cockroach sql" command itself gets no PGOPTIONS. It has no
+// effect on non-Postgres protocols.
+func WithPostgresOptions(options string) ConnectCommandFunc {
+	return func(opts *connectionCommandOpts) {
+		opts.postgresOptions = options
+	}
+}
+
+// WithPostgresServiceFile is the connect command option that makes the
+// generated psql command connect via "service=<name>", referencing the
+// pg_service.conf entry "tsh db login" already wrote for this database
+// (see lib/client/db.Add), instead of repeating the full connection string
+// (including cert paths) on the command line. It has no effect on
+// non-Postgres protocols, and has no effect if the caller hasn't actually
+// logged in to the database yet, since there would be no entry to
+// reference.
+func WithPostgresServiceFile() ConnectCommandFunc {
+	return func(opts *connectionCommandOpts) {
+		opts.postgresServiceFile = true
+	}
+}
+
+// WithMySQLOptionFile is the connect command option that makes the
+// generated mysql/mariadb command (Oracle client only) point at an
+// isolated option file via --defaults-extra-file, instead of the
+// --defaults-group-suffix section "tsh db login" writes to the user's
+// shared ~/.my.cnf. The file's [client] section is created (or overwritten)
+// with this connection's parameters each time the command is built. It has
+// no effect on non-MySQL protocols.
+func WithMySQLOptionFile(path string) ConnectCommandFunc {
+	return func(opts *connectionCommandOpts) {
+		opts.mysqlOptionFilePath = path
+	}
+}
+
+// WithExecQuery is the connect command option that makes the generated
+// command run query non-interactively and exit, instead of starting an
+// interactive session, using each client's batch-mode flag: "-c" for
+// psql, "--execute" for mysql/mariadb, "--eval" for mongosh/mongo, a
+// trailing positional command for redis-cli, and "-Q" for sqlcmd/
+// mssql-cli. Meant for scripting and health checks (e.g.
+// "tsh db connect --exec 'select 1'"). It has no effect on protocols
+// whose native client has no batch-query mode.
+func WithExecQuery(query string) ConnectCommandFunc {
+	return func(opts *connectionCommandOpts) {
+		opts.execQuery = query
+	}
+}
+
+// WithMySQLInitCommands is the connect command option that makes the
+// generated mysql/mariadb command run the given statements right after
+// connecting, via --init-command, for orgs that require e.g. a SET ROLE on
+// connect. It has no effect on non-MySQL protocols.
+func WithMySQLInitCommands(statements ...string) ConnectCommandFunc {
+	return func(opts *connectionCommandOpts) {
+		opts.mysqlInitCommands = statements
+	}
+}
+
+// WithTLSMinVersion is the connect command option that requests the
+// database client enforce a minimum TLS version, for clients that support
+// configuring this (e.g. "mysql --tls-version").
+func WithTLSMinVersion(version string) ConnectCommandFunc {
+	return func(opts *connectionCommandOpts) {
+		opts.tlsMinVersion = version
+	}
+}
+
+// WithCRLPath is the connect command option that points the database client
+// at a certificate revocation list to check server certificates against,
+// for clients that support revocation checking (e.g. "mysql --ssl-crl").
+func WithCRLPath(crlPath string) ConnectCommandFunc {
+	return func(opts *connectionCommandOpts) {
+		opts.crlPath = crlPath
+	}
+}
+
+// WithCAPath is the connect command option that overrides the CA
+// certificate path presented to the database client, for CAs issued by
+// external tooling (e.g. Machine ID, Vault) into a location not derived
+// from the profile layout.
+func WithCAPath(caPath string) ConnectCommandFunc {
+	return func(opts *connectionCommandOpts) {
+		opts.caPath = caPath
+	}
+}
+
+// WithEphemeralCerts is the connect command option that points the database
+// client at a key/cert pair living outside the profile directory, used when
+// the key agent is configured to never persist keys to disk.
+func WithEphemeralCerts(keyPath, certPath string) ConnectCommandFunc {
+	return func(opts *connectionCommandOpts) {
+		opts.keyPath = keyPath
+		opts.certPath = certPath
+	}
+}
+
+// WithContainerGateway is the connect command option that rewrites the local
+// proxy address from "localhost"/"127.0.0.1" to gateway, for generated
+// commands that are meant to be run by a database client inside a container
+// (Docker, Kubernetes) while the local proxy keeps listening on the host.
+// Typical values are "host.docker.internal" or the host's routable IP on the
+// container network.
+//
+// Cert, key and CA paths in the generated command still point at their
+// on-host locations; the caller is responsible for bind-mounting them into
+// the container at the same paths (e.g. via "docker run -v").
+func WithContainerGateway(gateway string) ConnectCommandFunc {
+	return func(opts *connectionCommandOpts) {
+		opts.containerGateway = gateway
+	}
+}
+
+// WithContainerImages is the connect command option that records the
+// admin-approved container image to use for each database protocol when a
+// caller runs the generated command inside a container rather than
+// executing it on the host (see WithContainerGateway). Images should be
+// pinned by digest (e.g. "postgres:15@sha256:...") so the container
+// fallback mode stays usable under supply-chain policies that forbid
+// floating tags. dbcmd never launches containers itself; callers read the
+// configured image back via CLICommandBuilder.ContainerImage.
+func WithContainerImages(images map[string]string) ConnectCommandFunc {
+	return func(opts *connectionCommandOpts) {
+		opts.containerImages = images
+	}
+}
+
+// WithEndpoint is the connect command option that selects an alternate
+// database endpoint (e.g. a reader endpoint) for the generated command to
+// target, enabling reader-only connections for callers such as analysts who
+// shouldn't write to the primary. dbcmd doesn't itself know how to route to
+// different endpoints; for protocols that support surfacing a client-side
+// annotation (currently Postgres' "application_name"), it threads the
+// chosen name through so it's visible to the server and to observability
+// tooling. Callers read the selection back via CLICommandBuilder.Endpoint.
+func WithEndpoint(endpoint string) ConnectCommandFunc {
+	return func(opts *connectionCommandOpts) {
+		opts.endpoint = endpoint
+	}
+}
+
+// WithMongoDBServerVersion is the connect command option that tells the
+// builder the target MongoDB server's version, when the caller already has
+// it from the database's metadata. It's used to refuse falling back to the
+// deprecated legacy "mongo" shell when "mongosh" isn't installed and the
+// server is new enough to have dropped support for it, producing a clear
+// error instead of a confusing connection failure. It has no effect on
+// non-MongoDB protocols, or when left empty (the caller doesn't have the
+// server version available).
+func WithMongoDBServerVersion(version string) ConnectCommandFunc {
+	return func(opts *connectionCommandOpts) {
+		opts.mongoDBServerVersion = version
+	}
+}
+
+// WithMongoDBURIOptions is the connect command option that adds extra query
+// parameters to the URI GetConnectionURI builds, for MongoDB connection
+// options that have no equivalent mongosh/mongo flag, e.g. replicaSet or
+// readPreference. Each key/value pair is added as-is; a key also set by
+// GetConnectionURI itself (e.g. "tls") is overridden. It has no effect on
+// GetConnectCommand or on non-MongoDB protocols.
+func WithMongoDBURIOptions(options map[string]string) ConnectCommandFunc {
+	return func(opts *connectionCommandOpts) {
+		opts.mongoDBURIOptions = options
+	}
+}
+
+// WithRedisClusterMode is the connect command option that tells the builder
+// the target Redis database runs in cluster mode, when the caller already
+// has that from the database resource's metadata (the route alone doesn't
+// carry it). redis-cli needs to know this up front, via -c, in order to
+// follow MOVED/ASK redirects itself instead of returning them as errors. It
+// has no effect on non-Redis protocols.
+func WithRedisClusterMode(clusterMode bool) ConnectCommandFunc {
+	return func(opts *connectionCommandOpts) {
+		opts.redisClusterMode = clusterMode
+	}
+}
+
+// WithQueryAuditNotice is the connect command option that tells the builder
+// query-level auditing is enabled for this database, so the generated
+// command should make that visible to the user for the life of the session.
+// Today this is wired up for psql only, via a persistent --set PROMPT1
+// prefix: it's the client named in the original ask, and most other native
+// clients (mysql, mongosh, etc.) don't expose an equivalent customizable,
+// always-visible prompt to hang a notice off of. notice is the text shown;
+// it has no effect when empty.
+//
+// No caller in this tree invokes this option yet: whether query-level
+// auditing is enabled is role-driven, and the current role schema
+// (RecordSession) has no field for it, only Desktop. Wiring this up end to
+// end requires that field to exist first.
+func WithQueryAuditNotice(notice string) ConnectCommandFunc {
+	return func(opts *connectionCommandOpts) {
+		opts.queryAuditNotice = notice
+	}
+}
+
+// WithIdleTimeout is the connect command option that tells the builder to
+// configure the native client to disconnect an idle session itself, ahead
+// of the server-side idle timeout cluster policy would otherwise enforce.
+// Ending the session client-side lets the client exit gracefully (e.g. psql
+// printing a message and returning to the shell) instead of the server
+// abruptly dropping the connection out from under it. It's wired up for
+// psql/pgcli, including cockroach's psql fallback (via the PGOPTIONS
+// idle_session_timeout GUC), and mysql/mariadb (via an --init-command
+// setting the wait_timeout session variable); it has no effect on the
+// "cockroach sql" command, on other protocols, or when timeout is zero.
+func WithIdleTimeout(timeout time.Duration) ConnectCommandFunc {
+	return func(opts *connectionCommandOpts) {
+		opts.idleTimeout = timeout
+	}
+}
+
+// WithSnowflakeAccount is the connect command option that tells snowsql
+// which Snowflake account to authenticate against. Teleport's database
+// route only carries the proxy's service name and a default
+// username/database (see tlsca.RouteToDatabase), not the upstream
+// account identifier snowsql needs for its login request, so callers that
+// know it (e.g. it was set on the database resource's connection URI) pass
+// it through here. Without it, getSnowflakeCommand falls back to the
+// database's service name, which only happens to be correct if the
+// service was named after its Snowflake account.
+func WithSnowflakeAccount(account string) ConnectCommandFunc {
+	return func(opts *connectionCommandOpts) {
+		opts.snowflakeAccount = account
+	}
+}
+
+// WithPreferredClient is the connect command option that requests a
+// specific CLI client binary (e.g. "mysqlsh") for protocols that support
+// more than one, instead of the builder's normal auto-detection order.
+// GetConnectCommand returns a trace.NotFound error if the requested binary
+// isn't on the PATH.
+func WithPreferredClient(name string) ConnectCommandFunc {
+	return func(opts *connectionCommandOpts) {
+		opts.preferredClient = name
+	}
+}
+
+// WithLogger is the connect command option that routes the builder's debug
+// logging (which binary was picked, which fallbacks were tried, which files
+// were written) through logger instead of the package-wide default, so
+// callers can tag the output with their own request context.
+func WithLogger(logger logrus.FieldLogger) ConnectCommandFunc {
+	return func(opts *connectionCommandOpts) {
+		opts.logger = logger
+	}
+}
+
+// WithExtraArgs is the connect command option that appends admin-configured
+// extra command-line flags to the generated command, keyed by database
+// protocol (e.g. defaults.ProtocolPostgres). It lets organizations enforce
+// client conventions (a pager, a custom prompt, etc.) without distributing
+// wrapper scripts around the native client.
+func WithExtraArgs(extraArgs map[string][]string) ConnectCommandFunc {
+	return func(opts *connectionCommandOpts) {
+		opts.extraArgs = extraArgs
+	}
+}
+
+// WithPassthroughArgs is the connect command option that appends
+// user-supplied command-line arguments to the generated command, e.g. for
+// "tsh db connect mydb -- -v FOO=bar". Unlike WithExtraArgs, which is
+// admin-configured and keyed by protocol, these come from a single
+// invocation and are inserted ahead of any trailing positional argument the
+// client expects (e.g. the database name mongosh takes as its last
+// argument) rather than simply appended at the end.
+func WithPassthroughArgs(args []string) ConnectCommandFunc {
+	return func(opts *connectionCommandOpts) {
+		opts.passthroughArgs = args
+	}
+}
+
+// WithMySQLLoginPath is the connect command option that stores password-auth
+// MySQL/MariaDB credentials in an encrypted mysql_config_editor login-path
+// entry and points the generated command at it via --login-path, instead of
+// the default plaintext [client] credential file passed via
+// --defaults-extra-file. Requires mysql_config_editor to be installed; it
+// ships with the MySQL/MariaDB client packages on most platforms.
+func WithMySQLLoginPath() ConnectCommandFunc {
+	return func(opts *connectionCommandOpts) {
+		opts.mysqlLoginPath = true
+	}
+}
+
+// WithCustomBinary is the connect command option that overrides the client
+// executable used for protocol (e.g. defaults.ProtocolMySQL) with path,
+// skipping the usual PATH lookup and, where applicable, client-flavor
+// version detection (mysql vs mariadb, mongosh vs mongo, etc.) for that
+// protocol. It's for users who keep clients in non-PATH locations or want
+// to pin a specific client version. Can be called once per protocol.
+func WithCustomBinary(protocol, path string) ConnectCommandFunc {
+	return func(opts *connectionCommandOpts) {
+		if opts.customBinaries == nil {
+			opts.customBinaries = make(map[string]string)
+		}
+		opts.customBinaries[protocol] = path
+	}
+}
+
+// CommandPolicy validates a generated native client command before it is
+// handed back to the caller. Enterprises can use it to restrict which
+// client binaries or flags dbcmd may emit (e.g. forbidding --no-ssl style
+// overrides) to keep endpoint tooling compliant. A non-nil error fails
+// command generation with a policy violation.
+type CommandPolicy func(cmd *exec.Cmd) error
+
+// WithCommandPolicy is the connect command option that validates the
+// generated command against policy before returning it from
+// GetConnectCommand.
+func WithCommandPolicy(policy CommandPolicy) ConnectCommandFunc {
+	return func(opts *connectionCommandOpts) {
+		opts.commandPolicy = policy
+	}
+}
+
+// WithKeyPassphrase is the connect command option that tells the database
+// client the private key is passphrase-protected, for clients that accept
+// it as a connection parameter (e.g. psql's sslpassword, mongosh's
+// tlsCertificateKeyFilePassword).
+func WithKeyPassphrase(passphrase string) ConnectCommandFunc {
+	return func(opts *connectionCommandOpts) {
+		opts.keyPassphrase = passphrase
+	}
+}
@@ -0,0 +1,2809 @@
+/*
+
+ Copyright 2022 Gravitational, Inc.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+
+*/
+
+package dbcmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gravitational/teleport/api/types"
+	"github.com/gravitational/teleport/lib/client"
+	"github.com/gravitational/teleport/lib/client/db/mysql"
+	"github.com/gravitational/teleport/lib/defaults"
+	"github.com/gravitational/teleport/lib/fixtures"
+	"github.com/gravitational/teleport/lib/tlsca"
+	"github.com/gravitational/teleport/lib/utils"
+
+	"github.com/gravitational/trace"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeExec implements the Execer interface for mocking purposes.
+type fakeExec struct {
+	// execOutput maps binary name and output that should be returned on RunCommand().
+	// Map is also being used to check if a binary exist. Command line args are not supported.
+	execOutput map[string][]byte
+}
+
+func (f fakeExec) RunCommand(cmd string, _ ...string) ([]byte, error) {
+	out, found := f.execOutput[cmd]
+	if !found {
+		return nil, errors.New("binary not found")
+	}
+
+	return out, nil
+}
+
+func (f fakeExec) LookPath(path string) (string, error) {
+	if _, found := f.execOutput[path]; found {
+		return "", nil
+	}
+	return "", trace.NotFound("not found")
+}
+
+// writeFakeDatabaseCredentials creates the private key, database certificate
+// and CA bundle files a CLICommandBuilder for the given profile expects to
+// find on disk, matching the layout the tests in this file assume
+// (profile "example.com", user "bob", database service "mysql", root
+// cluster "root").
+func writeFakeDatabaseCredentials(t *testing.T, profile *client.ProfileStatus) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(profile.Dir, "keys", profile.Name, "cas"), 0700))
+	require.NoError(t, os.MkdirAll(filepath.Join(profile.Dir, "keys", profile.Name, profile.Username+"-db", "db.example.com"), 0700))
+
+	require.NoError(t, os.WriteFile(filepath.Join(profile.Dir, "keys", profile.Name, profile.Username), []byte(fixtures.TLSCAKeyPEM), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(profile.Dir, "keys", profile.Name, "cas", "root.pem"), []byte(fixtures.TLSCACertPEM), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(profile.Dir, "keys", profile.Name, profile.Username+"-db", "db.example.com", "mysql-x509.pem"), []byte(fixtures.TLSCACertPEM), 0600))
+}
+
+func TestCLICommandBuilderGetConnectCommand(t *testing.T) {
+	tc, err := client.NewClient(&client.Config{
+		WebProxyAddr: "proxy:3080",
+		SiteName:     "db.example.com",
+		Username:     "bob",
+		HostLogin:    "bob",
+		KeysDir:      t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	profile := &client.ProfileStatus{
+		Name:     "example.com",
+		Username: "bob",
+		Dir:      "/tmp",
+	}
+	writeFakeDatabaseCredentials(t, profile)
+
+	tests := []struct {
+		name             string
+		dbProtocol       string
+		databaseName     string
+		execer           *fakeExec
+		cmd              []string
+		noTLS            bool
+		noClientCert     bool
+		preferredClient  string
+		redisClusterMode bool
+		wantErr          bool
+	}{
+		{
+			name:         "postgres",
+			dbProtocol:   defaults.ProtocolPostgres,
+			databaseName: "mydb",
+			execer: &fakeExec{
+				execOutput: map[string][]byte{
+					"psql": []byte(""),
+				},
+			},
+			cmd: []string{"psql",
+				"postgres://myUser@localhost:12345/mydb?sslrootcert=/tmp/keys/example.com/cas/root.pem&" +
+					"sslcert=/tmp/keys/example.com/bob-db/db.example.com/mysql-x509.pem&" +
+					"sslkey=/tmp/keys/example.com/bob&sslmode=verify-full"},
+			wantErr: false,
+		},
+		{
+			name:         "postgres no TLS",
+			dbProtocol:   defaults.ProtocolPostgres,
+			databaseName: "mydb",
+			noTLS:        true,
+			execer: &fakeExec{
+				execOutput: map[string][]byte{
+					"psql": []byte(""),
+				},
+			},
+			cmd: []string{"psql",
+				"postgres://myUser@localhost:12345/mydb"},
+			wantErr: false,
+		},
+		{
+			name:         "postgres usql fallback",
+			dbProtocol:   defaults.ProtocolPostgres,
+			databaseName: "mydb",
+			noTLS:        true,
+			execer: &fakeExec{
+				execOutput: map[string][]byte{
+					"usql": []byte(""),
+				},
+			},
+			cmd: []string{"usql",
+				"postgres://myUser@localhost:12345/mydb"},
+			wantErr: false,
+		},
+		{
+			name:         "postgres neither psql nor usql found",
+			dbProtocol:   defaults.ProtocolPostgres,
+			databaseName: "mydb",
+			noTLS:        true,
+			execer:       &fakeExec{},
+			cmd:          []string{},
+			wantErr:      true,
+		},
+		{
+			name:         "cockroach",
+			dbProtocol:   defaults.ProtocolCockroachDB,
+			databaseName: "mydb",
+			execer: &fakeExec{
+				execOutput: map[string][]byte{
+					"cockroach": []byte(""),
+				},
+			},
+			cmd: []string{"cockroach", "sql", "--url",
+				"postgres://myUser@localhost:12345/mydb?sslrootcert=/tmp/keys/example.com/cas/root.pem&" +
+					"sslcert=/tmp/keys/example.com/bob-db/db.example.com/mysql-x509.pem&" +
+					"sslkey=/tmp/keys/example.com/bob&sslmode=verify-full"},
+			wantErr: false,
+		},
+		{
+			name:         "cockroach no TLS",
+			dbProtocol:   defaults.ProtocolCockroachDB,
+			databaseName: "mydb",
+			noTLS:        true,
+			execer: &fakeExec{
+				execOutput: map[string][]byte{
+					"cockroach": []byte(""),
+				},
+			},
+			cmd: []string{"cockroach", "sql", "--url",
+				"postgres://myUser@localhost:12345/mydb"},
+			wantErr: false,
+		},
+		{
+			name:         "cockroach psql fallback",
+			dbProtocol:   defaults.ProtocolCockroachDB,
+			databaseName: "mydb",
+			execer:       &fakeExec{},
+			cmd: []string{"psql",
+				"postgres://myUser@localhost:12345/mydb?sslrootcert=/tmp/keys/example.com/cas/root.pem&" +
+					"sslcert=/tmp/keys/example.com/bob-db/db.example.com/mysql-x509.pem&" +
+					"sslkey=/tmp/keys/example.com/bob&sslmode=verify-full"},
+			wantErr: false,
+		},
+		{
+			name:            "postgres pgcli preferred",
+			dbProtocol:      defaults.ProtocolPostgres,
+			databaseName:    "mydb",
+			preferredClient: pgcliBin,
+			execer: &fakeExec{
+				execOutput: map[string][]byte{
+					"pgcli": []byte(""),
+				},
+			},
+			cmd: []string{"pgcli",
+				"postgres://myUser@localhost:12345/mydb?sslrootcert=/tmp/keys/example.com/cas/root.pem&" +
+					"sslcert=/tmp/keys/example.com/bob-db/db.example.com/mysql-x509.pem&" +
+					"sslkey=/tmp/keys/example.com/bob&sslmode=verify-full"},
+			wantErr: false,
+		},
+		{
+			name:            "postgres pgcli preferred but missing",
+			dbProtocol:      defaults.ProtocolPostgres,
+			databaseName:    "mydb",
+			preferredClient: pgcliBin,
+			execer:          &fakeExec{},
+			cmd:             []string{},
+			wantErr:         true,
+		},
+		{
+			name:            "cockroach pgcli preferred",
+			dbProtocol:      defaults.ProtocolCockroachDB,
+			databaseName:    "mydb",
+			preferredClient: pgcliBin,
+			execer: &fakeExec{
+				execOutput: map[string][]byte{
+					"cockroach": []byte(""),
+					"pgcli":     []byte(""),
+				},
+			},
+			cmd: []string{"pgcli",
+				"postgres://myUser@localhost:12345/mydb?sslrootcert=/tmp/keys/example.com/cas/root.pem&" +
+					"sslcert=/tmp/keys/example.com/bob-db/db.example.com/mysql-x509.pem&" +
+					"sslkey=/tmp/keys/example.com/bob&sslmode=verify-full"},
+			wantErr: false,
+		},
+		{
+			name:         "mariadb",
+			dbProtocol:   defaults.ProtocolMySQL,
+			databaseName: "mydb",
+			execer: &fakeExec{
+				execOutput: map[string][]byte{
+					"mariadb": []byte(""),
+				},
+			},
+			cmd: []string{"mariadb",
+				"--user", "myUser",
+				"--database", "mydb",
+				"--port", "12345",
+				"--host", "localhost",
+				"--protocol", "TCP",
+				"--ssl-key", "/tmp/keys/example.com/bob",
+				"--ssl-ca", "/tmp/keys/example.com/cas/root.pem",
+				"--ssl-cert", "/tmp/keys/example.com/bob-db/db.example.com/mysql-x509.pem",
+				"--ssl-verify-server-cert"},
+			wantErr: false,
+		},
+		{
+			name:         "mariadb no TLS",
+			dbProtocol:   defaults.ProtocolMySQL,
+			databaseName: "mydb",
+			noTLS:        true,
+			execer: &fakeExec{
+				execOutput: map[string][]byte{
+					"mariadb": []byte(""),
+				},
+			},
+			cmd: []string{"mariadb",
+				"--user", "myUser",
+				"--database", "mydb",
+				"--port", "12345",
+				"--host", "localhost",
+				"--protocol", "TCP"},
+			wantErr: false,
+		},
+		{
+			name:         "mysql by mariadb",
+			dbProtocol:   defaults.ProtocolMySQL,
+			databaseName: "mydb",
+			execer: &fakeExec{
+				execOutput: map[string][]byte{
+					"mysql": []byte("mysql  Ver 15.1 Distrib 10.3.32-MariaDB, for debian-linux-gnu (x86_64) using readline 5.2"),
+				},
+			},
+			cmd: []string{"mysql",
+				"--user", "myUser",
+				"--database", "mydb",
+				"--port", "12345",
+				"--host", "localhost",
+				"--protocol", "TCP",
+				"--ssl-key", "/tmp/keys/example.com/bob",
+				"--ssl-ca", "/tmp/keys/example.com/cas/root.pem",
+				"--ssl-cert", "/tmp/keys/example.com/bob-db/db.example.com/mysql-x509.pem",
+				"--ssl-verify-server-cert"},
+			wantErr: false,
+		},
+		{
+			name:         "mysql by oracle",
+			dbProtocol:   defaults.ProtocolMySQL,
+			databaseName: "mydb",
+			execer: &fakeExec{
+				execOutput: map[string][]byte{
+					"mysql": []byte("Ver 8.0.27-0ubuntu0.20.04.1 for Linux on x86_64 ((Ubuntu))"),
+				},
+			},
+			cmd: []string{"mysql",
+				"--defaults-group-suffix=_db.example.com-mysql",
+				"--user", "myUser",
+				"--database", "mydb",
+				"--port", "12345",
+				"--host", "localhost",
+				"--protocol", "TCP"},
+			wantErr: false,
+		},
+		{
+			name:         "mysql no TLS",
+			dbProtocol:   defaults.ProtocolMySQL,
+			databaseName: "mydb",
+			noTLS:        true,
+			execer: &fakeExec{
+				execOutput: map[string][]byte{
+					"mysql": []byte("Ver 8.0.27-0ubuntu0.20.04.1 for Linux on x86_64 ((Ubuntu))"),
+				},
+			},
+			cmd: []string{"mysql",
+				"--user", "myUser",
+				"--database", "mydb",
+				"--port", "12345",
+				"--host", "localhost",
+				"--protocol", "TCP"},
+			wantErr: false,
+		},
+		{
+			name:         "no mysql nor mariadb",
+			dbProtocol:   defaults.ProtocolMySQL,
+			databaseName: "mydb",
+			execer: &fakeExec{
+				execOutput: map[string][]byte{},
+			},
+			cmd:     []string{},
+			wantErr: true,
+		},
+		{
+			name:         "mysql usql fallback",
+			dbProtocol:   defaults.ProtocolMySQL,
+			databaseName: "mydb",
+			execer: &fakeExec{
+				execOutput: map[string][]byte{
+					"usql": []byte(""),
+				},
+			},
+			cmd:     []string{"usql", "mysql://myUser@localhost:12345/mydb"},
+			wantErr: false,
+		},
+		{
+			name:            "mysqlsh preferred",
+			dbProtocol:      defaults.ProtocolMySQL,
+			databaseName:    "mydb",
+			preferredClient: mysqlshBin,
+			execer: &fakeExec{
+				execOutput: map[string][]byte{
+					"mariadb": []byte(""),
+					"mysqlsh": []byte(""),
+				},
+			},
+			cmd: []string{"mysqlsh",
+				"--sql",
+				"--user", "myUser",
+				"--schema", "mydb",
+				"--port", "12345",
+				"--host", "localhost",
+				"--ssl-key", "/tmp/keys/example.com/bob",
+				"--ssl-cert", "/tmp/keys/example.com/bob-db/db.example.com/mysql-x509.pem",
+				"--ssl-ca", "/tmp/keys/example.com/cas/root.pem",
+				"--ssl-mode=VERIFY_CA"},
+			wantErr: false,
+		},
+		{
+			name:            "mysqlsh preferred but missing",
+			dbProtocol:      defaults.ProtocolMySQL,
+			databaseName:    "mydb",
+			preferredClient: mysqlshBin,
+			execer: &fakeExec{
+				execOutput: map[string][]byte{
+					"mariadb": []byte(""),
+				},
+			},
+			cmd:     []string{},
+			wantErr: true,
+		},
+		{
+			name:         "mysqlsh fallback",
+			dbProtocol:   defaults.ProtocolMySQL,
+			databaseName: "mydb",
+			noTLS:        true,
+			execer: &fakeExec{
+				execOutput: map[string][]byte{
+					"mysqlsh": []byte(""),
+				},
+			},
+			cmd: []string{"mysqlsh",
+				"--sql",
+				"--user", "myUser",
+				"--schema", "mydb",
+				"--port", "12345",
+				"--host", "localhost"},
+			wantErr: false,
+		},
+		{
+			name:            "mycli preferred",
+			dbProtocol:      defaults.ProtocolMySQL,
+			databaseName:    "mydb",
+			preferredClient: mycliBin,
+			execer: &fakeExec{
+				execOutput: map[string][]byte{
+					"mariadb": []byte(""),
+					"mycli":   []byte(""),
+				},
+			},
+			cmd: []string{"mycli",
+				"--user", "myUser",
+				"--database", "mydb",
+				"--port", "12345",
+				"--host", "localhost",
+				"--ssl-ca", "/tmp/keys/example.com/cas/root.pem",
+				"--ssl-cert", "/tmp/keys/example.com/bob-db/db.example.com/mysql-x509.pem",
+				"--ssl-key", "/tmp/keys/example.com/bob"},
+			wantErr: false,
+		},
+		{
+			name:            "mycli preferred but missing",
+			dbProtocol:      defaults.ProtocolMySQL,
+			databaseName:    "mydb",
+			preferredClient: mycliBin,
+			execer: &fakeExec{
+				execOutput: map[string][]byte{
+					"mariadb": []byte(""),
+				},
+			},
+			cmd:     []string{},
+			wantErr: true,
+		},
+		{
+			name:         "mycli not auto-selected",
+			dbProtocol:   defaults.ProtocolMySQL,
+			databaseName: "mydb",
+			execer: &fakeExec{
+				execOutput: map[string][]byte{
+					"mycli": []byte(""),
+				},
+			},
+			cmd:     []string{},
+			wantErr: true,
+		},
+		{
+			name:         "mongodb (legacy)",
+			dbProtocol:   defaults.ProtocolMongoDB,
+			databaseName: "mydb",
+			execer: &fakeExec{
+				execOutput: map[string][]byte{},
+			},
+			cmd: []string{"mongo",
+				"--host", "localhost",
+				"--port", "12345",
+				"--ssl",
+				"--sslPEMKeyFile", "/tmp/keys/example.com/bob-db/db.example.com/mysql-x509.pem",
+				"mydb"},
+			wantErr: false,
+		},
+		{
+			name:         "mongodb no TLS",
+			dbProtocol:   defaults.ProtocolMongoDB,
+			databaseName: "mydb",
+			noTLS:        true,
+			execer: &fakeExec{
+				execOutput: map[string][]byte{},
+			},
+			cmd: []string{"mongo",
+				"--host", "localhost",
+				"--port", "12345",
+				"mydb"},
+			wantErr: false,
+		},
+		{
+			name:         "mongosh",
+			dbProtocol:   defaults.ProtocolMongoDB,
+			databaseName: "mydb",
+			execer: &fakeExec{
+				execOutput: map[string][]byte{
+					"mongosh": []byte("1.1.6"),
+				},
+			},
+			cmd: []string{"mongosh",
+				"--host", "localhost",
+				"--port", "12345",
+				"--tls",
+				"--tlsCertificateKeyFile", "/tmp/keys/example.com/bob-db/db.example.com/mysql-x509.pem",
+				"--tlsCAFile", "/tmp/keys/example.com/cas/root.pem",
+				"mydb"},
+		},
+		{
+			name:         "mongosh no TLS",
+			dbProtocol:   defaults.ProtocolMongoDB,
+			databaseName: "mydb",
+			noTLS:        true,
+			execer: &fakeExec{
+				execOutput: map[string][]byte{
+					"mongosh": []byte("1.1.6"),
+				},
+			},
+			cmd: []string{"mongosh",
+				"--host", "localhost",
+				"--port", "12345",
+				"mydb"},
+		},
+		{
+			name:         "sqlserver sqlcmd preferred",
+			dbProtocol:   defaults.ProtocolSQLServer,
+			databaseName: "mydb",
+			execer: &fakeExec{
+				execOutput: map[string][]byte{
+					sqlcmdBin: []byte(""),
+					mssqlBin:  []byte(""),
+				},
+			},
+			cmd: []string{sqlcmdBin,
+				"-S", "localhost,12345",
+				"-U", "myUser",
+				"-P", fixtures.UUID,
+				"-d", "mydb",
+			},
+			wantErr: false,
+		},
+		{
+			name:         "sqlserver",
+			dbProtocol:   defaults.ProtocolSQLServer,
+			databaseName: "mydb",
+			execer: &fakeExec{
+				execOutput: map[string][]byte{
+					mssqlBin: []byte(""),
+				},
+			},
+			cmd: []string{mssqlBin,
+				"-S", "localhost,12345",
+				"-U", "myUser",
+				"-P", fixtures.UUID,
+				"-d", "mydb",
+			},
+			wantErr: false,
+		},
+		{
+			name:         "sqlserver usql fallback",
+			dbProtocol:   defaults.ProtocolSQLServer,
+			databaseName: "mydb",
+			execer: &fakeExec{
+				execOutput: map[string][]byte{
+					"usql": []byte(""),
+				},
+			},
+			cmd: []string{"usql",
+				fmt.Sprintf("sqlserver://myUser:%s@localhost:12345?database=mydb", fixtures.UUID),
+			},
+			wantErr: false,
+		},
+		{
+			name:         "sqlserver neither mssql-cli nor usql found",
+			dbProtocol:   defaults.ProtocolSQLServer,
+			databaseName: "mydb",
+			execer:       &fakeExec{},
+			cmd:          []string{},
+			wantErr:      true,
+		},
+		{
+			name:         "cassandra",
+			dbProtocol:   defaults.ProtocolCassandra,
+			databaseName: "mydb",
+			cmd: []string{cqlshBin,
+				"localhost", "12345",
+				"-u", "myUser",
+				"--ssl"},
+			wantErr: false,
+		},
+		{
+			name:         "cassandra no TLS",
+			dbProtocol:   defaults.ProtocolCassandra,
+			databaseName: "mydb",
+			noTLS:        true,
+			cmd: []string{cqlshBin,
+				"localhost", "12345",
+				"-u", "myUser"},
+			wantErr: false,
+		},
+		{
+			name:         "elasticsearch-sql-cli",
+			dbProtocol:   defaults.ProtocolElasticsearch,
+			databaseName: "mydb",
+			execer: &fakeExec{
+				execOutput: map[string][]byte{
+					"elasticsearch-sql-cli": []byte(""),
+				},
+			},
+			cmd:     []string{elasticsearchSQLCliBin, "https://localhost:12345/"},
+			wantErr: false,
+		},
+		{
+			name:         "elasticsearch curl fallback",
+			dbProtocol:   defaults.ProtocolElasticsearch,
+			databaseName: "mydb",
+			execer: &fakeExec{
+				execOutput: map[string][]byte{
+					"curl": []byte(""),
+				},
+			},
+			cmd: []string{curlBin, "https://localhost:12345/",
+				"--cert", "/tmp/keys/example.com/bob-db/db.example.com/mysql-x509.pem",
+				"--key", "/tmp/keys/example.com/bob"},
+			wantErr: false,
+		},
+		{
+			name:         "elasticsearch neither client found",
+			dbProtocol:   defaults.ProtocolElasticsearch,
+			databaseName: "mydb",
+			execer:       &fakeExec{execOutput: map[string][]byte{}},
+			cmd:          []string{},
+			wantErr:      true,
+		},
+		{
+			name:         "oracle sqlcl",
+			dbProtocol:   defaults.ProtocolOracle,
+			databaseName: "mydb",
+			execer: &fakeExec{
+				execOutput: map[string][]byte{
+					"sql": []byte(""),
+				},
+			},
+			cmd:     []string{sqlclBin, "myUser@tcps://localhost:12345/mydb"},
+			wantErr: false,
+		},
+		{
+			name:         "oracle sqlplus fallback",
+			dbProtocol:   defaults.ProtocolOracle,
+			databaseName: "mydb",
+			execer:       &fakeExec{execOutput: map[string][]byte{}},
+			cmd:          []string{sqlplusBin, "myUser@tcps://localhost:12345/mydb"},
+			wantErr:      false,
+		},
+		{
+			name:         "oracle no TLS",
+			dbProtocol:   defaults.ProtocolOracle,
+			databaseName: "mydb",
+			noTLS:        true,
+			execer:       &fakeExec{execOutput: map[string][]byte{}},
+			cmd:          []string{sqlplusBin, "myUser@tcps://localhost:12345/mydb"},
+			wantErr:      false,
+		},
+		{
+			name:         "clickhouse native",
+			dbProtocol:   defaults.ProtocolClickHouse,
+			databaseName: "mydb",
+			execer: &fakeExec{
+				execOutput: map[string][]byte{
+					"clickhouse-client": []byte(""),
+				},
+			},
+			cmd: []string{clickhouseBin,
+				"--host", "localhost",
+				"--port", "12345",
+				"--user", "myUser",
+				"--database", "mydb",
+				"--secure"},
+			wantErr: false,
+		},
+		{
+			name:         "clickhouse native no TLS",
+			dbProtocol:   defaults.ProtocolClickHouse,
+			databaseName: "mydb",
+			noTLS:        true,
+			execer: &fakeExec{
+				execOutput: map[string][]byte{
+					"clickhouse-client": []byte(""),
+				},
+			},
+			cmd: []string{clickhouseBin,
+				"--host", "localhost",
+				"--port", "12345",
+				"--user", "myUser",
+				"--database", "mydb"},
+			wantErr: false,
+		},
+		{
+			name:         "clickhouse native missing client",
+			dbProtocol:   defaults.ProtocolClickHouse,
+			databaseName: "mydb",
+			execer:       &fakeExec{execOutput: map[string][]byte{}},
+			cmd:          []string{},
+			wantErr:      true,
+		},
+		{
+			name:         "clickhouse http",
+			dbProtocol:   defaults.ProtocolClickHouseHTTP,
+			databaseName: "mydb",
+			execer: &fakeExec{
+				execOutput: map[string][]byte{
+					"clickhouse-client": []byte(""),
+				},
+			},
+			cmd: []string{clickhouseBin,
+				"--host", "localhost",
+				"--port", "12345",
+				"--user", "myUser",
+				"--database", "mydb",
+				"--protocol", "http",
+				"--secure"},
+			wantErr: false,
+		},
+		{
+			name:         "clickhouse http curl fallback",
+			dbProtocol:   defaults.ProtocolClickHouseHTTP,
+			databaseName: "mydb",
+			execer: &fakeExec{
+				execOutput: map[string][]byte{
+					"curl": []byte(""),
+				},
+			},
+			cmd: []string{curlBin,
+				"--cert", "/tmp/keys/example.com/bob-db/db.example.com/mysql-x509.pem",
+				"--key", "/tmp/keys/example.com/bob",
+				"https://localhost:12345/"},
+			wantErr: false,
+		},
+		{
+			name:         "clickhouse http neither client found",
+			dbProtocol:   defaults.ProtocolClickHouseHTTP,
+			databaseName: "mydb",
+			execer:       &fakeExec{execOutput: map[string][]byte{}},
+			cmd:          []string{},
+			wantErr:      true,
+		},
+		{
+			name:         "snowflake",
+			dbProtocol:   defaults.ProtocolSnowflake,
+			databaseName: "mydb",
+			cmd: []string{snowsqlBin,
+				"--accountname", "mysql",
+				"--host", "localhost",
+				"--port", "12345",
+				"--username", "myUser",
+				"--dbname", "mydb"},
+			wantErr: false,
+		},
+		{
+			name:         "dynamodb",
+			dbProtocol:   defaults.ProtocolDynamoDB,
+			databaseName: "mydb",
+			execer: &fakeExec{
+				execOutput: map[string][]byte{
+					"aws": []byte(""),
+				},
+			},
+			cmd: []string{awsBin,
+				"dynamodb", "list-tables",
+				"--endpoint-url", "https://localhost:12345"},
+			wantErr: false,
+		},
+		{
+			name:       "dynamodb missing client",
+			dbProtocol: defaults.ProtocolDynamoDB,
+			execer:     &fakeExec{execOutput: map[string][]byte{}},
+			cmd:        []string{},
+			wantErr:    true,
+		},
+		{
+			name:       "neo4j",
+			dbProtocol: defaults.ProtocolNeo4j,
+			cmd: []string{cypherShellBin,
+				"-a", "neo4j+s://localhost:12345",
+				"-u", "myUser"},
+			wantErr: false,
+		},
+		{
+			name:       "neo4j no TLS",
+			dbProtocol: defaults.ProtocolNeo4j,
+			noTLS:      true,
+			cmd: []string{cypherShellBin,
+				"-a", "neo4j://localhost:12345",
+				"-u", "myUser"},
+			wantErr: false,
+		},
+		{
+			name:       "redis-cli",
+			dbProtocol: defaults.ProtocolRedis,
+			execer:     &fakeExec{},
+			cmd: []string{"redis-cli",
+				"-h", "localhost",
+				"-p", "12345",
+				"--tls",
+				"--key", "/tmp/keys/example.com/bob",
+				"--cert", "/tmp/keys/example.com/bob-db/db.example.com/mysql-x509.pem",
+				"--user", "myUser"},
+			wantErr: false,
+		},
+		{
+			name:         "redis-cli with db",
+			dbProtocol:   defaults.ProtocolRedis,
+			databaseName: "2",
+			execer:       &fakeExec{},
+			cmd: []string{"redis-cli",
+				"-h", "localhost",
+				"-p", "12345",
+				"--tls",
+				"--key", "/tmp/keys/example.com/bob",
+				"--cert", "/tmp/keys/example.com/bob-db/db.example.com/mysql-x509.pem",
+				"-n", "2",
+				"--user", "myUser"},
+			wantErr: false,
+		},
+		{
+			name:       "redis-cli no TLS",
+			dbProtocol: defaults.ProtocolRedis,
+			noTLS:      true,
+			cmd: []string{"redis-cli",
+				"-h", "localhost",
+				"-p", "12345",
+				"--user", "myUser"},
+			wantErr: false,
+		},
+		{
+			name:             "redis-cli cluster mode",
+			dbProtocol:       defaults.ProtocolRedis,
+			noTLS:            true,
+			redisClusterMode: true,
+			cmd: []string{"redis-cli",
+				"-h", "localhost",
+				"-p", "12345",
+				"-c",
+				"--user", "myUser"},
+			wantErr: false,
+		},
+		{
+			name:         "redis-cli no client cert",
+			dbProtocol:   defaults.ProtocolRedis,
+			noClientCert: true,
+			execer:       &fakeExec{},
+			cmd: []string{"redis-cli",
+				"-h", "localhost",
+				"-p", "12345",
+				"--tls",
+				"--user", "myUser"},
+			wantErr: false,
+		},
+		{
+			name:       "redis-cli with sni support",
+			dbProtocol: defaults.ProtocolRedis,
+			execer: &fakeExec{
+				execOutput: map[string][]byte{
+					redisBin: []byte("redis-cli 7.0.8"),
+				},
+			},
+			cmd: []string{"redis-cli",
+				"-h", "localhost",
+				"-p", "12345",
+				"--tls",
+				"--key", "/tmp/keys/example.com/bob",
+				"--cert", "/tmp/keys/example.com/bob-db/db.example.com/mysql-x509.pem",
+				"--sni", "localhost",
+				"--user", "myUser"},
+			wantErr: false,
+		},
+		{
+			name:       "redis-cli without sni support",
+			dbProtocol: defaults.ProtocolRedis,
+			execer: &fakeExec{
+				execOutput: map[string][]byte{
+					redisBin: []byte("redis-cli 5.0.3"),
+				},
+			},
+			cmd: []string{"redis-cli",
+				"-h", "localhost",
+				"-p", "12345",
+				"--tls",
+				"--key", "/tmp/keys/example.com/bob",
+				"--cert", "/tmp/keys/example.com/bob-db/db.example.com/mysql-x509.pem",
+				"--user", "myUser"},
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			database := &tlsca.RouteToDatabase{
+				Protocol:    tt.dbProtocol,
+				Database:    tt.databaseName,
+				Username:    "myUser",
+				ServiceName: "mysql",
+			}
+
+			opts := []ConnectCommandFunc{
+				WithLocalProxy("localhost", 12345, ""),
+			}
+			if tt.noTLS {
+				opts = append(opts, WithNoTLS())
+			}
+			if tt.noClientCert {
+				opts = append(opts, WithNoClientCert())
+			}
+			if tt.preferredClient != "" {
+				opts = append(opts, WithPreferredClient(tt.preferredClient))
+			}
+			if tt.redisClusterMode {
+				opts = append(opts, WithRedisClusterMode(true))
+			}
+
+			c := NewCmdBuilder(tc, profile, database, "root", opts...)
+			c.uid = utils.NewFakeUID()
+			c.exe = tt.execer
+			got, err := c.GetConnectCommand()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("GetConnectCommand() should return an error, but it didn't")
+				}
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.cmd, got.Args)
+		})
+	}
+}
+
+func TestCLICommandBuilderRedisAuth(t *testing.T) {
+	tc, err := client.NewClient(&client.Config{
+		WebProxyAddr: "proxy:3080",
+		SiteName:     "db.example.com",
+		Username:     "bob",
+		HostLogin:    "bob",
+		KeysDir:      t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	profile := &client.ProfileStatus{
+		Name:     "example.com",
+		Username: "bob",
+		Dir:      "/tmp",
+	}
+	writeFakeDatabaseCredentials(t, profile)
+
+	database := &tlsca.RouteToDatabase{
+		Protocol:    defaults.ProtocolRedis,
+		Username:    "myUser",
+		ServiceName: "mysql",
+	}
+
+	t.Run("AUTH token from the secret store is passed via REDISCLI_AUTH, not the command line", func(t *testing.T) {
+		t.Setenv("mysql.redis-auth-token", "my-token")
+		c := NewCmdBuilder(tc, profile, database, "root", WithLocalProxy("localhost", 12345, ""), WithSecretStore(EnvSecretStore{}))
+		c.exe = &fakeExec{}
+		cmd, err := c.GetConnectCommand()
+		require.NoError(t, err)
+		require.NotContains(t, cmd.Args, "my-token")
+		require.NotContains(t, cmd.Args, "-a")
+		require.Contains(t, cmd.Env, "REDISCLI_AUTH=my-token")
+	})
+
+	t.Run("no token configured leaves the environment untouched", func(t *testing.T) {
+		c := NewCmdBuilder(tc, profile, database, "root", WithLocalProxy("localhost", 12345, ""))
+		c.exe = &fakeExec{}
+		cmd, err := c.GetConnectCommand()
+		require.NoError(t, err)
+		require.Nil(t, cmd.Env)
+	})
+}
+
+func TestCLICommandBuilderSQLServerADAuth(t *testing.T) {
+	tc, err := client.NewClient(&client.Config{
+		WebProxyAddr: "proxy:3080",
+		SiteName:     "db.example.com",
+		Username:     "bob",
+		HostLogin:    "bob",
+		KeysDir:      t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	profile := &client.ProfileStatus{
+		Name:     "example.com",
+		Username: "bob",
+		Dir:      "/tmp",
+	}
+	writeFakeDatabaseCredentials(t, profile)
+
+	database := &tlsca.RouteToDatabase{
+		Protocol:    defaults.ProtocolSQLServer,
+		Username:    "bob",
+		Database:    "billing",
+		ServiceName: "sqlserver",
+	}
+
+	t.Run("AD auth uses -E and KRB5CCNAME instead of a fixture password", func(t *testing.T) {
+		c := NewCmdBuilder(tc, profile, database, "root",
+			WithLocalProxy("localhost", 12345, ""),
+			WithADKerberosAuth("/tmp/krb5cc_1000"))
+		c.exe = &fakeExec{execOutput: map[string][]byte{sqlcmdBin: {}}}
+		cmd, err := c.GetConnectCommand()
+		require.NoError(t, err)
+		require.Contains(t, cmd.Args, "-E")
+		require.NotContains(t, cmd.Args, "-U")
+		require.NotContains(t, cmd.Args, "-P")
+		require.Contains(t, cmd.Env, "KRB5CCNAME=/tmp/krb5cc_1000")
+	})
+
+	t.Run("falls back to mssql-cli when sqlcmd is unavailable", func(t *testing.T) {
+		c := NewCmdBuilder(tc, profile, database, "root",
+			WithLocalProxy("localhost", 12345, ""),
+			WithADKerberosAuth("/tmp/krb5cc_1000"))
+		c.exe = &fakeExec{execOutput: map[string][]byte{mssqlBin: {}}}
+		cmd, err := c.GetConnectCommand()
+		require.NoError(t, err)
+		require.Equal(t, mssqlBin, cmd.Args[0])
+	})
+
+	t.Run("without AD auth configured, falls back to fixture password", func(t *testing.T) {
+		c := NewCmdBuilder(tc, profile, database, "root", WithLocalProxy("localhost", 12345, ""))
+		c.exe = &fakeExec{execOutput: map[string][]byte{sqlcmdBin: {}}}
+		cmd, err := c.GetConnectCommand()
+		require.NoError(t, err)
+		require.Contains(t, cmd.Args, "-U")
+		require.Nil(t, cmd.Env)
+	})
+}
+
+func TestCLICommandBuilderExecQuery(t *testing.T) {
+	tc, err := client.NewClient(&client.Config{
+		WebProxyAddr: "proxy:3080",
+		SiteName:     "db.example.com",
+		Username:     "bob",
+		HostLogin:    "bob",
+		KeysDir:      t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	profile := &client.ProfileStatus{
+		Name:     "example.com",
+		Username: "bob",
+		Dir:      "/tmp",
+	}
+	writeFakeDatabaseCredentials(t, profile)
+
+	t.Run("postgres passes the query via -c, keeping the connection string last", func(t *testing.T) {
+		database := &tlsca.RouteToDatabase{
+			Protocol:    defaults.ProtocolPostgres,
+			Database:    "mydb",
+			Username:    "myUser",
+			ServiceName: "mysql",
+		}
+		c := NewCmdBuilder(tc, profile, database, "root", WithLocalProxy("localhost", 12345, ""), WithExecQuery("select 1"))
+		c.exe = &fakeExec{execOutput: map[string][]byte{postgresBin: {}}}
+		cmd, err := c.GetConnectCommand()
+		require.NoError(t, err)
+		require.Contains(t, cmd.Args, "-c")
+		require.Contains(t, cmd.Args, "select 1")
+		require.NotEqual(t, "select 1", cmd.Args[len(cmd.Args)-1], "connection string must stay last")
+	})
+
+	t.Run("mysql passes the query via --execute", func(t *testing.T) {
+		database := &tlsca.RouteToDatabase{
+			Protocol:    defaults.ProtocolMySQL,
+			Database:    "mydb",
+			Username:    "myUser",
+			ServiceName: "mysql",
+		}
+		c := NewCmdBuilder(tc, profile, database, "root", WithLocalProxy("localhost", 12345, ""), WithExecQuery("select 1"))
+		c.exe = &fakeExec{execOutput: map[string][]byte{mysqlBin: {}}}
+		cmd, err := c.GetConnectCommand()
+		require.NoError(t, err)
+		require.Contains(t, cmd.Args, "--execute")
+		require.Contains(t, cmd.Args, "select 1")
+	})
+
+	t.Run("mongosh passes the query via --eval, ahead of the database name", func(t *testing.T) {
+		database := &tlsca.RouteToDatabase{
+			Protocol:    defaults.ProtocolMongoDB,
+			Database:    "mydb",
+			Username:    "myUser",
+			ServiceName: "mongo",
+		}
+		c := NewCmdBuilder(tc, profile, database, "root", WithLocalProxy("localhost", 12345, ""), WithNoTLS(), WithExecQuery("db.version()"))
+		c.exe = &fakeExec{execOutput: map[string][]byte{mongoshBin: {}}}
+		cmd, err := c.GetConnectCommand()
+		require.NoError(t, err)
+		evalIdx := -1
+		for i, arg := range cmd.Args {
+			if arg == "--eval" {
+				evalIdx = i
+			}
+		}
+		require.NotEqual(t, -1, evalIdx, "--eval flag not found")
+		require.Equal(t, "db.version()", cmd.Args[evalIdx+1])
+		require.Equal(t, "mydb", cmd.Args[len(cmd.Args)-1], "database name must stay last")
+	})
+
+	t.Run("redis-cli takes the query as trailing positional words, not a flag value", func(t *testing.T) {
+		database := &tlsca.RouteToDatabase{
+			Protocol:    defaults.ProtocolRedis,
+			Username:    "myUser",
+			ServiceName: "mysql",
+		}
+		c := NewCmdBuilder(tc, profile, database, "root", WithLocalProxy("localhost", 12345, ""), WithNoTLS(), WithExecQuery("GET foo"))
+		c.exe = &fakeExec{}
+		cmd, err := c.GetConnectCommand()
+		require.NoError(t, err)
+		require.Equal(t, []string{"GET", "foo"}, cmd.Args[len(cmd.Args)-2:])
+	})
+
+	t.Run("sqlcmd passes the query via -Q", func(t *testing.T) {
+		database := &tlsca.RouteToDatabase{
+			Protocol:    defaults.ProtocolSQLServer,
+			Username:    "bob",
+			Database:    "billing",
+			ServiceName: "sqlserver",
+		}
+		c := NewCmdBuilder(tc, profile, database, "root", WithLocalProxy("localhost", 12345, ""), WithExecQuery("select 1"))
+		c.exe = &fakeExec{execOutput: map[string][]byte{sqlcmdBin: {}}}
+		cmd, err := c.GetConnectCommand()
+		require.NoError(t, err)
+		require.Contains(t, cmd.Args, "-Q")
+		require.Contains(t, cmd.Args, "select 1")
+	})
+
+	t.Run("sqlcmd passes the query via -Q under AD Kerberos auth too", func(t *testing.T) {
+		database := &tlsca.RouteToDatabase{
+			Protocol:    defaults.ProtocolSQLServer,
+			Username:    "bob",
+			Database:    "billing",
+			ServiceName: "sqlserver",
+		}
+		c := NewCmdBuilder(tc, profile, database, "root",
+			WithLocalProxy("localhost", 12345, ""),
+			WithADKerberosAuth("/tmp/krb5cc_1000"),
+			WithExecQuery("select 1"))
+		c.exe = &fakeExec{execOutput: map[string][]byte{sqlcmdBin: {}}}
+		cmd, err := c.GetConnectCommand()
+		require.NoError(t, err)
+		require.Contains(t, cmd.Args, "-Q")
+		require.Contains(t, cmd.Args, "select 1")
+	})
+}
+
+func TestClientVersionProbe(t *testing.T) {
+	tc, err := client.NewClient(&client.Config{
+		WebProxyAddr: "proxy:3080",
+		SiteName:     "db.example.com",
+		Username:     "bob",
+		HostLogin:    "bob",
+		KeysDir:      t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	profile := &client.ProfileStatus{
+		Name:     "example.com",
+		Username: "bob",
+		Dir:      "/tmp",
+	}
+	writeFakeDatabaseCredentials(t, profile)
+
+	database := &tlsca.RouteToDatabase{
+		Protocol:    defaults.ProtocolMongoDB,
+		Database:    "mydb",
+		Username:    "myUser",
+		ServiceName: "mysql",
+	}
+
+	t.Run("old mongosh falls back to the system CA store instead of --tlsCAFile", func(t *testing.T) {
+		c := NewCmdBuilder(tc, profile, database, "root", WithLocalProxy("localhost", 12345, ""))
+		c.exe = &fakeExec{execOutput: map[string][]byte{"mongosh": []byte("0.9.0")}}
+		cmd, err := c.GetConnectCommand()
+		require.NoError(t, err)
+		require.Contains(t, cmd.Args, "--tlsUseSystemCA")
+		require.NotContains(t, cmd.Args, "--tlsCAFile")
+	})
+
+	t.Run("new enough mongosh uses --tlsCAFile with the profile CA", func(t *testing.T) {
+		c := NewCmdBuilder(tc, profile, database, "root", WithLocalProxy("localhost", 12345, ""))
+		c.exe = &fakeExec{execOutput: map[string][]byte{"mongosh": []byte("1.1.6")}}
+		cmd, err := c.GetConnectCommand()
+		require.NoError(t, err)
+		require.Contains(t, cmd.Args, "--tlsCAFile")
+		require.NotContains(t, cmd.Args, "--tlsUseSystemCA")
+	})
+
+	t.Run("version is probed once and cached across multiple checks", func(t *testing.T) {
+		execer := &countingExec{fakeExec: fakeExec{execOutput: map[string][]byte{"mongosh": []byte("1.1.6")}}}
+		c := NewCmdBuilder(tc, profile, database, "root", WithLocalProxy("localhost", 12345, ""))
+		c.exe = execer
+
+		require.True(t, c.mongoshSupportsTLSCAFile())
+		require.True(t, c.mongoshSupportsTLSCAFile())
+		require.Equal(t, 1, execer.calls["mongosh"])
+	})
+
+	t.Run("unparseable version output is treated as too old", func(t *testing.T) {
+		c := NewCmdBuilder(tc, profile, database, "root", WithLocalProxy("localhost", 12345, ""))
+		c.exe = &fakeExec{execOutput: map[string][]byte{"mongosh": []byte("not-a-version")}}
+		require.False(t, c.mongoshSupportsTLSCAFile())
+	})
+}
+
+// countingExec wraps fakeExec to count RunCommand invocations per binary,
+// so tests can assert a version probe was cached rather than re-exec'd.
+type countingExec struct {
+	fakeExec
+	calls map[string]int
+}
+
+func (c *countingExec) RunCommand(cmd string, args ...string) ([]byte, error) {
+	if c.calls == nil {
+		c.calls = map[string]int{}
+	}
+	c.calls[cmd]++
+	return c.fakeExec.RunCommand(cmd, args...)
+}
+
+func TestIsRedisClusterMode(t *testing.T) {
+	newRedis := func(t *testing.T, uri string) types.Database {
+		database, err := types.NewDatabaseV3(types.Metadata{
+			Name: "redis",
+		}, types.DatabaseSpecV3{
+			Protocol: defaults.ProtocolRedis,
+			URI:      uri,
+		})
+		require.NoError(t, err)
+		return database
+	}
+
+	tests := []struct {
+		name string
+		uri  string
+		want bool
+	}{
+		{
+			name: "cluster mode",
+			uri:  "rediss://redis.example.com:6379?mode=cluster",
+			want: true,
+		},
+		{
+			name: "standalone mode is explicit",
+			uri:  "rediss://redis.example.com:6379?mode=standalone",
+			want: false,
+		},
+		{
+			name: "no mode query defaults to standalone",
+			uri:  "rediss://redis.example.com:6379",
+			want: false,
+		},
+		{
+			name: "mode is case-insensitive",
+			uri:  "rediss://redis.example.com:6379?mode=CLUSTER",
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, IsRedisClusterMode(newRedis(t, tt.uri)))
+		})
+	}
+}
+
+func TestCLICommandBuilderDefaultDatabaseName(t *testing.T) {
+	tc, err := client.NewClient(&client.Config{
+		WebProxyAddr: "proxy:3080",
+		SiteName:     "db.example.com",
+		Username:     "bob",
+		HostLogin:    "bob",
+		KeysDir:      t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	profile := &client.ProfileStatus{
+		Name:     "example.com",
+		Username: "bob",
+		Dir:      "/tmp",
+	}
+	writeFakeDatabaseCredentials(t, profile)
+
+	tests := []struct {
+		name       string
+		dbProtocol string
+		wantSuffix string
+	}{
+		{
+			name:       "postgres defaults to the database user",
+			dbProtocol: defaults.ProtocolPostgres,
+			wantSuffix: "/myUser?",
+		},
+		{
+			name:       "mongo defaults to admin",
+			dbProtocol: defaults.ProtocolMongoDB,
+			wantSuffix: "admin",
+		},
+		{
+			name:       "mysql has no default",
+			dbProtocol: defaults.ProtocolMySQL,
+			wantSuffix: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			database := &tlsca.RouteToDatabase{
+				Protocol:    tt.dbProtocol,
+				Username:    "myUser",
+				ServiceName: "mysql",
+			}
+			c := NewCmdBuilder(tc, profile, database, "root", WithLocalProxy("localhost", 12345, ""))
+			c.uid = utils.NewFakeUID()
+			c.exe = &fakeExec{execOutput: map[string][]byte{mysqlBin: {}, postgresBin: {}}}
+
+			got, err := c.GetConnectCommand()
+			require.NoError(t, err)
+
+			switch tt.dbProtocol {
+			case defaults.ProtocolPostgres:
+				require.Contains(t, got.Args[len(got.Args)-1], tt.wantSuffix)
+			case defaults.ProtocolMongoDB:
+				require.Equal(t, tt.wantSuffix, got.Args[len(got.Args)-1])
+			case defaults.ProtocolMySQL:
+				require.NotContains(t, got.Args, "--database")
+			}
+		})
+	}
+}
+
+func TestCLICommandBuilderCAPathOverride(t *testing.T) {
+	tc, err := client.NewClient(&client.Config{
+		WebProxyAddr: "proxy:3080",
+		SiteName:     "db.example.com",
+		Username:     "bob",
+		HostLogin:    "bob",
+		KeysDir:      t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	profile := &client.ProfileStatus{
+		Name:     "example.com",
+		Username: "bob",
+		Dir:      "/tmp",
+	}
+	writeFakeDatabaseCredentials(t, profile)
+
+	customCAPath := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(customCAPath, []byte(fixtures.TLSCACertPEM), 0600))
+
+	database := &tlsca.RouteToDatabase{
+		Protocol:    defaults.ProtocolPostgres,
+		Database:    "mydb",
+		Username:    "myUser",
+		ServiceName: "mysql",
+	}
+
+	c := NewCmdBuilder(tc, profile, database, "root",
+		WithLocalProxy("localhost", 12345, ""),
+		WithCAPath(customCAPath),
+	)
+	c.uid = utils.NewFakeUID()
+	c.exe = &fakeExec{execOutput: map[string][]byte{postgresBin: {}}}
+
+	got, err := c.GetConnectCommand()
+	require.NoError(t, err)
+	require.Contains(t, got.Args[len(got.Args)-1], "sslrootcert="+customCAPath)
+}
+
+func TestCLICommandBuilderMissingCredentials(t *testing.T) {
+	tc, err := client.NewClient(&client.Config{
+		WebProxyAddr: "proxy:3080",
+		SiteName:     "db.example.com",
+		Username:     "bob",
+		HostLogin:    "bob",
+		KeysDir:      t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	// Note: writeFakeDatabaseCredentials is intentionally not called, so
+	// none of the profile's database credential files exist on disk.
+	profile := &client.ProfileStatus{
+		Name:     "example.com",
+		Username: "bob",
+		Dir:      t.TempDir(),
+	}
+
+	database := &tlsca.RouteToDatabase{
+		Protocol:    defaults.ProtocolPostgres,
+		Database:    "mydb",
+		Username:    "myUser",
+		ServiceName: "mysql",
+	}
+
+	c := NewCmdBuilder(tc, profile, database, "root", WithLocalProxy("localhost", 12345, ""))
+	c.uid = utils.NewFakeUID()
+
+	_, err = c.GetConnectCommand()
+	require.True(t, trace.IsNotFound(err), "expected a not found error, got %T: %v", err, err)
+	require.Contains(t, err.Error(), "tsh db login mysql")
+}
+
+func TestCLICommandBuilderContainerGateway(t *testing.T) {
+	tc, err := client.NewClient(&client.Config{
+		WebProxyAddr: "proxy:3080",
+		SiteName:     "db.example.com",
+		Username:     "bob",
+		HostLogin:    "bob",
+		KeysDir:      t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	profile := &client.ProfileStatus{
+		Name:     "example.com",
+		Username: "bob",
+		Dir:      "/tmp",
+	}
+	writeFakeDatabaseCredentials(t, profile)
+
+	database := &tlsca.RouteToDatabase{
+		Protocol:    defaults.ProtocolPostgres,
+		Database:    "mydb",
+		Username:    "myUser",
+		ServiceName: "mysql",
+	}
+
+	c := NewCmdBuilder(tc, profile, database, "root",
+		WithLocalProxy("localhost", 12345, ""),
+		WithContainerGateway("host.docker.internal"),
+	)
+	c.uid = utils.NewFakeUID()
+	c.exe = &fakeExec{execOutput: map[string][]byte{postgresBin: {}}}
+
+	got, err := c.GetConnectCommand()
+	require.NoError(t, err)
+	require.Contains(t, got.Args[len(got.Args)-1], "host.docker.internal:12345")
+	require.NotContains(t, got.Args[len(got.Args)-1], "localhost")
+}
+
+func TestCLICommandBuilderContainerImage(t *testing.T) {
+	tc, err := client.NewClient(&client.Config{
+		WebProxyAddr: "proxy:3080",
+		SiteName:     "db.example.com",
+		Username:     "bob",
+		HostLogin:    "bob",
+		KeysDir:      t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	profile := &client.ProfileStatus{
+		Name:     "example.com",
+		Username: "bob",
+		Dir:      "/tmp",
+	}
+	writeFakeDatabaseCredentials(t, profile)
+
+	database := &tlsca.RouteToDatabase{
+		Protocol:    defaults.ProtocolPostgres,
+		Database:    "mydb",
+		Username:    "myUser",
+		ServiceName: "mysql",
+	}
+
+	images := map[string]string{
+		defaults.ProtocolPostgres: "postgres:15@sha256:1234",
+	}
+
+	t.Run("configured image is returned for matching protocol", func(t *testing.T) {
+		c := NewCmdBuilder(tc, profile, database, "root", WithContainerImages(images))
+		image, ok := c.ContainerImage()
+		require.True(t, ok)
+		require.Equal(t, "postgres:15@sha256:1234", image)
+	})
+
+	t.Run("no image configured for other protocols", func(t *testing.T) {
+		mysqlDatabase := &tlsca.RouteToDatabase{
+			Protocol:    defaults.ProtocolMySQL,
+			ServiceName: "mysql",
+		}
+		c := NewCmdBuilder(tc, profile, mysqlDatabase, "root", WithContainerImages(images))
+		_, ok := c.ContainerImage()
+		require.False(t, ok)
+	})
+}
+
+func TestCLICommandBuilderMongoDBLegacyShell(t *testing.T) {
+	tc, err := client.NewClient(&client.Config{
+		WebProxyAddr: "proxy:3080",
+		SiteName:     "db.example.com",
+		Username:     "bob",
+		HostLogin:    "bob",
+		KeysDir:      t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	profile := &client.ProfileStatus{
+		Name:     "example.com",
+		Username: "bob",
+		Dir:      "/tmp",
+	}
+	writeFakeDatabaseCredentials(t, profile)
+
+	database := &tlsca.RouteToDatabase{
+		Protocol:    defaults.ProtocolMongoDB,
+		Database:    "mydb",
+		Username:    "myUser",
+		ServiceName: "mysql",
+	}
+	noMongosh := &fakeExec{execOutput: map[string][]byte{}}
+
+	t.Run("refuses legacy shell against a server that dropped support for it", func(t *testing.T) {
+		c := NewCmdBuilder(tc, profile, database, "root", WithMongoDBServerVersion("5.0.3"))
+		c.exe = noMongosh
+		_, err := c.GetConnectCommand()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "mongosh")
+	})
+
+	t.Run("falls back to legacy shell against an older server", func(t *testing.T) {
+		c := NewCmdBuilder(tc, profile, database, "root", WithMongoDBServerVersion("4.4.0"))
+		c.exe = noMongosh
+		cmd, err := c.GetConnectCommand()
+		require.NoError(t, err)
+		require.Equal(t, mongoBin, cmd.Args[0])
+	})
+
+	t.Run("falls back to legacy shell when server version is unknown", func(t *testing.T) {
+		c := NewCmdBuilder(tc, profile, database, "root")
+		c.exe = noMongosh
+		cmd, err := c.GetConnectCommand()
+		require.NoError(t, err)
+		require.Equal(t, mongoBin, cmd.Args[0])
+	})
+}
+
+func TestCLICommandBuilderGetConnectionURI(t *testing.T) {
+	tc, err := client.NewClient(&client.Config{
+		WebProxyAddr: "proxy:3080",
+		SiteName:     "db.example.com",
+		Username:     "bob",
+		HostLogin:    "bob",
+		KeysDir:      t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	profile := &client.ProfileStatus{
+		Name:     "example.com",
+		Username: "bob",
+		Dir:      "/tmp",
+	}
+	writeFakeDatabaseCredentials(t, profile)
+
+	mongoDatabase := &tlsca.RouteToDatabase{
+		Protocol:    defaults.ProtocolMongoDB,
+		Database:    "mydb",
+		Username:    "myUser",
+		ServiceName: "mysql",
+	}
+
+	t.Run("builds a copy-pasteable mongodb:// URI", func(t *testing.T) {
+		c := NewCmdBuilder(tc, profile, mongoDatabase, "root", WithLocalProxy("localhost", 12345, ""))
+		uri, err := c.GetConnectionURI()
+		require.NoError(t, err)
+
+		parsed, err := url.Parse(uri)
+		require.NoError(t, err)
+		require.Equal(t, "mongodb", parsed.Scheme)
+		require.Equal(t, "myUser", parsed.User.Username())
+		require.Equal(t, "localhost:12345", parsed.Host)
+		require.Equal(t, "/mydb", parsed.Path)
+		require.Equal(t, "true", parsed.Query().Get("tls"))
+		require.Equal(t, c.getCertPath(), parsed.Query().Get("tlsCertificateKeyFile"))
+	})
+
+	t.Run("without TLS omits tls query parameters", func(t *testing.T) {
+		c := NewCmdBuilder(tc, profile, mongoDatabase, "root", WithLocalProxy("localhost", 12345, ""), WithNoTLS())
+		uri, err := c.GetConnectionURI()
+		require.NoError(t, err)
+
+		parsed, err := url.Parse(uri)
+		require.NoError(t, err)
+		require.Empty(t, parsed.Query().Get("tls"))
+	})
+
+	t.Run("WithMongoDBURIOptions adds options flags can't express", func(t *testing.T) {
+		c := NewCmdBuilder(tc, profile, mongoDatabase, "root", WithLocalProxy("localhost", 12345, ""),
+			WithMongoDBURIOptions(map[string]string{
+				"replicaSet":     "rs0",
+				"readPreference": "secondary",
+			}))
+		uri, err := c.GetConnectionURI()
+		require.NoError(t, err)
+
+		parsed, err := url.Parse(uri)
+		require.NoError(t, err)
+		require.Equal(t, "rs0", parsed.Query().Get("replicaSet"))
+		require.Equal(t, "secondary", parsed.Query().Get("readPreference"))
+	})
+
+	t.Run("unsupported for non-MongoDB protocols", func(t *testing.T) {
+		database := &tlsca.RouteToDatabase{
+			Protocol:    defaults.ProtocolPostgres,
+			Username:    "myUser",
+			ServiceName: "mysql",
+		}
+		c := NewCmdBuilder(tc, profile, database, "root", WithLocalProxy("localhost", 12345, ""))
+		_, err := c.GetConnectionURI()
+		require.Error(t, err)
+		require.True(t, trace.IsBadParameter(err))
+	})
+}
+
+func TestCLICommandBuilderEndpoint(t *testing.T) {
+	tc, err := client.NewClient(&client.Config{
+		WebProxyAddr: "proxy:3080",
+		SiteName:     "db.example.com",
+		Username:     "bob",
+		HostLogin:    "bob",
+		KeysDir:      t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	profile := &client.ProfileStatus{
+		Name:     "example.com",
+		Username: "bob",
+		Dir:      "/tmp",
+	}
+	writeFakeDatabaseCredentials(t, profile)
+
+	database := &tlsca.RouteToDatabase{
+		Protocol:    defaults.ProtocolPostgres,
+		Database:    "mydb",
+		Username:    "myUser",
+		ServiceName: "mysql",
+	}
+
+	t.Run("endpoint configured is returned and surfaced as application_name", func(t *testing.T) {
+		c := NewCmdBuilder(tc, profile, database, "root", WithEndpoint("reader"))
+		c.exe = &fakeExec{execOutput: map[string][]byte{postgresBin: {}}}
+		endpoint, ok := c.Endpoint()
+		require.True(t, ok)
+		require.Equal(t, "reader", endpoint)
+
+		cmd, err := c.GetConnectCommand()
+		require.NoError(t, err)
+		require.Contains(t, cmd.Args[len(cmd.Args)-1], "application_name=reader")
+	})
+
+	t.Run("no endpoint configured", func(t *testing.T) {
+		c := NewCmdBuilder(tc, profile, database, "root")
+		_, ok := c.Endpoint()
+		require.False(t, ok)
+	})
+}
+
+func TestCLICommandBuilderQueryAuditNotice(t *testing.T) {
+	tc, err := client.NewClient(&client.Config{
+		WebProxyAddr: "proxy:3080",
+		SiteName:     "db.example.com",
+		Username:     "bob",
+		HostLogin:    "bob",
+		KeysDir:      t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	profile := &client.ProfileStatus{
+		Name:     "example.com",
+		Username: "bob",
+		Dir:      "/tmp",
+	}
+	writeFakeDatabaseCredentials(t, profile)
+
+	database := &tlsca.RouteToDatabase{
+		Protocol:    defaults.ProtocolPostgres,
+		Database:    "mydb",
+		Username:    "myUser",
+		ServiceName: "mysql",
+	}
+
+	t.Run("notice configured is returned and surfaced via a persistent psql prompt", func(t *testing.T) {
+		c := NewCmdBuilder(tc, profile, database, "root", WithQueryAuditNotice("queries are recorded"))
+		c.exe = &fakeExec{execOutput: map[string][]byte{postgresBin: {}}}
+		notice, ok := c.QueryAuditNotice()
+		require.True(t, ok)
+		require.Equal(t, "queries are recorded", notice)
+
+		cmd, err := c.GetConnectCommand()
+		require.NoError(t, err)
+		require.Equal(t, []string{"--set", "PROMPT1=[queries are recorded] %/%R%# "}, cmd.Args[1:3])
+	})
+
+	t.Run("no notice configured", func(t *testing.T) {
+		c := NewCmdBuilder(tc, profile, database, "root")
+		c.exe = &fakeExec{execOutput: map[string][]byte{postgresBin: {}}}
+		_, ok := c.QueryAuditNotice()
+		require.False(t, ok)
+
+		cmd, err := c.GetConnectCommand()
+		require.NoError(t, err)
+		require.Len(t, cmd.Args, 2)
+	})
+}
+
+func TestCLICommandBuilderIdleTimeout(t *testing.T) {
+	tc, err := client.NewClient(&client.Config{
+		WebProxyAddr: "proxy:3080",
+		SiteName:     "db.example.com",
+		Username:     "bob",
+		HostLogin:    "bob",
+		KeysDir:      t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	profile := &client.ProfileStatus{
+		Name:     "example.com",
+		Username: "bob",
+		Dir:      "/tmp",
+	}
+	writeFakeDatabaseCredentials(t, profile)
+
+	t.Run("postgres sets PGOPTIONS idle_session_timeout", func(t *testing.T) {
+		database := &tlsca.RouteToDatabase{
+			Protocol:    defaults.ProtocolPostgres,
+			Database:    "mydb",
+			Username:    "myUser",
+			ServiceName: "mysql",
+		}
+		c := NewCmdBuilder(tc, profile, database, "root", WithIdleTimeout(5*time.Minute))
+		c.exe = &fakeExec{execOutput: map[string][]byte{postgresBin: {}}}
+		timeout, ok := c.IdleTimeout()
+		require.True(t, ok)
+		require.Equal(t, 5*time.Minute, timeout)
+
+		cmd, err := c.GetConnectCommand()
+		require.NoError(t, err)
+		require.Contains(t, cmd.Env, "PGOPTIONS=-c idle_session_timeout=300000")
+	})
+
+	t.Run("mariadb sets wait_timeout via init-command", func(t *testing.T) {
+		database := &tlsca.RouteToDatabase{
+			Protocol:    defaults.ProtocolMySQL,
+			Database:    "mydb",
+			Username:    "myUser",
+			ServiceName: "mysql",
+		}
+		c := NewCmdBuilder(tc, profile, database, "root", WithIdleTimeout(90*time.Second))
+		c.exe = &fakeExec{execOutput: map[string][]byte{"mariadb": []byte("")}}
+		cmd, err := c.GetConnectCommand()
+		require.NoError(t, err)
+		require.Contains(t, cmd.Args, "--init-command=SET SESSION wait_timeout=90")
+	})
+
+	t.Run("no idle timeout configured", func(t *testing.T) {
+		database := &tlsca.RouteToDatabase{
+			Protocol:    defaults.ProtocolPostgres,
+			Database:    "mydb",
+			Username:    "myUser",
+			ServiceName: "mysql",
+		}
+		c := NewCmdBuilder(tc, profile, database, "root")
+		c.exe = &fakeExec{execOutput: map[string][]byte{postgresBin: {}}}
+		_, ok := c.IdleTimeout()
+		require.False(t, ok)
+
+		cmd, err := c.GetConnectCommand()
+		require.NoError(t, err)
+		require.Nil(t, cmd.Env)
+	})
+}
+
+func TestCLICommandBuilderStartupParameters(t *testing.T) {
+	tc, err := client.NewClient(&client.Config{
+		WebProxyAddr: "proxy:3080",
+		SiteName:     "db.example.com",
+		Username:     "bob",
+		HostLogin:    "bob",
+		KeysDir:      t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	profile := &client.ProfileStatus{
+		Name:     "example.com",
+		Username: "bob",
+		Dir:      "/tmp",
+	}
+	writeFakeDatabaseCredentials(t, profile)
+
+	t.Run("postgres carries options through PGOPTIONS alongside idle timeout", func(t *testing.T) {
+		database := &tlsca.RouteToDatabase{
+			Protocol:    defaults.ProtocolPostgres,
+			Database:    "mydb",
+			Username:    "myUser",
+			ServiceName: "mysql",
+		}
+		c := NewCmdBuilder(tc, profile, database, "root",
+			WithPostgresOptions("-c search_path=myschema -c role=readonly"),
+			WithIdleTimeout(5*time.Minute))
+		c.exe = &fakeExec{execOutput: map[string][]byte{postgresBin: {}}}
+		cmd, err := c.GetConnectCommand()
+		require.NoError(t, err)
+		require.Contains(t, cmd.Env, "PGOPTIONS=-c search_path=myschema -c role=readonly -c idle_session_timeout=300000")
+	})
+
+	t.Run("postgres with no options configured leaves PGOPTIONS untouched", func(t *testing.T) {
+		database := &tlsca.RouteToDatabase{
+			Protocol:    defaults.ProtocolPostgres,
+			Database:    "mydb",
+			Username:    "myUser",
+			ServiceName: "mysql",
+		}
+		c := NewCmdBuilder(tc, profile, database, "root")
+		c.exe = &fakeExec{execOutput: map[string][]byte{postgresBin: {}}}
+		cmd, err := c.GetConnectCommand()
+		require.NoError(t, err)
+		require.Nil(t, cmd.Env)
+	})
+
+	t.Run("mysql runs init commands alongside the idle timeout's SET SESSION", func(t *testing.T) {
+		database := &tlsca.RouteToDatabase{
+			Protocol:    defaults.ProtocolMySQL,
+			Database:    "mydb",
+			Username:    "myUser",
+			ServiceName: "mysql",
+		}
+		c := NewCmdBuilder(tc, profile, database, "root",
+			WithMySQLInitCommands("SET ROLE readonly"),
+			WithIdleTimeout(90*time.Second))
+		c.exe = &fakeExec{execOutput: map[string][]byte{"mariadb": []byte("")}}
+		cmd, err := c.GetConnectCommand()
+		require.NoError(t, err)
+		require.Contains(t, cmd.Args, "--init-command=SET SESSION wait_timeout=90; SET ROLE readonly")
+	})
+
+	t.Run("mysql with no init commands configured", func(t *testing.T) {
+		database := &tlsca.RouteToDatabase{
+			Protocol:    defaults.ProtocolMySQL,
+			Database:    "mydb",
+			Username:    "myUser",
+			ServiceName: "mysql",
+		}
+		c := NewCmdBuilder(tc, profile, database, "root")
+		c.exe = &fakeExec{execOutput: map[string][]byte{"mariadb": []byte("")}}
+		cmd, err := c.GetConnectCommand()
+		require.NoError(t, err)
+		for _, arg := range cmd.Args {
+			require.NotContains(t, arg, "--init-command")
+		}
+	})
+}
+
+func TestCLICommandBuilderPostgresServiceFile(t *testing.T) {
+	tc, err := client.NewClient(&client.Config{
+		WebProxyAddr: "proxy:3080",
+		SiteName:     "db.example.com",
+		Username:     "bob",
+		HostLogin:    "bob",
+		KeysDir:      t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	profile := &client.ProfileStatus{
+		Name:     "example.com",
+		Username: "bob",
+		Dir:      "/tmp",
+	}
+	writeFakeDatabaseCredentials(t, profile)
+
+	database := &tlsca.RouteToDatabase{
+		Protocol:    defaults.ProtocolPostgres,
+		Database:    "mydb",
+		Username:    "myUser",
+		ServiceName: "mysql",
+	}
+
+	t.Run("connects via service= instead of a full connection string", func(t *testing.T) {
+		c := NewCmdBuilder(tc, profile, database, "root", WithPostgresServiceFile())
+		c.exe = &fakeExec{execOutput: map[string][]byte{postgresBin: {}}}
+		cmd, err := c.GetConnectCommand()
+		require.NoError(t, err)
+		require.Contains(t, cmd.Args, "service=db.example.com-mysql")
+		for _, arg := range cmd.Args {
+			require.NotContains(t, arg, "sslcert")
+		}
+	})
+
+	t.Run("without the option, uses a full connection string", func(t *testing.T) {
+		c := NewCmdBuilder(tc, profile, database, "root")
+		c.exe = &fakeExec{execOutput: map[string][]byte{postgresBin: {}}}
+		cmd, err := c.GetConnectCommand()
+		require.NoError(t, err)
+		for _, arg := range cmd.Args {
+			require.NotContains(t, arg, "service=")
+		}
+	})
+}
+
+func TestCLICommandBuilderMySQLOptionFile(t *testing.T) {
+	tc, err := client.NewClient(&client.Config{
+		WebProxyAddr: "proxy:3080",
+		SiteName:     "db.example.com",
+		Username:     "bob",
+		HostLogin:    "bob",
+		KeysDir:      t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	profile := &client.ProfileStatus{
+		Name:     "example.com",
+		Username: "bob",
+		Dir:      "/tmp",
+	}
+	writeFakeDatabaseCredentials(t, profile)
+
+	database := &tlsca.RouteToDatabase{
+		Protocol:    defaults.ProtocolMySQL,
+		Database:    "mydb",
+		Username:    "myUser",
+		ServiceName: "mysql",
+	}
+
+	oracleExecer := &fakeExec{
+		execOutput: map[string][]byte{
+			mysqlBin: []byte("Ver 8.0.27-0ubuntu0.20.04.1 for Linux on x86_64 ((Ubuntu))"),
+		},
+	}
+
+	t.Run("connects via an isolated option file instead of a group suffix", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "my.cnf")
+		c := NewCmdBuilder(tc, profile, database, "root", WithMySQLOptionFile(path))
+		c.exe = oracleExecer
+		cmd, err := c.GetConnectCommand()
+		require.NoError(t, err)
+		require.Contains(t, cmd.Args, "--defaults-extra-file="+path)
+		for _, arg := range cmd.Args {
+			require.NotContains(t, arg, "--defaults-group-suffix")
+		}
+
+		optionFile, err := mysql.LoadFromPath(path)
+		require.NoError(t, err)
+		require.NoError(t, optionFile.VerifyClientSection())
+	})
+
+	t.Run("without the option, uses a group suffix", func(t *testing.T) {
+		c := NewCmdBuilder(tc, profile, database, "root")
+		c.exe = oracleExecer
+		cmd, err := c.GetConnectCommand()
+		require.NoError(t, err)
+		require.Contains(t, cmd.Args, "--defaults-group-suffix=_db.example.com-mysql")
+		for _, arg := range cmd.Args {
+			require.NotContains(t, arg, "--defaults-extra-file")
+		}
+	})
+}
+
+func TestCLICommandBuilderPasswordAuthCredentialFiles(t *testing.T) {
+	tc, err := client.NewClient(&client.Config{
+		WebProxyAddr: "proxy:3080",
+		SiteName:     "db.example.com",
+		Username:     "bob",
+		HostLogin:    "bob",
+		KeysDir:      t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	profile := &client.ProfileStatus{
+		Name:     "example.com",
+		Username: "bob",
+		Dir:      t.TempDir(),
+	}
+	writeFakeDatabaseCredentials(t, profile)
+
+	t.Run("postgres writes a scoped .pgpass file and points PGPASSFILE at it", func(t *testing.T) {
+		t.Setenv("mysql.postgres-password", "s3cr3t")
+		database := &tlsca.RouteToDatabase{
+			Protocol:    defaults.ProtocolPostgres,
+			Database:    "mydb",
+			Username:    "myUser",
+			ServiceName: "mysql",
+		}
+		c := NewCmdBuilder(tc, profile, database, "root", WithLocalProxy("localhost", 12345, ""), WithSecretStore(EnvSecretStore{}))
+		c.exe = &fakeExec{execOutput: map[string][]byte{postgresBin: {}}}
+		cmd, err := c.GetConnectCommand()
+		require.NoError(t, err)
+
+		var pgpassFile string
+		for _, e := range cmd.Env {
+			if strings.HasPrefix(e, "PGPASSFILE=") {
+				pgpassFile = strings.TrimPrefix(e, "PGPASSFILE=")
+			}
+		}
+		require.NotEmpty(t, pgpassFile, "expected PGPASSFILE to be set")
+
+		info, err := os.Stat(pgpassFile)
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+		contents, err := os.ReadFile(pgpassFile)
+		require.NoError(t, err)
+		require.Equal(t, "localhost:12345:mydb:myUser:s3cr3t\n", string(contents))
+
+		require.NotContains(t, cmd.Args, "s3cr3t")
+	})
+
+	t.Run("postgres with no password secret configured leaves PGPASSFILE unset", func(t *testing.T) {
+		database := &tlsca.RouteToDatabase{
+			Protocol:    defaults.ProtocolPostgres,
+			Database:    "mydb",
+			Username:    "myUser",
+			ServiceName: "mysql",
+		}
+		c := NewCmdBuilder(tc, profile, database, "root", WithLocalProxy("localhost", 12345, ""), WithSecretStore(EnvSecretStore{}))
+		c.exe = &fakeExec{execOutput: map[string][]byte{postgresBin: {}}}
+		cmd, err := c.GetConnectCommand()
+		require.NoError(t, err)
+		for _, e := range cmd.Env {
+			require.NotContains(t, e, "PGPASSFILE")
+		}
+	})
+
+	t.Run("mysql writes a .my.cnf file and passes it via --defaults-extra-file first", func(t *testing.T) {
+		t.Setenv("mysql.mysql-password", "s3cr3t")
+		database := &tlsca.RouteToDatabase{
+			Protocol:    defaults.ProtocolMySQL,
+			Database:    "mydb",
+			Username:    "myUser",
+			ServiceName: "mysql",
+		}
+		c := NewCmdBuilder(tc, profile, database, "root", WithSecretStore(EnvSecretStore{}))
+		c.exe = &fakeExec{execOutput: map[string][]byte{"mariadb": []byte("")}}
+		cmd, err := c.GetConnectCommand()
+		require.NoError(t, err)
+
+		require.True(t, strings.HasPrefix(cmd.Args[1], "--defaults-extra-file="), "expected --defaults-extra-file to be the first argument, got %v", cmd.Args)
+		cnfPath := strings.TrimPrefix(cmd.Args[1], "--defaults-extra-file=")
+
+		info, err := os.Stat(cnfPath)
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+		contents, err := os.ReadFile(cnfPath)
+		require.NoError(t, err)
+		require.Equal(t, "[client]\nuser=myUser\npassword=s3cr3t\n", string(contents))
+
+		require.NotContains(t, cmd.Args, "s3cr3t")
+	})
+}
+
+func TestCLICommandBuilderSnowflakeAccount(t *testing.T) {
+	tc, err := client.NewClient(&client.Config{
+		WebProxyAddr: "proxy:3080",
+		SiteName:     "db.example.com",
+		Username:     "bob",
+		HostLogin:    "bob",
+		KeysDir:      t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	profile := &client.ProfileStatus{
+		Name:     "example.com",
+		Username: "bob",
+		Dir:      "/tmp",
+	}
+	writeFakeDatabaseCredentials(t, profile)
+
+	database := &tlsca.RouteToDatabase{
+		Protocol:    defaults.ProtocolSnowflake,
+		Database:    "mydb",
+		Username:    "myUser",
+		ServiceName: "mysql",
+	}
+
+	t.Run("falls back to service name without an explicit account", func(t *testing.T) {
+		c := NewCmdBuilder(tc, profile, database, "root")
+		_, ok := c.SnowflakeAccount()
+		require.False(t, ok)
+
+		cmd, err := c.GetConnectCommand()
+		require.NoError(t, err)
+		require.Contains(t, cmd.Args, "mysql")
+	})
+
+	t.Run("explicit account overrides the service name fallback", func(t *testing.T) {
+		c := NewCmdBuilder(tc, profile, database, "root", WithSnowflakeAccount("acme-xy12345"))
+		account, ok := c.SnowflakeAccount()
+		require.True(t, ok)
+		require.Equal(t, "acme-xy12345", account)
+
+		cmd, err := c.GetConnectCommand()
+		require.NoError(t, err)
+		require.Contains(t, cmd.Args, "acme-xy12345")
+		require.NotContains(t, cmd.Args, "mysql")
+	})
+
+	t.Run("token from the secret store triggers oauth authentication", func(t *testing.T) {
+		t.Setenv("mysql.snowflake-token", "my-token")
+		c := NewCmdBuilder(tc, profile, database, "root", WithSecretStore(EnvSecretStore{}))
+		cmd, err := c.GetConnectCommand()
+		require.NoError(t, err)
+		require.Contains(t, cmd.Args, "--authenticator")
+		require.Contains(t, cmd.Args, "oauth")
+		require.Contains(t, cmd.Args, "--token")
+		require.Contains(t, cmd.Args, "my-token")
+	})
+
+	t.Run("no token configured skips oauth flags", func(t *testing.T) {
+		c := NewCmdBuilder(tc, profile, database, "root")
+		cmd, err := c.GetConnectCommand()
+		require.NoError(t, err)
+		require.NotContains(t, cmd.Args, "--authenticator")
+	})
+}
+
+func TestCLICommandBuilderWithLogger(t *testing.T) {
+	tc, err := client.NewClient(&client.Config{
+		WebProxyAddr: "proxy:3080",
+		SiteName:     "db.example.com",
+		Username:     "bob",
+		HostLogin:    "bob",
+		KeysDir:      t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	profile := &client.ProfileStatus{
+		Name:     "example.com",
+		Username: "bob",
+		Dir:      "/tmp",
+	}
+	writeFakeDatabaseCredentials(t, profile)
+
+	database := &tlsca.RouteToDatabase{
+		Protocol:    defaults.ProtocolCockroachDB,
+		Database:    "mydb",
+		Username:    "myUser",
+		ServiceName: "mysql",
+	}
+
+	var buf bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&buf)
+	logger.SetLevel(logrus.DebugLevel)
+
+	c := NewCmdBuilder(tc, profile, database, "root",
+		WithLocalProxy("localhost", 12345, ""),
+		WithLogger(logger),
+	)
+	c.uid = utils.NewFakeUID()
+	c.exe = &fakeExec{}
+
+	_, err = c.GetConnectCommand()
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "falling back to")
+	require.Contains(t, buf.String(), "dbcmd.build_command: completed in")
+}
+
+func TestTraceSpan(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&buf)
+	logger.SetLevel(logrus.DebugLevel)
+
+	endSpan := TraceSpan(logger, "test.span")
+	endSpan()
+
+	require.Contains(t, buf.String(), "test.span: completed in")
+}
+
+func TestCLICommandBuilderExtraArgs(t *testing.T) {
+	tc, err := client.NewClient(&client.Config{
+		WebProxyAddr: "proxy:3080",
+		SiteName:     "db.example.com",
+		Username:     "bob",
+		HostLogin:    "bob",
+		KeysDir:      t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	profile := &client.ProfileStatus{
+		Name:     "example.com",
+		Username: "bob",
+		Dir:      "/tmp",
+	}
+	writeFakeDatabaseCredentials(t, profile)
+
+	database := &tlsca.RouteToDatabase{
+		Protocol:    defaults.ProtocolPostgres,
+		Database:    "mydb",
+		Username:    "myUser",
+		ServiceName: "mysql",
+	}
+
+	c := NewCmdBuilder(tc, profile, database, "root",
+		WithLocalProxy("localhost", 12345, ""),
+		WithExtraArgs(map[string][]string{
+			defaults.ProtocolPostgres: {"--pager=pspg"},
+			defaults.ProtocolMySQL:    {"--prompt=unused"},
+		}),
+	)
+	c.uid = utils.NewFakeUID()
+	c.exe = &fakeExec{execOutput: map[string][]byte{postgresBin: {}}}
+
+	got, err := c.GetConnectCommand()
+	require.NoError(t, err)
+	require.Equal(t, []string{"--pager=pspg"}, got.Args[len(got.Args)-1:])
+}
+
+func TestCLICommandBuilderPassthroughArgs(t *testing.T) {
+	tc, err := client.NewClient(&client.Config{
+		WebProxyAddr: "proxy:3080",
+		SiteName:     "db.example.com",
+		Username:     "bob",
+		HostLogin:    "bob",
+		KeysDir:      t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	profile := &client.ProfileStatus{
+		Name:     "example.com",
+		Username: "bob",
+		Dir:      "/tmp",
+	}
+	writeFakeDatabaseCredentials(t, profile)
+
+	t.Run("postgres appends passthrough args at the end", func(t *testing.T) {
+		database := &tlsca.RouteToDatabase{
+			Protocol:    defaults.ProtocolPostgres,
+			Database:    "mydb",
+			Username:    "myUser",
+			ServiceName: "mysql",
+		}
+
+		c := NewCmdBuilder(tc, profile, database, "root",
+			WithLocalProxy("localhost", 12345, ""),
+			WithPassthroughArgs([]string{"-v", "FOO=bar"}),
+		)
+		c.uid = utils.NewFakeUID()
+		c.exe = &fakeExec{execOutput: map[string][]byte{postgresBin: {}}}
+
+		got, err := c.GetConnectCommand()
+		require.NoError(t, err)
+		require.Equal(t, []string{"-v", "FOO=bar"}, got.Args[len(got.Args)-2:])
+	})
+
+	t.Run("mongo inserts passthrough args before the trailing database name", func(t *testing.T) {
+		database := &tlsca.RouteToDatabase{
+			Protocol:    defaults.ProtocolMongoDB,
+			Database:    "mydb",
+			Username:    "myUser",
+			ServiceName: "mongo",
+		}
+
+		c := NewCmdBuilder(tc, profile, database, "root",
+			WithLocalProxy("localhost", 12345, ""),
+			WithNoTLS(),
+			WithPassthroughArgs([]string{"--quiet"}),
+		)
+		c.uid = utils.NewFakeUID()
+		c.exe = &fakeExec{execOutput: map[string][]byte{mongoshBin: {}}}
+
+		got, err := c.GetConnectCommand()
+		require.NoError(t, err)
+		require.Equal(t, []string{"--quiet", "mydb"}, got.Args[len(got.Args)-2:])
+	})
+}
+
+func TestDoctor(t *testing.T) {
+	exe := &fakeExec{execOutput: map[string][]byte{
+		postgresBin: []byte("psql (PostgreSQL) 15.2\n"),
+	}}
+
+	checks := Doctor(exe)
+	require.NotEmpty(t, checks)
+
+	byProtocol := make(map[string]ClientCheck, len(checks))
+	for _, check := range checks {
+		byProtocol[check.Protocol] = check
+	}
+
+	postgresCheck, ok := byProtocol[defaults.ProtocolPostgres]
+	require.True(t, ok)
+	require.True(t, postgresCheck.Found)
+	require.Equal(t, "psql (PostgreSQL) 15.2", postgresCheck.Version)
+
+	mysqlCheck, ok := byProtocol[defaults.ProtocolMySQL]
+	require.True(t, ok)
+	require.False(t, mysqlCheck.Found)
+	require.Empty(t, mysqlCheck.Version)
+}
+
+func TestCLICommandBuilderCustomBinary(t *testing.T) {
+	tc, err := client.NewClient(&client.Config{
+		WebProxyAddr: "proxy:3080",
+		SiteName:     "db.example.com",
+		Username:     "bob",
+		HostLogin:    "bob",
+		KeysDir:      t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	profile := &client.ProfileStatus{
+		Name:     "example.com",
+		Username: "bob",
+		Dir:      "/tmp",
+	}
+	writeFakeDatabaseCredentials(t, profile)
+
+	t.Run("postgres uses the custom binary without probing PATH", func(t *testing.T) {
+		database := &tlsca.RouteToDatabase{
+			Protocol:    defaults.ProtocolPostgres,
+			Database:    "mydb",
+			Username:    "myUser",
+			ServiceName: "mysql",
+		}
+
+		c := NewCmdBuilder(tc, profile, database, "root",
+			WithLocalProxy("localhost", 12345, ""),
+			WithCustomBinary(defaults.ProtocolPostgres, "/opt/postgres15/bin/psql"),
+		)
+		c.uid = utils.NewFakeUID()
+		// No binaries registered in execOutput: a real detection attempt
+		// would fail LookPath and this test would catch it.
+		c.exe = &fakeExec{}
+
+		got, err := c.GetConnectCommand()
+		require.NoError(t, err)
+		require.Equal(t, "/opt/postgres15/bin/psql", got.Path)
+	})
+
+	t.Run("mysql uses the custom binary without flavor detection", func(t *testing.T) {
+		database := &tlsca.RouteToDatabase{
+			Protocol:    defaults.ProtocolMySQL,
+			Database:    "mydb",
+			Username:    "myUser",
+			ServiceName: "mysql",
+		}
+
+		c := NewCmdBuilder(tc, profile, database, "root",
+			WithLocalProxy("localhost", 12345, ""),
+			WithCustomBinary(defaults.ProtocolMySQL, "/opt/mysql8/bin/mysql"),
+		)
+		c.uid = utils.NewFakeUID()
+		c.exe = &fakeExec{}
+
+		got, err := c.GetConnectCommand()
+		require.NoError(t, err)
+		require.Equal(t, "/opt/mysql8/bin/mysql", got.Path)
+	})
+}
+
+func TestCLICommandBuilderCommandPolicy(t *testing.T) {
+	tc, err := client.NewClient(&client.Config{
+		WebProxyAddr: "proxy:3080",
+		SiteName:     "db.example.com",
+		Username:     "bob",
+		HostLogin:    "bob",
+		KeysDir:      t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	profile := &client.ProfileStatus{
+		Name:     "example.com",
+		Username: "bob",
+		Dir:      "/tmp",
+	}
+	writeFakeDatabaseCredentials(t, profile)
+
+	database := &tlsca.RouteToDatabase{
+		Protocol:    defaults.ProtocolPostgres,
+		Database:    "mydb",
+		Username:    "myUser",
+		ServiceName: "mysql",
+	}
+
+	forbidPlaintextConnection := func(cmd *exec.Cmd) error {
+		for _, arg := range cmd.Args {
+			if strings.Contains(arg, "sslmode=disable") {
+				return trace.BadParameter("sslmode=disable is forbidden by policy")
+			}
+		}
+		return nil
+	}
+
+	c := NewCmdBuilder(tc, profile, database, "root",
+		WithLocalProxy("localhost", 12345, ""),
+		WithExtraArgs(map[string][]string{defaults.ProtocolPostgres: {"sslmode=disable"}}),
+		WithCommandPolicy(forbidPlaintextConnection),
+	)
+	c.uid = utils.NewFakeUID()
+	c.exe = &fakeExec{execOutput: map[string][]byte{postgresBin: {}}}
+
+	_, err = c.GetConnectCommand()
+	require.Error(t, err)
+	require.True(t, trace.IsAccessDenied(err), "expected an access denied error, got %T: %v", err, err)
+
+	c = NewCmdBuilder(tc, profile, database, "root",
+		WithLocalProxy("localhost", 12345, ""),
+		WithCommandPolicy(forbidPlaintextConnection),
+	)
+	c.uid = utils.NewFakeUID()
+	c.exe = &fakeExec{execOutput: map[string][]byte{postgresBin: {}}}
+
+	_, err = c.GetConnectCommand()
+	require.NoError(t, err)
+}
+
+func TestCLICommandBuilderPipedIO(t *testing.T) {
+	tc, err := client.NewClient(&client.Config{
+		WebProxyAddr: "proxy:3080",
+		SiteName:     "db.example.com",
+		Username:     "bob",
+		HostLogin:    "bob",
+		KeysDir:      t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	profile := &client.ProfileStatus{
+		Name:     "example.com",
+		Username: "bob",
+		Dir:      "/tmp",
+	}
+	writeFakeDatabaseCredentials(t, profile)
+
+	database := &tlsca.RouteToDatabase{
+		Protocol:    defaults.ProtocolPostgres,
+		Database:    "mydb",
+		Username:    "myUser",
+		ServiceName: "mysql",
+	}
+
+	c := NewCmdBuilder(tc, profile, database, "root", WithLocalProxy("localhost", 12345, ""))
+	c.uid = utils.NewFakeUID()
+	c.exe = &fakeExec{execOutput: map[string][]byte{postgresBin: {}}}
+	got, err := c.GetConnectCommand()
+	require.NoError(t, err)
+	require.Equal(t, os.Stdin, got.Stdin)
+	require.Equal(t, os.Stdout, got.Stdout)
+	require.Equal(t, os.Stderr, got.Stderr)
+
+	c = NewCmdBuilder(tc, profile, database, "root",
+		WithLocalProxy("localhost", 12345, ""),
+		WithPipedIO(),
+	)
+	c.uid = utils.NewFakeUID()
+	c.exe = &fakeExec{execOutput: map[string][]byte{postgresBin: {}}}
+	got, err = c.GetConnectCommand()
+	require.NoError(t, err)
+	require.Nil(t, got.Stdin)
+	require.Nil(t, got.Stdout)
+	require.Nil(t, got.Stderr)
+}
+
+// TestCLICommandBuilderGetConnectCommandNoAbsolutePath verifies that
+// GetConnectCommandNoAbsolutePath strips any directory component from the
+// returned command's Path for every protocol registered in
+// defaults.DatabaseProtocols, so the invariant automatically covers
+// protocols added to that list in the future. Each entry here supplies just
+// enough execer/credential setup for its protocol's GetConnectCommand to
+// succeed; a protocol missing an entry fails loudly rather than being
+// silently skipped.
+func TestCLICommandBuilderGetConnectCommandNoAbsolutePath(t *testing.T) {
+	tc, err := client.NewClient(&client.Config{
+		WebProxyAddr: "proxy:3080",
+		SiteName:     "db.example.com",
+		Username:     "bob",
+		HostLogin:    "bob",
+		KeysDir:      t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	profile := &client.ProfileStatus{
+		Name:     "example.com",
+		Username: "bob",
+		Dir:      "/tmp",
+	}
+	writeFakeDatabaseCredentials(t, profile)
+
+	execerByProtocol := map[string]*fakeExec{
+		defaults.ProtocolPostgres:       {execOutput: map[string][]byte{postgresBin: []byte("")}},
+		defaults.ProtocolCockroachDB:    {execOutput: map[string][]byte{"cockroach": []byte("")}},
+		defaults.ProtocolMySQL:          {execOutput: map[string][]byte{"mariadb": []byte("")}},
+		defaults.ProtocolMongoDB:        {execOutput: map[string][]byte{"mongosh": []byte("1.1.6")}},
+		defaults.ProtocolRedis:          {},
+		defaults.ProtocolSQLServer:      {execOutput: map[string][]byte{mssqlBin: []byte("")}},
+		defaults.ProtocolCassandra:      {},
+		defaults.ProtocolElasticsearch:  {execOutput: map[string][]byte{"elasticsearch-sql-cli": []byte("")}},
+		defaults.ProtocolOracle:         {execOutput: map[string][]byte{"sql": []byte("")}},
+		defaults.ProtocolClickHouse:     {execOutput: map[string][]byte{"clickhouse-client": []byte("")}},
+		defaults.ProtocolClickHouseHTTP: {execOutput: map[string][]byte{"clickhouse-client": []byte("")}},
+		defaults.ProtocolSnowflake:      {},
+		defaults.ProtocolDynamoDB:       {execOutput: map[string][]byte{"aws": []byte("")}},
+		defaults.ProtocolNeo4j:          {},
+	}
+
+	for _, protocol := range defaults.DatabaseProtocols {
+		protocol := protocol
+		t.Run(protocol, func(t *testing.T) {
+			t.Parallel()
+
+			execer, ok := execerByProtocol[protocol]
+			require.True(t, ok, "no test fixture registered for database protocol %q; add one above", protocol)
+
+			database := &tlsca.RouteToDatabase{
+				Protocol:    protocol,
+				Database:    "mydb",
+				Username:    "myUser",
+				ServiceName: "mysql",
+			}
+
+			c := NewCmdBuilder(tc, profile, database, "root", WithLocalProxy("localhost", 12345, ""))
+			c.uid = utils.NewFakeUID()
+			c.exe = execer
+
+			cmd, err := c.GetConnectCommandNoAbsolutePath()
+			require.NoError(t, err)
+			require.Equal(t, filepath.Base(cmd.Path), cmd.Path, "Path %q for protocol %q still has a directory component", cmd.Path, protocol)
+		})
+	}
+}
+
+func TestCLICommandBuilderGetConnectCommandEnv(t *testing.T) {
+	tc, err := client.NewClient(&client.Config{
+		WebProxyAddr: "proxy:3080",
+		SiteName:     "db.example.com",
+		Username:     "bob",
+		HostLogin:    "bob",
+		KeysDir:      t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	profile := &client.ProfileStatus{
+		Name:     "example.com",
+		Username: "bob",
+		Dir:      "/tmp",
+	}
+	writeFakeDatabaseCredentials(t, profile)
+
+	t.Run("mysql moves host, port, and password to the environment", func(t *testing.T) {
+		t.Setenv("mysql.mysql-password", "s3cr3t")
+		database := &tlsca.RouteToDatabase{
+			Protocol:    defaults.ProtocolMySQL,
+			Database:    "mydb",
+			Username:    "myUser",
+			ServiceName: "mysql",
+		}
+		c := NewCmdBuilder(tc, profile, database, "root", WithLocalProxy("localhost", 12345, ""), WithSecretStore(EnvSecretStore{}))
+
+		cmd, err := c.GetConnectCommandEnv()
+		require.NoError(t, err)
+		require.Equal(t, mysqlBin, filepath.Base(cmd.Path))
+		require.Contains(t, cmd.Env, "MYSQL_HOST=localhost")
+		require.Contains(t, cmd.Env, "MYSQL_TCP_PORT=12345")
+		require.Contains(t, cmd.Env, "MYSQL_PWD=s3cr3t")
+		require.NotContains(t, cmd.Args, "s3cr3t")
+		require.Contains(t, cmd.Args, "--user")
+		require.Contains(t, cmd.Args, "--database")
+		require.Contains(t, cmd.Args, "--ssl-ca")
+	})
+
+	t.Run("redis has no host/port/TLS env vars, so it falls back to flags plus REDISCLI_AUTH", func(t *testing.T) {
+		t.Setenv("mysql.redis-auth-token", "s3cr3t")
+		database := &tlsca.RouteToDatabase{
+			Protocol:    defaults.ProtocolRedis,
+			Username:    "myUser",
+			ServiceName: "mysql",
+		}
+		c := NewCmdBuilder(tc, profile, database, "root", WithLocalProxy("localhost", 12345, ""), WithSecretStore(EnvSecretStore{}))
+
+		cmd, err := c.GetConnectCommandEnv()
+		require.NoError(t, err)
+		require.Contains(t, cmd.Env, "REDISCLI_AUTH=s3cr3t")
+		require.Contains(t, cmd.Args, "-h")
+	})
+
+	t.Run("postgres with no connection profile on disk returns not found", func(t *testing.T) {
+		database := &tlsca.RouteToDatabase{
+			Protocol:    defaults.ProtocolPostgres,
+			Database:    "mydb",
+			Username:    "myUser",
+			ServiceName: "no-such-service-" + t.Name(),
+		}
+		c := NewCmdBuilder(tc, profile, database, "root", WithLocalProxy("localhost", 12345, ""))
+
+		_, err := c.GetConnectCommandEnv()
+		require.True(t, trace.IsNotFound(err), "expected a not found error, got %v", err)
+	})
+
+	t.Run("unsupported protocol returns bad parameter", func(t *testing.T) {
+		database := &tlsca.RouteToDatabase{
+			Protocol:    defaults.ProtocolMongoDB,
+			ServiceName: "mysql",
+		}
+		c := NewCmdBuilder(tc, profile, database, "root", WithLocalProxy("localhost", 12345, ""))
+
+		_, err := c.GetConnectCommandEnv()
+		require.True(t, trace.IsBadParameter(err), "expected a bad parameter error, got %v", err)
+	})
+}
+
+func TestCLICommandBuilderWriteActivationScripts(t *testing.T) {
+	tc, err := client.NewClient(&client.Config{
+		WebProxyAddr: "proxy:3080",
+		SiteName:     "db.example.com",
+		Username:     "bob",
+		HostLogin:    "bob",
+		KeysDir:      t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	profile := &client.ProfileStatus{
+		Name:     "example.com",
+		Username: "bob",
+		Dir:      "/tmp",
+	}
+	writeFakeDatabaseCredentials(t, profile)
+
+	t.Setenv("mysql.mysql-password", "s3cr3t")
+	database := &tlsca.RouteToDatabase{
+		Protocol:    defaults.ProtocolMySQL,
+		Database:    "mydb",
+		Username:    "myUser",
+		ServiceName: "mysql",
+	}
+	c := NewCmdBuilder(tc, profile, database, "root", WithLocalProxy("localhost", 12345, ""), WithSecretStore(EnvSecretStore{}))
+
+	dir := filepath.Join(t.TempDir(), "teleport-mysql")
+	require.NoError(t, c.WriteActivationScripts(dir))
+
+	shimPath := filepath.Join(dir, "bin", mysqlBin)
+	shimInfo, err := os.Stat(shimPath)
+	require.NoError(t, err)
+	require.NotZero(t, shimInfo.Mode()&0100, "shim script should be executable")
+	shim, err := os.ReadFile(shimPath)
+	require.NoError(t, err)
+	require.Contains(t, string(shim), "exec '"+mysqlBin+"'")
+	require.Contains(t, string(shim), "--ssl-ca")
+	require.NotContains(t, string(shim), "s3cr3t")
+
+	activate, err := os.ReadFile(filepath.Join(dir, "activate"))
+	require.NoError(t, err)
+	require.Contains(t, string(activate), "export PATH='"+filepath.Join(dir, "bin")+"'")
+	require.Contains(t, string(activate), "export MYSQL_HOST='localhost'")
+	require.Contains(t, string(activate), "export MYSQL_PWD='s3cr3t'")
+	require.Contains(t, string(activate), "deactivate() {")
+	require.Contains(t, string(activate), `unset MYSQL_PWD`)
+}
+
+func TestCLICommandBuilderConnectionParams(t *testing.T) {
+	tc, err := client.NewClient(&client.Config{
+		WebProxyAddr: "proxy:3080",
+		SiteName:     "db.example.com",
+		Username:     "bob",
+		HostLogin:    "bob",
+		KeysDir:      t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	profile := &client.ProfileStatus{
+		Name:     "example.com",
+		Username: "bob",
+		Dir:      "/tmp",
+	}
+	writeFakeDatabaseCredentials(t, profile)
+
+	database := &tlsca.RouteToDatabase{
+		Protocol:    defaults.ProtocolPostgres,
+		Database:    "mydb",
+		Username:    "myUser",
+		ServiceName: "mysql",
+	}
+
+	t.Run("verify-full TLS mode includes cert paths", func(t *testing.T) {
+		c := NewCmdBuilder(tc, profile, database, "root", WithLocalProxy("localhost", 12345, ""))
+		params := c.ConnectionParams()
+		require.Equal(t, defaults.ProtocolPostgres, params.Protocol)
+		require.Equal(t, "localhost", params.Host)
+		require.Equal(t, 12345, params.Port)
+		require.Equal(t, "myUser", params.User)
+		require.Equal(t, "mydb", params.Database)
+		require.Equal(t, TLSModeVerifyFull, params.TLSMode)
+		require.Equal(t, c.getCAPath(), params.CACertPath)
+		require.Equal(t, c.getCertPath(), params.CertPath)
+		require.Equal(t, c.getKeyPath(), params.KeyPath)
+	})
+
+	t.Run("no TLS mode omits cert paths", func(t *testing.T) {
+		c := NewCmdBuilder(tc, profile, database, "root", WithLocalProxy("localhost", 12345, ""), WithNoTLS())
+		params := c.ConnectionParams()
+		require.Equal(t, TLSModeDisabled, params.TLSMode)
+		require.Empty(t, params.CACertPath)
+		require.Empty(t, params.CertPath)
+		require.Empty(t, params.KeyPath)
+	})
+
+	t.Run("redis cluster mode surfaces as a protocol-specific option", func(t *testing.T) {
+		redisDatabase := &tlsca.RouteToDatabase{
+			Protocol:    defaults.ProtocolRedis,
+			Username:    "myUser",
+			ServiceName: "mysql",
+		}
+		c := NewCmdBuilder(tc, profile, redisDatabase, "root", WithLocalProxy("localhost", 12345, ""), WithRedisClusterMode(true))
+		params := c.ConnectionParams()
+		require.Equal(t, map[string]string{"clusterMode": "true"}, params.Options)
+	})
+}
+
+func TestCLICommandBuilderJDBCAndODBC(t *testing.T) {
+	tc, err := client.NewClient(&client.Config{
+		WebProxyAddr: "proxy:3080",
+		SiteName:     "db.example.com",
+		Username:     "bob",
+		HostLogin:    "bob",
+		KeysDir:      t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	profile := &client.ProfileStatus{
+		Name:     "example.com",
+		Username: "bob",
+		Dir:      "/tmp",
+	}
+	writeFakeDatabaseCredentials(t, profile)
+
+	postgres := &tlsca.RouteToDatabase{
+		Protocol:    defaults.ProtocolPostgres,
+		Database:    "mydb",
+		Username:    "myUser",
+		ServiceName: "postgres",
+	}
+
+	t.Run("postgres JDBC URL includes TLS params", func(t *testing.T) {
+		c := NewCmdBuilder(tc, profile, postgres, "root", WithLocalProxy("localhost", 12345, ""))
+		jdbcURL, err := c.GetJDBCConnectionURL()
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(jdbcURL, "jdbc:postgresql://localhost:12345/mydb?"))
+		require.Contains(t, jdbcURL, "sslmode=verify-full")
+		require.Contains(t, jdbcURL, "user=myUser")
+	})
+
+	t.Run("postgres ODBC string includes TLS params", func(t *testing.T) {
+		c := NewCmdBuilder(tc, profile, postgres, "root", WithLocalProxy("localhost", 12345, ""))
+		odbcString, err := c.GetODBCConnectionString()
+		require.NoError(t, err)
+		require.Contains(t, odbcString, "Server=localhost;")
+		require.Contains(t, odbcString, "Port=12345;")
+		require.Contains(t, odbcString, "SSLmode=verify-full;")
+	})
+
+	t.Run("no TLS mode disables SSL in both formats", func(t *testing.T) {
+		c := NewCmdBuilder(tc, profile, postgres, "root", WithLocalProxy("localhost", 12345, ""), WithNoTLS())
+		jdbcURL, err := c.GetJDBCConnectionURL()
+		require.NoError(t, err)
+		require.Contains(t, jdbcURL, "sslmode=disable")
+
+		odbcString, err := c.GetODBCConnectionString()
+		require.NoError(t, err)
+		require.Contains(t, odbcString, "SSLmode=disable;")
+	})
+
+	t.Run("unsupported protocol returns bad parameter", func(t *testing.T) {
+		mongoDatabase := &tlsca.RouteToDatabase{
+			Protocol:    defaults.ProtocolMongoDB,
+			ServiceName: "mongo",
+		}
+		c := NewCmdBuilder(tc, profile, mongoDatabase, "root", WithLocalProxy("localhost", 12345, ""))
+		_, err := c.GetJDBCConnectionURL()
+		require.True(t, trace.IsBadParameter(err))
+		_, err = c.GetODBCConnectionString()
+		require.True(t, trace.IsBadParameter(err))
+	})
+}
+
+func TestCleanupGeneratedFiles(t *testing.T) {
+	dir := t.TempDir()
+	profile := &client.ProfileStatus{
+		Name:     "example.com",
+		Username: "bob",
+		Dir:      dir,
+	}
+
+	// No manifest yet: should be a no-op, not an error.
+	require.NoError(t, CleanupGeneratedFiles(profile))
+
+	chainPath := filepath.Join(dir, "some-chain.pem")
+	require.NoError(t, os.WriteFile(chainPath, []byte("chain"), 0600))
+	require.NoError(t, trackGeneratedFile(profile, chainPath))
+
+	// Tracking the same file twice shouldn't duplicate the manifest entry.
+	require.NoError(t, trackGeneratedFile(profile, chainPath))
+	manifest, err := readGeneratedFilesManifest(profile.GeneratedDatabaseFilesPath())
+	require.NoError(t, err)
+	require.Equal(t, []string{chainPath}, manifest.Files)
+
+	require.NoError(t, CleanupGeneratedFiles(profile))
+	require.NoFileExists(t, chainPath)
+	require.NoFileExists(t, profile.GeneratedDatabaseFilesPath())
+}
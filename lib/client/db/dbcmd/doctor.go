@@ -0,0 +1,100 @@
+/*
+
+ Copyright 2023 Gravitational, Inc.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+
+*/
+
+package dbcmd
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/gravitational/teleport/lib/defaults"
+)
+
+// ClientCheck reports one native database client's availability on the
+// local machine, as returned by Doctor.
+type ClientCheck struct {
+	// Protocol is the Teleport database protocol this client is used for.
+	Protocol string
+	// Binary is the executable name looked up on $PATH.
+	Binary string
+	// Found reports whether Binary was located on $PATH.
+	Found bool
+	// Path is the resolved path to Binary, empty if it wasn't found.
+	Path string
+	// Version is the first line of "<Binary> --version" output, empty if
+	// the binary wasn't found or didn't respond to --version.
+	Version string
+}
+
+// doctorBinaries lists the default native client binary checked for each
+// database protocol that has one well-known client. Protocols with several
+// interchangeable clients (e.g. postgres's psql/pgcli, mysql's
+// mysql/mariadb/mysqlsh) report their stock pick here; a deployment that
+// overrides the client via WithPreferredClient or WithCustomBinary should
+// check its configured binary directly instead.
+var doctorBinaries = map[string]string{
+	defaults.ProtocolPostgres:       postgresBin,
+	defaults.ProtocolCockroachDB:    cockroachBin,
+	defaults.ProtocolMySQL:          mysqlBin,
+	defaults.ProtocolMongoDB:        mongoshBin,
+	defaults.ProtocolRedis:          redisBin,
+	defaults.ProtocolSQLServer:      sqlcmdBin,
+	defaults.ProtocolCassandra:      cqlshBin,
+	defaults.ProtocolElasticsearch:  elasticsearchSQLCliBin,
+	defaults.ProtocolOracle:         sqlclBin,
+	defaults.ProtocolClickHouse:     clickhouseBin,
+	defaults.ProtocolClickHouseHTTP: clickhouseBin,
+	defaults.ProtocolSnowflake:      snowsqlBin,
+	defaults.ProtocolDynamoDB:       awsBin,
+	defaults.ProtocolNeo4j:          cypherShellBin,
+}
+
+// Doctor reports, for every supported database protocol, whether its
+// default native client is installed in $PATH and what version it reports,
+// so users can fix their toolchain before running "tsh db connect". exe is
+// the same execer abstraction CLICommandBuilder uses, so callers can fake
+// it out in tests without touching the real $PATH. Checks are returned
+// sorted by protocol for stable output.
+func Doctor(exe Execer) []ClientCheck {
+	protocols := make([]string, 0, len(doctorBinaries))
+	for protocol := range doctorBinaries {
+		protocols = append(protocols, protocol)
+	}
+	sort.Strings(protocols)
+
+	checks := make([]ClientCheck, 0, len(protocols))
+	for _, protocol := range protocols {
+		bin := doctorBinaries[protocol]
+		check := ClientCheck{Protocol: protocol, Binary: bin}
+		if path, err := exe.LookPath(bin); err == nil {
+			check.Found = true
+			check.Path = path
+			if out, err := exe.RunCommand(bin, "--version"); err == nil {
+				check.Version = firstLine(string(out))
+			}
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// firstLine returns the first line of s, trimmed of surrounding whitespace.
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return strings.TrimSpace(line)
+}
@@ -0,0 +1,147 @@
+/*
+
+ Copyright 2023 Gravitational, Inc.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+
+*/
+
+package dbcmd
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+
+	"github.com/gravitational/trace"
+
+	"github.com/gravitational/teleport/lib/client/db"
+	"github.com/gravitational/teleport/lib/defaults"
+)
+
+// GetConnectCommandEnv is like GetConnectCommand, but for clients whose
+// environment variables can carry the connection details instead of
+// command-line flags or a URL. This keeps the proxy address and credentials
+// out of "ps" output and lets GUI integrations reuse the environment rather
+// than re-parsing argv.
+//
+// Only Postgres, MySQL, and Redis are supported, and not all connection
+// details move to the environment for all three: Postgres clients recognize
+// an environment variable for every relevant setting, but MySQL and Redis
+// clients only have environment variables for a subset (MySQL has none for
+// the username, database, or TLS material; Redis has none beyond the AUTH
+// password), so the returned command still carries flags for whatever the
+// client has no environment variable for. Other protocols return
+// trace.BadParameter, same as GetConnectionURI.
+func (c *CLICommandBuilder) GetConnectCommandEnv() (*exec.Cmd, error) {
+	binary, args, env, err := c.getConnectCommandEnv()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	cmd := exec.Command(binary, args...)
+	cmd.Env = append(os.Environ(), envSlice(env)...)
+	return cmd, nil
+}
+
+// getConnectCommandEnv is GetConnectCommandEnv split into its binary, args,
+// and the env vars it layers on top of the current process's environment,
+// so callers that need the env vars on their own (e.g. writeActivation,
+// which embeds them in a sourceable script rather than an *exec.Cmd) don't
+// have to diff them back out of a merged os.Environ().
+func (c *CLICommandBuilder) getConnectCommandEnv() (binary string, args []string, env map[string]string, err error) {
+	switch c.db.Protocol {
+	case defaults.ProtocolPostgres:
+		return c.getPostgresCommandEnv()
+	case defaults.ProtocolMySQL:
+		return c.getMySQLCommandEnv()
+	case defaults.ProtocolRedis:
+		// redis-cli has no environment variable for the host, port, or TLS
+		// material, only for the AUTH password, which getRedisCommand
+		// already passes via REDISCLI_AUTH instead of -a.
+		cmd, err := c.getRedisCommand()
+		if err != nil {
+			return "", nil, nil, trace.Wrap(err)
+		}
+		env := map[string]string{}
+		if authToken, ok := c.getSecret(c.db.ServiceName + ".redis-auth-token"); ok {
+			env["REDISCLI_AUTH"] = authToken
+		}
+		return cmd.Path, cmd.Args[1:], env, nil
+	}
+	return "", nil, nil, trace.BadParameter("environment variable connection mode is not supported for %q", c.db.Protocol)
+}
+
+// getPostgresCommandEnv returns psql with no connection flags at all, since
+// the profile connection file written by "tsh db login" (lib/client/db.Add)
+// covers every setting psql needs through PGHOST/PGPORT/PGUSER/PGDATABASE/
+// PGSSLMODE/PGSSLROOTCERT/PGSSLCERT/PGSSLKEY.
+func (c *CLICommandBuilder) getPostgresCommandEnv() (string, []string, map[string]string, error) {
+	env, err := db.Env(c.tc, c.routeWithDefaultDatabase())
+	if err != nil {
+		return "", nil, nil, trace.Wrap(err)
+	}
+	return postgresBin, nil, env, nil
+}
+
+// getMySQLCommandEnv returns mysql with the host, port, and password (when
+// using password auth) moved to MYSQL_HOST/MYSQL_TCP_PORT/MYSQL_PWD. The
+// user, database, and TLS material stay as flags, since the mysql client
+// has no environment variable equivalents for them.
+func (c *CLICommandBuilder) getMySQLCommandEnv() (string, []string, map[string]string, error) {
+	var args []string
+	if c.db.Username != "" {
+		args = append(args, "--user", c.db.Username)
+	}
+	if dbName := c.databaseName(); dbName != "" {
+		args = append(args, "--database", dbName)
+	}
+	if initCommand, ok := c.mysqlInitCommand(); ok {
+		args = append(args, "--init-command="+initCommand)
+	}
+
+	env := map[string]string{
+		"MYSQL_HOST":     c.host,
+		"MYSQL_TCP_PORT": strconv.Itoa(c.port),
+	}
+
+	if !c.options.noTLS {
+		if err := c.checkCredentialFile(c.getKeyPath(), "private key"); err != nil {
+			return "", nil, nil, trace.Wrap(err)
+		}
+		if err := c.checkCredentialFile(c.getCertPath(), "certificate"); err != nil {
+			return "", nil, nil, trace.Wrap(err)
+		}
+		if err := c.checkCredentialFile(c.getCAPath(), "CA certificate"); err != nil {
+			return "", nil, nil, trace.Wrap(err)
+		}
+		args = append(args, "--ssl-ca", c.getCAPath(), "--ssl-cert", c.getCertPath(), "--ssl-key", c.getKeyPath())
+		if !c.tc.InsecureSkipVerify {
+			args = append(args, "--ssl-verify-server-cert")
+		}
+	}
+
+	if password, ok := c.getSecret(c.db.ServiceName + ".mysql-password"); ok {
+		env["MYSQL_PWD"] = password
+	}
+
+	return mysqlBin, args, env, nil
+}
+
+// envSlice renders env as "KEY=value" pairs suitable for exec.Cmd.Env.
+func envSlice(env map[string]string) []string {
+	out := make([]string, 0, len(env))
+	for k, v := range env {
+		out = append(out, k+"="+v)
+	}
+	return out
+}
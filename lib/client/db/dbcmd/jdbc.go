@@ -0,0 +1,205 @@
+/*
+
+ Copyright 2023 Gravitational, Inc.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+
+*/
+
+package dbcmd
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/gravitational/trace"
+
+	"github.com/gravitational/teleport/lib/defaults"
+)
+
+// GetJDBCConnectionURL returns a JDBC connection URL for c's database,
+// suitable for pasting into a JDBC-based GUI client (DataGrip, DBeaver)
+// without hand-assembling TLS parameters.
+func (c *CLICommandBuilder) GetJDBCConnectionURL() (string, error) {
+	params := c.ConnectionParams()
+	switch params.Protocol {
+	case defaults.ProtocolPostgres, defaults.ProtocolCockroachDB:
+		return jdbcPostgresURL(params), nil
+	case defaults.ProtocolMySQL:
+		return jdbcMySQLURL(params), nil
+	case defaults.ProtocolSQLServer:
+		return jdbcSQLServerURL(params), nil
+	default:
+		return "", trace.BadParameter("JDBC connection strings are not supported for %q", params.Protocol)
+	}
+}
+
+// GetODBCConnectionString returns an ODBC DSN-style connection string for
+// c's database, suitable for pasting into an ODBC-based GUI client.
+func (c *CLICommandBuilder) GetODBCConnectionString() (string, error) {
+	params := c.ConnectionParams()
+	switch params.Protocol {
+	case defaults.ProtocolPostgres, defaults.ProtocolCockroachDB:
+		return odbcPostgresString(params), nil
+	case defaults.ProtocolMySQL:
+		return odbcMySQLString(params), nil
+	case defaults.ProtocolSQLServer:
+		return odbcSQLServerString(params), nil
+	default:
+		return "", trace.BadParameter("ODBC connection strings are not supported for %q", params.Protocol)
+	}
+}
+
+func jdbcPostgresURL(params ConnectionParams) string {
+	values := url.Values{}
+	if params.User != "" {
+		values.Set("user", params.User)
+	}
+	setJDBCTLSParams(values, params, "sslmode", "sslrootcert", "sslcert", "sslkey")
+	return fmt.Sprintf("jdbc:postgresql://%s:%d/%s?%s", params.Host, params.Port, params.Database, values.Encode())
+}
+
+func jdbcMySQLURL(params ConnectionParams) string {
+	values := url.Values{}
+	if params.User != "" {
+		values.Set("user", params.User)
+	}
+	switch params.TLSMode {
+	case TLSModeDisabled:
+		values.Set("useSSL", "false")
+	case TLSModeInsecure:
+		values.Set("useSSL", "true")
+		values.Set("verifyServerCertificate", "false")
+	default:
+		values.Set("useSSL", "true")
+		values.Set("verifyServerCertificate", "true")
+		values.Set("clientCertificateKeyStoreUrl", "file:"+params.CertPath)
+		values.Set("trustCertificateKeyStoreUrl", "file:"+params.CACertPath)
+	}
+	return fmt.Sprintf("jdbc:mysql://%s:%d/%s?%s", params.Host, params.Port, params.Database, values.Encode())
+}
+
+func jdbcSQLServerURL(params ConnectionParams) string {
+	values := url.Values{}
+	if params.Database != "" {
+		values.Set("databaseName", params.Database)
+	}
+	if params.User != "" {
+		values.Set("user", params.User)
+	}
+	switch params.TLSMode {
+	case TLSModeDisabled:
+		values.Set("encrypt", "false")
+	case TLSModeInsecure:
+		values.Set("encrypt", "true")
+		values.Set("trustServerCertificate", "true")
+	default:
+		values.Set("encrypt", "true")
+		values.Set("trustServerCertificate", "false")
+		values.Set("trustStore", params.CACertPath)
+	}
+	return fmt.Sprintf("jdbc:sqlserver://%s:%d;%s", params.Host, params.Port, strings.ReplaceAll(values.Encode(), "&", ";"))
+}
+
+// setJDBCTLSParams fills in libpq-style sslmode/sslrootcert/sslcert/sslkey
+// parameters from params.TLSMode, under the given query param names.
+func setJDBCTLSParams(values url.Values, params ConnectionParams, sslModeKey, sslRootCertKey, sslCertKey, sslKeyKey string) {
+	switch params.TLSMode {
+	case TLSModeDisabled:
+		values.Set(sslModeKey, "disable")
+	case TLSModeInsecure:
+		values.Set(sslModeKey, "require")
+	default:
+		values.Set(sslModeKey, "verify-full")
+		values.Set(sslRootCertKey, params.CACertPath)
+		values.Set(sslCertKey, params.CertPath)
+		values.Set(sslKeyKey, params.KeyPath)
+	}
+}
+
+func odbcPostgresString(params ConnectionParams) string {
+	pairs := []string{
+		"Driver={PostgreSQL Unicode}",
+		fmt.Sprintf("Server=%s", params.Host),
+		fmt.Sprintf("Port=%d", params.Port),
+		fmt.Sprintf("Database=%s", params.Database),
+	}
+	if params.User != "" {
+		pairs = append(pairs, fmt.Sprintf("Uid=%s", params.User))
+	}
+	switch params.TLSMode {
+	case TLSModeDisabled:
+		pairs = append(pairs, "SSLmode=disable")
+	case TLSModeInsecure:
+		pairs = append(pairs, "SSLmode=require")
+	default:
+		pairs = append(pairs,
+			"SSLmode=verify-full",
+			fmt.Sprintf("SSLrootcert=%s", params.CACertPath),
+			fmt.Sprintf("SSLcert=%s", params.CertPath),
+			fmt.Sprintf("SSLkey=%s", params.KeyPath),
+		)
+	}
+	return strings.Join(pairs, ";") + ";"
+}
+
+func odbcMySQLString(params ConnectionParams) string {
+	pairs := []string{
+		"Driver={MySQL ODBC 8.0 Unicode Driver}",
+		fmt.Sprintf("Server=%s", params.Host),
+		fmt.Sprintf("Port=%d", params.Port),
+		fmt.Sprintf("Database=%s", params.Database),
+	}
+	if params.User != "" {
+		pairs = append(pairs, fmt.Sprintf("User=%s", params.User))
+	}
+	switch params.TLSMode {
+	case TLSModeDisabled:
+		pairs = append(pairs, "SSLMODE=DISABLED")
+	case TLSModeInsecure:
+		pairs = append(pairs, "SSLMODE=REQUIRED")
+	default:
+		pairs = append(pairs,
+			"SSLMODE=VERIFY_IDENTITY",
+			fmt.Sprintf("SSLCA=%s", params.CACertPath),
+			fmt.Sprintf("SSLCERT=%s", params.CertPath),
+			fmt.Sprintf("SSLKEY=%s", params.KeyPath),
+		)
+	}
+	return strings.Join(pairs, ";") + ";"
+}
+
+func odbcSQLServerString(params ConnectionParams) string {
+	pairs := []string{
+		"Driver={ODBC Driver 17 for SQL Server}",
+		fmt.Sprintf("Server=%s,%d", params.Host, params.Port),
+		fmt.Sprintf("Database=%s", params.Database),
+	}
+	if params.User != "" {
+		pairs = append(pairs, fmt.Sprintf("Uid=%s", params.User))
+	}
+	switch params.TLSMode {
+	case TLSModeDisabled:
+		pairs = append(pairs, "Encrypt=no")
+	case TLSModeInsecure:
+		pairs = append(pairs, "Encrypt=yes", "TrustServerCertificate=yes")
+	default:
+		pairs = append(pairs,
+			"Encrypt=yes",
+			"TrustServerCertificate=no",
+			fmt.Sprintf("ServerCertificateCA=%s", params.CACertPath),
+		)
+	}
+	return strings.Join(pairs, ";") + ";"
+}
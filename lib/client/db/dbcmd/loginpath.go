@@ -0,0 +1,58 @@
+/*
+
+ Copyright 2023 Gravitational, Inc.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+
+*/
+
+package dbcmd
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+
+	"github.com/gravitational/trace"
+)
+
+// mysqlLoginPathName returns the mysql_config_editor login-path name used
+// to store this database's password-auth credentials, scoped by service
+// name so multiple databases don't collide in the shared login-path store.
+func mysqlLoginPathName(serviceName string) string {
+	return "teleport-" + serviceName
+}
+
+// createMySQLLoginPath creates or updates an encrypted mysql_config_editor
+// login-path entry named loginPath with this database's connection details
+// and password. mysql_config_editor refuses to take the password as a
+// command-line argument (it would leak into argv and shell history), so it
+// prompts for it on stdin instead; createMySQLLoginPath answers that prompt
+// itself, which keeps the password out of argv while still avoiding any
+// interactive prompt for the person running "tsh db connect".
+func (c *CLICommandBuilder) createMySQLLoginPath(loginPath, password string) error {
+	cmd := exec.Command(mysqlConfigEditorBin, "set",
+		"--login-path="+loginPath,
+		"--host="+c.host,
+		"--port="+fmt.Sprintf("%d", c.port),
+		"--user="+c.db.Username,
+		"--password",
+	)
+	cmd.Stdin = bytes.NewBufferString(password + "\n")
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return trace.Wrap(err, "creating mysql_config_editor login-path %q: %s", loginPath, out)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+//go:build windows
+// +build windows
+
+/*
+Copyright 2023 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dbcmd
+
+import "strings"
+
+// shellMetaChars are the characters that require an argument to be quoted
+// before it can be safely pasted into cmd.exe or PowerShell.
+const shellMetaChars = " \t\n\"'&|;<>()^%"
+
+// quoteArg quotes arg for display in a command line a user could paste into
+// cmd.exe or PowerShell, leaving arguments that need no quoting untouched so
+// the common case (e.g. a bare flag or host:port) stays readable. This
+// mirrors cmd.exe/PowerShell's own double-quote escaping (a literal quote is
+// doubled), which both shells accept.
+func quoteArg(arg string) string {
+	if arg != "" && !strings.ContainsAny(arg, shellMetaChars) {
+		return arg
+	}
+	return `"` + strings.ReplaceAll(arg, `"`, `""`) + `"`
+}
@@ -0,0 +1,89 @@
+/*
+
+ Copyright 2022 Gravitational, Inc.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+
+*/
+
+package dbcmd
+
+import (
+	"os/exec"
+	"strings"
+
+	"github.com/gravitational/teleport/lib/defaults"
+)
+
+const redactedSecret = "<redacted>"
+
+// secretFlags maps, per protocol, the flags whose following argument is a
+// secret (e.g. a password or auth token) that must never be printed or
+// logged verbatim.
+var secretFlags = map[string]map[string]bool{
+	defaults.ProtocolMongoDB:   {"--tlsCertificateKeyFilePassword": true},
+	defaults.ProtocolRedis:     {"-a": true},
+	defaults.ProtocolSQLServer: {"-P": true},
+	defaults.ProtocolSnowflake: {"--token": true},
+}
+
+// secretConnStringParams lists connection string query parameters whose
+// value is a secret, for protocols (Postgres, CockroachDB) that embed
+// credentials in a URL argument rather than passing them as a separate flag.
+var secretConnStringParams = []string{"sslpassword"}
+
+// RedactCommand returns cmd's command line with any protocol-specific
+// secrets (passwords, auth tokens) replaced with a placeholder, safe to
+// print to a terminal or write to a debug log without leaking credentials
+// into support bundles. Arguments are quoted for the host shell (POSIX on
+// Unix, cmd.exe/PowerShell on Windows) where needed, so the result can be
+// pasted back into a terminal even when an argument (e.g. a PGOPTIONS value
+// or a path containing spaces) would otherwise be split apart.
+func RedactCommand(protocol string, cmd *exec.Cmd) string {
+	args := make([]string, len(cmd.Args))
+	copy(args, cmd.Args)
+
+	flags := secretFlags[protocol]
+	for i := 0; i < len(args); i++ {
+		if flags[args[i]] && i+1 < len(args) {
+			args[i+1] = redactedSecret
+			i++
+			continue
+		}
+		args[i] = redactConnStringParams(args[i])
+	}
+	for i := range args {
+		args[i] = quoteArg(args[i])
+	}
+	return strings.Join(args, " ")
+}
+
+// redactConnStringParams replaces the value of any secret query parameter
+// found in arg (e.g. "sslpassword=...") with a placeholder, leaving the
+// rest of the argument untouched.
+func redactConnStringParams(arg string) string {
+	for _, param := range secretConnStringParams {
+		prefix := param + "="
+		start := strings.Index(arg, prefix)
+		if start == -1 {
+			continue
+		}
+		start += len(prefix)
+		if end := strings.IndexByte(arg[start:], '&'); end != -1 {
+			arg = arg[:start] + redactedSecret + arg[start+end:]
+		} else {
+			arg = arg[:start] + redactedSecret
+		}
+	}
+	return arg
+}
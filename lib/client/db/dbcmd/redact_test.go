@@ -0,0 +1,87 @@
+/*
+
+ Copyright 2022 Gravitational, Inc.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+
+*/
+
+package dbcmd
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/gravitational/teleport/lib/defaults"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		protocol string
+		args     []string
+		want     string
+	}{
+		{
+			name:     "sqlserver password",
+			protocol: defaults.ProtocolSQLServer,
+			args:     []string{"sqlcmd", "-S", "localhost,12345", "-U", "alice", "-P", "s3cr3t"},
+			want:     "sqlcmd -S localhost,12345 -U alice -P '<redacted>'",
+		},
+		{
+			name:     "redis auth token",
+			protocol: defaults.ProtocolRedis,
+			args:     []string{"redis-cli", "-h", "localhost", "-p", "12345", "-a", "s3cr3t"},
+			want:     "redis-cli -h localhost -p 12345 -a '<redacted>'",
+		},
+		{
+			name:     "snowflake oauth token",
+			protocol: defaults.ProtocolSnowflake,
+			args:     []string{"snowsql", "--accountname", "acme", "--authenticator", "oauth", "--token", "s3cr3t"},
+			want:     "snowsql --accountname acme --authenticator oauth --token '<redacted>'",
+		},
+		{
+			name:     "mongo key file password",
+			protocol: defaults.ProtocolMongoDB,
+			args:     []string{"mongosh", "--tlsCertificateKeyFilePassword", "s3cr3t", "--host", "localhost"},
+			want:     "mongosh --tlsCertificateKeyFilePassword '<redacted>' --host localhost",
+		},
+		{
+			name:     "postgres connection string password",
+			protocol: defaults.ProtocolPostgres,
+			args:     []string{"psql", "postgres://alice@localhost:12345/mydb?sslpassword=s3cr3t&sslmode=verify-full"},
+			want:     "psql 'postgres://alice@localhost:12345/mydb?sslpassword=<redacted>&sslmode=verify-full'",
+		},
+		{
+			name:     "no secrets to redact",
+			protocol: defaults.ProtocolMySQL,
+			args:     []string{"mysql", "--user", "alice", "--database", "mydb"},
+			want:     "mysql --user alice --database mydb",
+		},
+		{
+			name:     "argument with a space is quoted so it can be pasted back into a shell",
+			protocol: defaults.ProtocolMySQL,
+			args:     []string{"mysql", "--init-command=SET SESSION wait_timeout=90; SET ROLE readonly"},
+			want:     `mysql '--init-command=SET SESSION wait_timeout=90; SET ROLE readonly'`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := exec.Command(tt.args[0], tt.args[1:]...)
+			require.Equal(t, tt.want, RedactCommand(tt.protocol, cmd))
+		})
+	}
+}
@@ -0,0 +1,158 @@
+/*
+
+ Copyright 2022 Gravitational, Inc.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+
+*/
+
+package dbcmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gravitational/trace"
+)
+
+// SecretStore retrieves client credentials (passwords, tokens) that native
+// database clients require but that Teleport's mutual TLS authentication
+// scheme doesn't need (e.g. SQL Server login, Redis AUTH), so dbcmd doesn't
+// have to fall back to hard-coded placeholder values or take secrets as
+// plaintext arguments.
+type SecretStore interface {
+	// GetSecret returns the secret value identified by key.
+	GetSecret(ctx context.Context, key string) (string, error)
+}
+
+// EnvSecretStore retrieves secrets from environment variables, where key is
+// the variable name.
+type EnvSecretStore struct{}
+
+// GetSecret returns the value of the environment variable named key.
+func (EnvSecretStore) GetSecret(_ context.Context, key string) (string, error) {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return "", trace.NotFound("environment variable %q is not set", key)
+	}
+	return value, nil
+}
+
+// FileSecretStore retrieves secrets from files on disk, one secret per
+// file named after its key, e.g. Docker or Kubernetes mounted secrets.
+type FileSecretStore struct {
+	// Dir is the directory containing one file per secret.
+	Dir string
+}
+
+// GetSecret returns the trimmed contents of the file named key inside Dir.
+func (s FileSecretStore) GetSecret(_ context.Context, key string) (string, error) {
+	data, err := ioutil.ReadFile(filepath.Join(s.Dir, key))
+	if err != nil {
+		return "", trace.ConvertSystemError(err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// VaultSecretStore retrieves secrets from a HashiCorp Vault KV version 2
+// secrets engine over Vault's HTTP API. Keys are of the form
+// "<secret-path>#<field>", e.g. "databases/redis#auth-token".
+type VaultSecretStore struct {
+	// Addr is the Vault server address, e.g. https://vault.example.com:8200.
+	Addr string
+	// Token is the Vault token used to authenticate requests.
+	Token string
+	// MountPath is the KV v2 secrets engine mount path. Defaults to "secret".
+	MountPath string
+	// Client is the HTTP client used to talk to Vault. Defaults to
+	// http.DefaultClient.
+	Client *http.Client
+}
+
+// GetSecret fetches the named field from the secret stored at the given
+// Vault path.
+func (s VaultSecretStore) GetSecret(ctx context.Context, key string) (string, error) {
+	path, field, err := splitVaultKey(key)
+	if err != nil {
+		return "", trace.Wrap(err)
+	}
+	mount := s.MountPath
+	if mount == "" {
+		mount = "secret"
+	}
+	url := fmt.Sprintf("%s/v1/%s/data/%s", strings.TrimRight(s.Addr, "/"), mount, path)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", trace.Wrap(err)
+	}
+	req.Header.Set("X-Vault-Token", s.Token)
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", trace.Wrap(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", trace.AccessDenied("vault request to %q failed with status %v", path, resp.StatusCode)
+	}
+	var body struct {
+		Data struct {
+			Data map[string]interface{} `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", trace.Wrap(err)
+	}
+	value, ok := body.Data.Data[field]
+	if !ok {
+		return "", trace.NotFound("field %q not found at vault path %q", field, path)
+	}
+	str, ok := value.(string)
+	if !ok {
+		return "", trace.BadParameter("field %q at vault path %q is not a string", field, path)
+	}
+	return str, nil
+}
+
+// splitVaultKey splits a "<path>#<field>" key into its path and field parts.
+func splitVaultKey(key string) (path, field string, err error) {
+	idx := strings.LastIndex(key, "#")
+	if idx == -1 {
+		return "", "", trace.BadParameter("vault secret key %q must be of the form <path>#<field>", key)
+	}
+	return key[:idx], key[idx+1:], nil
+}
+
+// KeychainSecretStore retrieves secrets from the OS-native credential
+// store (e.g. macOS Keychain, Windows Credential Manager, Linux Secret
+// Service).
+//
+// Not implemented yet: accessing OS credential stores requires
+// platform-specific libraries that aren't vendored in this build. Callers
+// that need it should implement SecretStore against their platform's
+// keyring package instead.
+type KeychainSecretStore struct{}
+
+// GetSecret always returns a not-implemented error. See KeychainSecretStore.
+func (KeychainSecretStore) GetSecret(_ context.Context, key string) (string, error) {
+	return "", trace.NotImplemented("keychain secret store is not implemented on this platform")
+}
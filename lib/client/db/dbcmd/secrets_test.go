@@ -0,0 +1,68 @@
+/*
+
+ Copyright 2022 Gravitational, Inc.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+
+*/
+
+package dbcmd
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/gravitational/trace"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvSecretStore(t *testing.T) {
+	t.Setenv("TELEPORT_TEST_SECRET", "hunter2")
+
+	secret, err := EnvSecretStore{}.GetSecret(context.Background(), "TELEPORT_TEST_SECRET")
+	require.NoError(t, err)
+	require.Equal(t, "hunter2", secret)
+
+	_, err = EnvSecretStore{}.GetSecret(context.Background(), "TELEPORT_TEST_SECRET_MISSING")
+	require.True(t, trace.IsNotFound(err))
+}
+
+func TestFileSecretStore(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "redis-auth-token"), []byte("s3cr3t\n"), 0600))
+
+	store := FileSecretStore{Dir: dir}
+	secret, err := store.GetSecret(context.Background(), "redis-auth-token")
+	require.NoError(t, err)
+	require.Equal(t, "s3cr3t", secret)
+
+	_, err = store.GetSecret(context.Background(), "does-not-exist")
+	require.Error(t, err)
+}
+
+func TestSplitVaultKey(t *testing.T) {
+	path, field, err := splitVaultKey("databases/redis#auth-token")
+	require.NoError(t, err)
+	require.Equal(t, "databases/redis", path)
+	require.Equal(t, "auth-token", field)
+
+	_, _, err = splitVaultKey("databases/redis")
+	require.True(t, trace.IsBadParameter(err))
+}
+
+func TestKeychainSecretStoreNotImplemented(t *testing.T) {
+	_, err := KeychainSecretStore{}.GetSecret(context.Background(), "anything")
+	require.True(t, trace.IsNotImplemented(err))
+}
@@ -0,0 +1,91 @@
+/*
+
+ Copyright 2023 Gravitational, Inc.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+
+*/
+
+package dbcmd
+
+import (
+	"strings"
+
+	"github.com/coreos/go-semver/semver"
+	"github.com/gravitational/trace"
+)
+
+// versionExtractor parses the output of a client's "--version" invocation
+// into a semver.Version, e.g. pulling "7.0.8" out of "redis-cli 7.0.8".
+type versionExtractor func(output string) (*semver.Version, error)
+
+// clientVersion probes bin's version by running it with "--version" and
+// passing the output through extract, caching the result on c so that
+// repeated flag-selection checks against the same client on one builder
+// only exec the binary once.
+func (c *CLICommandBuilder) clientVersion(bin string, extract versionExtractor) (*semver.Version, error) {
+	if version, ok := c.versionCache[bin]; ok {
+		return version, nil
+	}
+
+	out, err := c.exe.RunCommand(bin, "--version")
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	version, err := extract(string(out))
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	if c.versionCache == nil {
+		c.versionCache = make(map[string]*semver.Version)
+	}
+	c.versionCache[bin] = version
+	return version, nil
+}
+
+// clientAtLeast reports whether bin's probed version is at least min. Any
+// failure to probe or parse the version (binary missing, unrecognized
+// "--version" output) is treated as "too old", so callers fail closed onto
+// whatever behavior is safe for the oldest supported client.
+func (c *CLICommandBuilder) clientAtLeast(bin string, extract versionExtractor, min semver.Version) bool {
+	version, err := c.clientVersion(bin, extract)
+	if err != nil {
+		return false
+	}
+	return !version.LessThan(min)
+}
+
+// extractRedisCliVersion parses redis-cli's "--version" output, e.g.
+// "redis-cli 7.0.8".
+func extractRedisCliVersion(output string) (*semver.Version, error) {
+	fields := strings.Fields(output)
+	if len(fields) < 2 {
+		return nil, trace.BadParameter("unrecognized %q --version output %q", redisBin, output)
+	}
+	version, err := semver.NewVersion(fields[1])
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return version, nil
+}
+
+// extractMongoshVersion parses mongosh's "--version" output, a single line
+// containing just the version number, e.g. "1.10.1".
+func extractMongoshVersion(output string) (*semver.Version, error) {
+	version, err := semver.NewVersion(strings.TrimSpace(output))
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return version, nil
+}
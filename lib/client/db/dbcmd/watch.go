@@ -0,0 +1,141 @@
+/*
+
+ Copyright 2023 Gravitational, Inc.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+
+*/
+
+package dbcmd
+
+import (
+	"context"
+	"io"
+
+	"github.com/gravitational/trace"
+
+	"github.com/gravitational/teleport"
+	"github.com/gravitational/teleport/api/types"
+	"github.com/gravitational/teleport/lib/client"
+	"github.com/gravitational/teleport/lib/services"
+)
+
+// ResourceChange describes a database resource change observed by Watch.
+// Database is nil when the database was deleted.
+type ResourceChange struct {
+	// ServiceName is the name of the database resource that changed.
+	ServiceName string
+	// Database is the database resource's new state, or nil if it was
+	// deleted.
+	Database types.Database
+}
+
+// WatchCallback is invoked for every database resource change observed by
+// Watch.
+type WatchCallback func(ResourceChange)
+
+// Watch starts a background watcher for database resource changes (creates,
+// updates, deletes) visible to tc, invoking onChange for each one. It's
+// meant for long-lived callers (e.g. Teleport Connect) that generate and
+// cache commands or config files with dbcmd and need to know when to
+// invalidate and regenerate them because a database was renamed, had its
+// protocol changed, or had its endpoint updated.
+//
+// A rename surfaces as a creation of the new name and a deletion of the old
+// one, since Teleport identifies databases by name; within one event batch
+// all creations and updates are reported before any deletion. Watch has no
+// way to tell a rename apart from an unrelated delete and create happening
+// to land in the same event batch.
+//
+// Call Close on the returned io.Closer to stop watching.
+func Watch(ctx context.Context, tc *client.TeleportClient, onChange WatchCallback) (io.Closer, error) {
+	proxyClient, err := tc.ConnectToProxy(ctx)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	authClient, err := proxyClient.ConnectToRootCluster(ctx, false)
+	if err != nil {
+		proxyClient.Close()
+		return nil, trace.Wrap(err)
+	}
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	databasesC := make(chan types.Databases)
+	watcher, err := services.NewDatabaseWatcher(watchCtx, services.DatabaseWatcherConfig{
+		ResourceWatcherConfig: services.ResourceWatcherConfig{
+			Component: teleport.ComponentTSH,
+			Client:    authClient,
+		},
+		DatabasesC: databasesC,
+	})
+	if err != nil {
+		cancel()
+		proxyClient.Close()
+		return nil, trace.Wrap(err)
+	}
+
+	closer := &watchCloser{cancel: cancel, watcher: watcher, proxyClient: proxyClient}
+
+	go func() {
+		current := make(map[string]types.Database)
+		for {
+			select {
+			case <-watchCtx.Done():
+				return
+			case databases, ok := <-databasesC:
+				if !ok {
+					return
+				}
+				next := make(map[string]types.Database, len(databases))
+				for _, db := range databases {
+					next[db.GetName()] = db
+				}
+				for name, db := range next {
+					if old, ok := current[name]; !ok || databaseChanged(old, db) {
+						onChange(ResourceChange{ServiceName: name, Database: db})
+					}
+				}
+				for name := range current {
+					if _, ok := next[name]; !ok {
+						onChange(ResourceChange{ServiceName: name})
+					}
+				}
+				current = next
+			}
+		}
+	}()
+
+	return closer, nil
+}
+
+// databaseChanged reports whether a is meaningfully different from b for
+// the purposes of regenerating a cached command or config file: its
+// protocol or connection endpoint (URI) changed.
+func databaseChanged(a, b types.Database) bool {
+	return a.GetProtocol() != b.GetProtocol() || a.GetURI() != b.GetURI()
+}
+
+// watchCloser stops the watcher goroutine started by Watch and releases the
+// proxy connection it was using.
+type watchCloser struct {
+	cancel      context.CancelFunc
+	watcher     *services.DatabaseWatcher
+	proxyClient *client.ProxyClient
+}
+
+func (c *watchCloser) Close() error {
+	c.cancel()
+	c.watcher.Close()
+	return trace.Wrap(c.proxyClient.Close())
+}
@@ -0,0 +1,74 @@
+/*
+
+ Copyright 2023 Gravitational, Inc.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+
+*/
+
+package dbcmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gravitational/teleport/api/types"
+	"github.com/gravitational/teleport/lib/defaults"
+)
+
+func newTestDatabase(t *testing.T, protocol, uri string) types.Database {
+	t.Helper()
+	db, err := types.NewDatabaseV3(types.Metadata{
+		Name: "test-database",
+	}, types.DatabaseSpecV3{
+		Protocol: protocol,
+		URI:      uri,
+	})
+	require.NoError(t, err)
+	return db
+}
+
+func TestDatabaseChanged(t *testing.T) {
+	postgres := newTestDatabase(t, defaults.ProtocolPostgres, "localhost:5432")
+
+	tests := []struct {
+		name string
+		a, b types.Database
+		want bool
+	}{
+		{
+			name: "identical databases",
+			a:    postgres,
+			b:    newTestDatabase(t, defaults.ProtocolPostgres, "localhost:5432"),
+			want: false,
+		},
+		{
+			name: "protocol changed",
+			a:    postgres,
+			b:    newTestDatabase(t, defaults.ProtocolMySQL, "localhost:5432"),
+			want: true,
+		},
+		{
+			name: "endpoint changed",
+			a:    postgres,
+			b:    newTestDatabase(t, defaults.ProtocolPostgres, "localhost:5433"),
+			want: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.want, databaseChanged(test.a, test.b))
+		})
+	}
+}
@@ -66,7 +66,20 @@ func LoadFromPath(path string) (*OptionFile, error) {
 
 // Upsert saves the provided connection profile in MySQL option file.
 func (o *OptionFile) Upsert(profile profile.ConnectProfile) error {
-	sectionName := o.section(profile.Name)
+	return o.upsert(o.section(profile.Name), profile)
+}
+
+// UpsertClientSection saves the provided connection profile under this
+// option file's unsuffixed "[client]" section. It's meant for option files
+// dedicated to a single profile (e.g. a temporary file passed to the mysql
+// client via --defaults-extra-file), where there's no need for Upsert's
+// group-suffix naming scheme to disambiguate multiple profiles sharing one
+// file.
+func (o *OptionFile) UpsertClientSection(profile profile.ConnectProfile) error {
+	return o.upsert(clientSectionName, profile)
+}
+
+func (o *OptionFile) upsert(sectionName string, profile profile.ConnectProfile) error {
 	section := o.iniFile.Section(sectionName)
 	if section != nil {
 		o.iniFile.DeleteSection(sectionName)
@@ -123,6 +136,60 @@ func (o *OptionFile) Delete(name string) error {
 	return o.iniFile.SaveTo(o.path)
 }
 
+// Verify checks that the named connection profile has all the keys a
+// mysql/mariadb client needs to connect (host, port, ssl-ca, ssl-cert and
+// ssl-key), returning a clear Teleport error instead of letting an
+// incomplete or stale section surface as a cryptic client-side connection
+// failure.
+func (o *OptionFile) Verify(name string) error {
+	return o.verify(o.section(name))
+}
+
+// VerifyClientSection is like Verify, for option files written by
+// UpsertClientSection.
+func (o *OptionFile) VerifyClientSection() error {
+	return o.verify(clientSectionName)
+}
+
+func (o *OptionFile) verify(sectionName string) error {
+	section, err := o.iniFile.GetSection(sectionName)
+	if err != nil {
+		if strings.Contains(err.Error(), "does not exist") {
+			return trace.NotFound("connection profile %q not found", sectionName)
+		}
+		return trace.Wrap(err)
+	}
+	for _, key := range []string{"host", "port", "ssl-ca", "ssl-cert", "ssl-key"} {
+		if !section.HasKey(key) {
+			return trace.BadParameter("connection profile %q is missing required key %q", sectionName, key)
+		}
+	}
+	return nil
+}
+
+// List returns all connection profiles currently saved in the option file.
+func (o *OptionFile) List() (profiles []profile.ConnectProfile, err error) {
+	for _, section := range o.iniFile.Sections() {
+		name, ok := o.unsection(section.Name())
+		if !ok || !section.HasKey("host") {
+			continue
+		}
+		port, _ := section.Key("port").Int()
+		profiles = append(profiles, profile.ConnectProfile{
+			Name:       name,
+			Host:       section.Key("host").Value(),
+			Port:       port,
+			User:       section.Key("user").Value(),
+			Database:   section.Key("database").Value(),
+			Insecure:   section.Key("ssl-mode").Value() == MySQLSSLModeVerifyCA,
+			CACertPath: section.Key("ssl-ca").Value(),
+			CertPath:   section.Key("ssl-cert").Value(),
+			KeyPath:    section.Key("ssl-key").Value(),
+		})
+	}
+	return profiles, nil
+}
+
 // section returns the section name in MySQL option file.
 //
 // Sections that are read by MySQL client start with "client" prefix.
@@ -130,6 +197,16 @@ func (o *OptionFile) section(name string) string {
 	return "client" + suffix(name)
 }
 
+// unsection reverses section, extracting the connection profile name from a
+// "client_<name>" MySQL option file section name.
+func (o *OptionFile) unsection(sectionName string) (name string, ok bool) {
+	const prefix = "client_"
+	if !strings.HasPrefix(sectionName, prefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(sectionName, prefix), true
+}
+
 func suffix(name string) string {
 	return "_" + name
 }
@@ -147,4 +224,7 @@ const (
 	MySQLSSLModeVerifyIdentity = "VERIFY_IDENTITY"
 	// mysqlOptionFile is the default name of the MySQL option file.
 	mysqlOptionFile = ".my.cnf"
+	// clientSectionName is the standard, unsuffixed option file section
+	// name that mysql/mariadb clients read by default.
+	clientSectionName = "client"
 )
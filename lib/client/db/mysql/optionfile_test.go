@@ -53,6 +53,11 @@ func TestOptionFile(t *testing.T) {
 		"MYSQL_GROUP_SUFFIX": "_test",
 	}, env)
 
+	profiles, err := optionFile.List()
+	require.NoError(t, err)
+	require.Len(t, profiles, 1)
+	require.Equal(t, profile, profiles[0])
+
 	err = optionFile.Delete(profile.Name)
 	require.NoError(t, err)
 
@@ -60,3 +65,46 @@ func TestOptionFile(t *testing.T) {
 	require.Error(t, err)
 	require.IsType(t, trace.NotFound(""), err)
 }
+
+func TestOptionFileClientSection(t *testing.T) {
+	path := filepath.Join(t.TempDir(), mysqlOptionFile)
+
+	optionFile, err := LoadFromPath(path)
+	require.NoError(t, err)
+
+	// Verify fails before the section exists.
+	err = optionFile.VerifyClientSection()
+	require.Error(t, err)
+	require.IsType(t, trace.NotFound(""), err)
+
+	complete := profile.ConnectProfile{
+		Host:       "localhost",
+		Port:       3036,
+		CACertPath: "ca.pem",
+		CertPath:   "cert.pem",
+		KeyPath:    "key.pem",
+	}
+
+	err = optionFile.UpsertClientSection(complete)
+	require.NoError(t, err)
+	require.NoError(t, optionFile.VerifyClientSection())
+
+	// UpsertClientSection writes to the bare "client" section, not a
+	// suffixed one, so Verify for a named profile still fails.
+	err = optionFile.Verify("test")
+	require.Error(t, err)
+	require.IsType(t, trace.NotFound(""), err)
+
+	// A section missing one of the required keys, e.g. because the file
+	// was hand-edited rather than written by UpsertClientSection, is
+	// reported with a clear error instead of silently passing.
+	optionFile.iniFile.DeleteSection(clientSectionName)
+	section, err := optionFile.iniFile.NewSection(clientSectionName)
+	require.NoError(t, err)
+	section.NewKey("host", "localhost")
+	section.NewKey("port", "3036")
+
+	err = optionFile.VerifyClientSection()
+	require.Error(t, err)
+	require.IsType(t, trace.BadParameter(""), err)
+}
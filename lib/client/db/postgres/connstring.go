@@ -26,8 +26,11 @@ import (
 	"github.com/gravitational/teleport/lib/client/db/profile"
 )
 
-// GetConnString returns formatted Postgres connection string for the profile.
-func GetConnString(c *profile.ConnectProfile, noTLS bool) string {
+// GetConnString returns formatted Postgres connection string for the
+// profile. If disableGSSAPI is true, "gssencmode=disable" is added to the
+// connection string so that psql doesn't stall negotiating GSS/SSPI
+// encryption against proxies and servers that don't support it.
+func GetConnString(c *profile.ConnectProfile, noTLS, disableGSSAPI bool) string {
 	connStr := "postgres://"
 	if c.User != "" {
 		// Username may contain special characters in which case it should
@@ -39,20 +42,33 @@ func GetConnString(c *profile.ConnectProfile, noTLS bool) string {
 	if c.Database != "" {
 		connStr += "/" + c.Database
 	}
-	if noTLS {
-		return connStr
+	var params []string
+	if !noTLS {
+		params = append(params,
+			fmt.Sprintf("sslrootcert=%v", c.CACertPath),
+			fmt.Sprintf("sslcert=%v", c.CertPath),
+			fmt.Sprintf("sslkey=%v", c.KeyPath))
+		if c.KeyPassphrase != "" {
+			params = append(params, fmt.Sprintf("sslpassword=%v", url.QueryEscape(c.KeyPassphrase)))
+		}
+		if c.Insecure {
+			params = append(params,
+				fmt.Sprintf("sslmode=%v", SSLModeVerifyCA))
+		} else {
+			params = append(params,
+				fmt.Sprintf("sslmode=%v", SSLModeVerifyFull))
+		}
 	}
-	params := []string{
-		fmt.Sprintf("sslrootcert=%v", c.CACertPath),
-		fmt.Sprintf("sslcert=%v", c.CertPath),
-		fmt.Sprintf("sslkey=%v", c.KeyPath),
+	if disableGSSAPI {
+		params = append(params, "gssencmode=disable")
 	}
-	if c.Insecure {
-		params = append(params,
-			fmt.Sprintf("sslmode=%v", SSLModeVerifyCA))
-	} else {
-		params = append(params,
-			fmt.Sprintf("sslmode=%v", SSLModeVerifyFull))
+	if c.Endpoint != "" {
+		// Surfaces the selected endpoint (e.g. a reader) to the server and to
+		// observability tooling such as pg_stat_activity.
+		params = append(params, fmt.Sprintf("application_name=%v", url.QueryEscape(c.Endpoint)))
+	}
+	if len(params) == 0 {
+		return connStr
 	}
 	return fmt.Sprintf("%v?%v", connStr, strings.Join(params, "&"))
 }
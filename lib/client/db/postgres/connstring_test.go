@@ -35,11 +35,13 @@ func TestConnString(t *testing.T) {
 	)
 
 	tests := []struct {
-		name     string
-		user     string
-		database string
-		insecure bool
-		out      string
+		name          string
+		user          string
+		database      string
+		insecure      bool
+		disableGSSAPI bool
+		endpoint      string
+		out           string
 	}{
 		{
 			name: "default settings",
@@ -71,6 +73,16 @@ func TestConnString(t *testing.T) {
 			database: "test",
 			out:      "postgres://alice@localhost:5432/test?sslrootcert=/tmp/ca&sslcert=/tmp/cert&sslkey=/tmp/key&sslmode=verify-full",
 		},
+		{
+			name:          "gssapi disabled",
+			disableGSSAPI: true,
+			out:           "postgres://localhost:5432?sslrootcert=/tmp/ca&sslcert=/tmp/cert&sslkey=/tmp/key&sslmode=verify-full&gssencmode=disable",
+		},
+		{
+			name:     "endpoint set",
+			endpoint: "reader",
+			out:      "postgres://localhost:5432?sslrootcert=/tmp/ca&sslcert=/tmp/cert&sslkey=/tmp/key&sslmode=verify-full&application_name=reader",
+		},
 	}
 
 	for _, test := range tests {
@@ -84,7 +96,17 @@ func TestConnString(t *testing.T) {
 				CACertPath: caPath,
 				CertPath:   certPath,
 				KeyPath:    keyPath,
-			}, false))
+				Endpoint:   test.endpoint,
+			}, false, test.disableGSSAPI))
 		})
 	}
 }
+
+// TestConnStringNoTLS verifies gssencmode is still applied when TLS is
+// disabled (e.g. when connecting through a local proxy in mutual-TLS mode).
+func TestConnStringNoTLS(t *testing.T) {
+	require.Equal(t, "postgres://localhost:5432?gssencmode=disable", GetConnString(&profile.ConnectProfile{
+		Host: "localhost",
+		Port: 5432,
+	}, true, true))
+}
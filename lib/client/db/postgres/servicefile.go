@@ -173,6 +173,29 @@ func (s *ServiceFile) Delete(name string) error {
 	return s.iniFile.SaveTo(s.path)
 }
 
+// List returns all connection profiles currently saved in the service file.
+func (s *ServiceFile) List() (profiles []profile.ConnectProfile, err error) {
+	for _, section := range s.iniFile.Sections() {
+		// ini.v1 always has an implicit "DEFAULT" section even when empty.
+		if section.Name() == ini.DefaultSection || !section.HasKey("host") {
+			continue
+		}
+		port, _ := section.Key("port").Int()
+		profiles = append(profiles, profile.ConnectProfile{
+			Name:       section.Name(),
+			Host:       section.Key("host").Value(),
+			Port:       port,
+			User:       section.Key("user").Value(),
+			Database:   section.Key("dbname").Value(),
+			Insecure:   section.Key("sslmode").Value() == SSLModeVerifyCA,
+			CACertPath: section.Key("sslrootcert").Value(),
+			CertPath:   section.Key("sslcert").Value(),
+			KeyPath:    section.Key("sslkey").Value(),
+		})
+	}
+	return profiles, nil
+}
+
 const (
 	// SSLModeVerifyFull is the Postgres SSL "verify-full" mode.
 	//
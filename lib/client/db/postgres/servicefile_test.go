@@ -61,6 +61,11 @@ func TestServiceFile(t *testing.T) {
 		"PGSSLKEY":      profile.KeyPath,
 	}, env)
 
+	profiles, err := serviceFile.List()
+	require.NoError(t, err)
+	require.Len(t, profiles, 1)
+	require.Equal(t, profile, profiles[0])
+
 	err = serviceFile.Delete(profile.Name)
 	require.NoError(t, err)
 
@@ -26,6 +26,7 @@ package db
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/gravitational/teleport/lib/client"
 	"github.com/gravitational/teleport/lib/client/db/mysql"
@@ -74,7 +75,7 @@ func add(tc *client.TeleportClient, db tlsca.RouteToDatabase, clientProfile clie
 	default:
 		return nil, trace.BadParameter("unknown database protocol: %q", db)
 	}
-	connectProfile := New(tc, db, clientProfile, rootCluster, host, port)
+	connectProfile := New(tc, db, clientProfile, rootCluster, host, port, "", "", "", "", "")
 	err := profileFile.Upsert(*connectProfile)
 	if err != nil {
 		return nil, trace.Wrap(err)
@@ -82,19 +83,50 @@ func add(tc *client.TeleportClient, db tlsca.RouteToDatabase, clientProfile clie
 	return connectProfile, nil
 }
 
-// New makes a new database connection profile.
-func New(tc *client.TeleportClient, db tlsca.RouteToDatabase, clientProfile client.ProfileStatus, rootCluster string, host string, port int) *profile.ConnectProfile {
+// New makes a new database connection profile. caPath, certPath and keyPath
+// override the corresponding paths derived from the profile layout, for
+// certificates issued by external tooling (e.g. Machine ID, Vault) into a
+// custom location; an empty override falls back to the profile-derived
+// path. endpoint names the alternate database endpoint (e.g. a reader
+// endpoint) this connection targets, if any.
+func New(tc *client.TeleportClient, db tlsca.RouteToDatabase, clientProfile client.ProfileStatus, rootCluster string, host string, port int, keyPassphrase string, caPath, certPath, keyPath, endpoint string) *profile.ConnectProfile {
+	if caPath == "" {
+		caPath = CACertPath(tc, clientProfile, rootCluster)
+	}
+	if certPath == "" {
+		certPath = clientProfile.DatabaseCertPathForCluster(tc.SiteName, db.ServiceName)
+	}
+	if keyPath == "" {
+		keyPath = clientProfile.KeyPath()
+	}
 	return &profile.ConnectProfile{
-		Name:       profileName(tc.SiteName, db.ServiceName),
-		Host:       host,
-		Port:       port,
-		User:       db.Username,
-		Database:   db.Database,
-		Insecure:   tc.InsecureSkipVerify,
-		CACertPath: clientProfile.CACertPathForCluster(rootCluster),
-		CertPath:   clientProfile.DatabaseCertPathForCluster(tc.SiteName, db.ServiceName),
-		KeyPath:    clientProfile.KeyPath(),
+		Name:          ProfileName(tc.SiteName, db.ServiceName),
+		Host:          host,
+		Port:          port,
+		User:          db.Username,
+		Database:      db.Database,
+		Insecure:      tc.InsecureSkipVerify,
+		CACertPath:    caPath,
+		CertPath:      certPath,
+		KeyPath:       keyPath,
+		KeyPassphrase: keyPassphrase,
+		Endpoint:      endpoint,
+	}
+}
+
+// CACertPath returns the CA certificate path the database client should
+// trust. When the current cluster is a trusted leaf cluster with its own CA
+// bundle on disk, that one is used so clients don't end up trusting
+// unrelated root cluster CAs; otherwise it falls back to the root cluster's
+// CA bundle.
+func CACertPath(tc *client.TeleportClient, clientProfile client.ProfileStatus, rootCluster string) string {
+	if tc.SiteName != "" && tc.SiteName != rootCluster {
+		leafPath := clientProfile.CACertPathForCluster(tc.SiteName)
+		if _, err := os.Stat(leafPath); err == nil {
+			return leafPath
+		}
 	}
+	return clientProfile.CACertPathForCluster(rootCluster)
 }
 
 // Env returns environment variables for the specified database profile.
@@ -103,7 +135,7 @@ func Env(tc *client.TeleportClient, db tlsca.RouteToDatabase) (map[string]string
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
-	env, err := profileFile.Env(profileName(tc.SiteName, db.ServiceName))
+	env, err := profileFile.Env(ProfileName(tc.SiteName, db.ServiceName))
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
@@ -123,13 +155,58 @@ func Delete(tc *client.TeleportClient, db tlsca.RouteToDatabase) error {
 	if err != nil {
 		return trace.Wrap(err)
 	}
-	err = profileFile.Delete(profileName(tc.SiteName, db.ServiceName))
+	err = profileFile.Delete(ProfileName(tc.SiteName, db.ServiceName))
 	if err != nil {
 		return trace.Wrap(err)
 	}
 	return nil
 }
 
+// CleanupStale does a best-effort scan of the Postgres connection service
+// file and MySQL option file for entries whose client certificate no
+// longer exists on disk, and removes them. This catches profiles left
+// behind when a user's ~/.tsh directory was wiped out-of-band (e.g. by
+// hand, or by a machine rebuild) instead of via "tsh logout", which would
+// otherwise accumulate dead Teleport sections in the user's config files
+// forever.
+func CleanupStale() error {
+	var errs []error
+	for _, load := range []func() (profile.ConnectProfileFile, error){
+		func() (profile.ConnectProfileFile, error) { return postgres.Load() },
+		func() (profile.ConnectProfileFile, error) { return mysql.Load() },
+	} {
+		profileFile, err := load()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := cleanupStale(profileFile); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return trace.NewAggregate(errs...)
+}
+
+func cleanupStale(profileFile profile.ConnectProfileFile) error {
+	profiles, err := profileFile.List()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	var errs []error
+	for _, p := range profiles {
+		if p.CertPath == "" {
+			continue
+		}
+		if _, err := os.Stat(p.CertPath); !os.IsNotExist(err) {
+			continue
+		}
+		if err := profileFile.Delete(p.Name); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return trace.NewAggregate(errs...)
+}
+
 // load loads the appropriate database connection profile.
 func load(db tlsca.RouteToDatabase) (profile.ConnectProfileFile, error) {
 	switch db.Protocol {
@@ -142,8 +219,10 @@ func load(db tlsca.RouteToDatabase) (profile.ConnectProfileFile, error) {
 		db.Protocol)
 }
 
-// profileName constructs the Postgres connection service name from the
-// Teleport cluster name and the database service name.
-func profileName(cluster, name string) string {
+// ProfileName constructs the connection profile entry name (the pg_service
+// entry name, or the suffix of a MySQL option file group) from the
+// Teleport cluster name and the database service name, as used by
+// Add/Env/Delete.
+func ProfileName(cluster, name string) string {
 	return fmt.Sprintf("%v-%v", cluster, name)
 }
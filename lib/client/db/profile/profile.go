@@ -24,6 +24,8 @@ type ConnectProfileFile interface {
 	Env(name string) (map[string]string, error)
 	// Delete removes the specified connection profile.
 	Delete(name string) error
+	// List returns all connection profiles currently saved.
+	List() ([]ConnectProfile, error)
 }
 
 // ConnectProfile represents a database connection profile parameters.
@@ -46,4 +48,11 @@ type ConnectProfile struct {
 	CertPath string
 	// KeyPath is the client key path.
 	KeyPath string
+	// KeyPassphrase is the passphrase protecting KeyPath, if any.
+	KeyPassphrase string
+	// Endpoint is the name of the alternate database endpoint (e.g. a
+	// reader endpoint) this connection targets, if any. It's surfaced to
+	// the native client as an informational annotation where the client
+	// supports one; it does not itself perform endpoint routing.
+	Endpoint string
 }
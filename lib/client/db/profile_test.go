@@ -17,6 +17,8 @@ limitations under the License.
 package db
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/gravitational/teleport/lib/client"
@@ -108,7 +110,7 @@ func TestAddProfile(t *testing.T) {
 			actual, err := add(tc, db, ps, &testProfileFile{profiles: make(map[string]profile.ConnectProfile)}, "root-cluster")
 			require.NoError(t, err)
 			require.EqualValues(t, &profile.ConnectProfile{
-				Name:       profileName(tc.SiteName, db.ServiceName),
+				Name:       ProfileName(tc.SiteName, db.ServiceName),
 				Host:       test.profileHostOut,
 				Port:       test.profilePortOut,
 				CACertPath: ps.CACertPathForCluster("root-cluster"),
@@ -119,6 +121,67 @@ func TestAddProfile(t *testing.T) {
 	}
 }
 
+// TestCleanupStale verifies that cleanupStale removes only the profiles
+// whose certificate no longer exists on disk.
+func TestCleanupStale(t *testing.T) {
+	dir := t.TempDir()
+	liveCert := filepath.Join(dir, "live-cert.pem")
+	require.NoError(t, os.WriteFile(liveCert, []byte("cert"), 0600))
+	staleCert := filepath.Join(dir, "stale-cert.pem")
+
+	profileFile := &testProfileFile{profiles: map[string]profile.ConnectProfile{
+		"root-live": {
+			Name:     "root-live",
+			CertPath: liveCert,
+		},
+		"root-stale": {
+			Name:     "root-stale",
+			CertPath: staleCert,
+		},
+		"root-no-cert": {
+			Name: "root-no-cert",
+		},
+	}}
+
+	require.NoError(t, cleanupStale(profileFile))
+
+	remaining, err := profileFile.List()
+	require.NoError(t, err)
+	var names []string
+	for _, p := range remaining {
+		names = append(names, p.Name)
+	}
+	require.ElementsMatch(t, []string{"root-live", "root-no-cert"}, names)
+}
+
+// TestCACertPath verifies that the CA certificate path prefers a trusted
+// leaf cluster's own CA bundle when one exists on disk, and falls back to
+// the root cluster's bundle otherwise.
+func TestCACertPath(t *testing.T) {
+	ps := client.ProfileStatus{
+		Dir:  t.TempDir(),
+		Name: "alice",
+	}
+
+	t.Run("root cluster", func(t *testing.T) {
+		tc := &client.TeleportClient{Config: client.Config{SiteName: "root-cluster"}}
+		require.Equal(t, ps.CACertPathForCluster("root-cluster"), CACertPath(tc, ps, "root-cluster"))
+	})
+
+	t.Run("leaf cluster without its own CA bundle on disk", func(t *testing.T) {
+		tc := &client.TeleportClient{Config: client.Config{SiteName: "leaf-cluster"}}
+		require.Equal(t, ps.CACertPathForCluster("root-cluster"), CACertPath(tc, ps, "root-cluster"))
+	})
+
+	t.Run("leaf cluster with its own CA bundle on disk", func(t *testing.T) {
+		tc := &client.TeleportClient{Config: client.Config{SiteName: "leaf-cluster"}}
+		leafPath := ps.CACertPathForCluster("leaf-cluster")
+		require.NoError(t, os.MkdirAll(filepath.Dir(leafPath), 0700))
+		require.NoError(t, os.WriteFile(leafPath, []byte("leaf CA"), 0600))
+		require.Equal(t, leafPath, CACertPath(tc, ps, "root-cluster"))
+	})
+}
+
 // testProfileFile is the test implementation of connection profile file.
 type testProfileFile struct {
 	profiles map[string]profile.ConnectProfile
@@ -140,3 +203,12 @@ func (p *testProfileFile) Delete(name string) error {
 	delete(p.profiles, name)
 	return nil
 }
+
+// List returns all connection profiles currently saved.
+func (p *testProfileFile) List() ([]profile.ConnectProfile, error) {
+	var profiles []profile.ConnectProfile
+	for _, cp := range p.profiles {
+		profiles = append(profiles, cp)
+	}
+	return profiles, nil
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2022 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gravitational/trace"
+
+	"github.com/gravitational/teleport/api/types"
+	alpncommon "github.com/gravitational/teleport/lib/srv/alpnproxy/common"
+	"github.com/gravitational/teleport/lib/tlsca"
+)
+
+// databaseProbeTimeout bounds how long a single database's connectivity
+// probe may take, so one unreachable database can't stall the whole batch.
+const databaseProbeTimeout = 5 * time.Second
+
+// DatabaseConnectivityStatus is the outcome of probing a single database's
+// reachability through the Teleport proxy.
+type DatabaseConnectivityStatus struct {
+	// Database is the probed database.
+	Database types.Database
+	// Reachable is true if the probe succeeded within the probe timeout.
+	Reachable bool
+	// Latency is how long the probe took to complete. Zero if unreachable.
+	Latency time.Duration
+	// Error explains why the probe failed, nil if Reachable is true.
+	Error error
+}
+
+// ProbeDatabasesConnectivity concurrently probes connectivity to every
+// database in databases via tc's active proxy and returns one
+// DatabaseConnectivityStatus per database, in the same order. It powers
+// `tsh db ls --verbose`'s health column.
+//
+// The probe opens a TCP connection to the proxy's database endpoint and,
+// when TLS routing is enabled, completes a TLS handshake that advertises
+// the database's ALPN protocol, confirming the proxy can route to it. It
+// does not perform a protocol-level login handshake (e.g. a Postgres
+// startup message), so a reachable result doesn't guarantee the database
+// itself would accept a connection.
+func (tc *TeleportClient) ProbeDatabasesConnectivity(ctx context.Context, databases []types.Database) []DatabaseConnectivityStatus {
+	statuses := make([]DatabaseConnectivityStatus, len(databases))
+	var wg sync.WaitGroup
+	for i, database := range databases {
+		wg.Add(1)
+		go func(i int, database types.Database) {
+			defer wg.Done()
+			statuses[i] = tc.probeDatabaseConnectivity(ctx, database)
+		}(i, database)
+	}
+	wg.Wait()
+	return statuses
+}
+
+func (tc *TeleportClient) probeDatabaseConnectivity(ctx context.Context, database types.Database) DatabaseConnectivityStatus {
+	probeCtx, cancel := context.WithTimeout(ctx, databaseProbeTimeout)
+	defer cancel()
+
+	route := tlsca.RouteToDatabase{ServiceName: database.GetName(), Protocol: database.GetProtocol()}
+	host, port := tc.DatabaseProxyHostPort(route)
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
+
+	start := time.Now()
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(probeCtx, "tcp", addr)
+	if err != nil {
+		return DatabaseConnectivityStatus{Database: database, Error: trace.Wrap(err)}
+	}
+	defer conn.Close()
+
+	if tc.TLSRoutingEnabled {
+		alpnProtocol, err := alpncommon.ToALPNProtocol(database.GetProtocol())
+		if err != nil {
+			return DatabaseConnectivityStatus{Database: database, Error: trace.Wrap(err)}
+		}
+		tlsConn := tls.Client(conn, &tls.Config{
+			NextProtos:         []string{string(alpnProtocol)},
+			InsecureSkipVerify: tc.InsecureSkipVerify,
+			ServerName:         host,
+		})
+		if err := tlsConn.HandshakeContext(probeCtx); err != nil {
+			return DatabaseConnectivityStatus{Database: database, Error: trace.Wrap(err)}
+		}
+	}
+
+	return DatabaseConnectivityStatus{Database: database, Reachable: true, Latency: time.Since(start)}
+}
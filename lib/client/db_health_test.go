@@ -0,0 +1,81 @@
+/*
+Copyright 2022 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gravitational/teleport/api/types"
+	"github.com/gravitational/teleport/lib/defaults"
+)
+
+func TestProbeDatabasesConnectivity(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	// An address nothing is listening on, for the unreachable case.
+	unreachable, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	unreachableAddr := unreachable.Addr().String()
+	require.NoError(t, unreachable.Close())
+
+	reachableDB, err := types.NewDatabaseV3(types.Metadata{Name: "reachable"}, types.DatabaseSpecV3{
+		Protocol: defaults.ProtocolPostgres,
+		URI:      "localhost:5432",
+	})
+	require.NoError(t, err)
+	unreachableDB, err := types.NewDatabaseV3(types.Metadata{Name: "unreachable"}, types.DatabaseSpecV3{
+		Protocol: defaults.ProtocolMySQL,
+		URI:      "localhost:3306",
+	})
+	require.NoError(t, err)
+
+	tc, err := NewClient(&Config{
+		WebProxyAddr: listener.Addr().String(),
+		SiteName:     "example.com",
+		Username:     "bob",
+		HostLogin:    "bob",
+		KeysDir:      t.TempDir(),
+	})
+	require.NoError(t, err)
+	// MySQL resolves to its own proxy addr when set, so route it at the
+	// address nothing is listening on to exercise the unreachable case.
+	tc.MySQLProxyAddr = unreachableAddr
+
+	statuses := tc.ProbeDatabasesConnectivity(context.Background(), []types.Database{reachableDB, unreachableDB})
+	require.Len(t, statuses, 2)
+
+	require.True(t, statuses[0].Reachable)
+	require.NoError(t, statuses[0].Error)
+
+	require.False(t, statuses[1].Reachable)
+	require.Error(t, statuses[1].Error)
+}
@@ -83,6 +83,12 @@ type Key struct {
 	// DBTLSCerts are PEM-encoded TLS certificates for database access.
 	// Map key is the database service name.
 	DBTLSCerts map[string][]byte `json:"DBCerts,omitempty"`
+	// DBTLSKeys are PEM-encoded private keys for database access.
+	// Map key is the database service name. A given entry is only
+	// populated when a database route was issued its own keypair
+	// instead of reusing Priv; databases without an entry here fall
+	// back to Priv.
+	DBTLSKeys map[string][]byte `json:"DBKeys,omitempty"`
 	// AppTLSCerts are TLS certificates for application access.
 	// Map key is the application name.
 	AppTLSCerts map[string][]byte `json:"AppCerts,omitempty"`
@@ -106,6 +112,7 @@ func NewKey() (key *Key, err error) {
 		Pub:          pub,
 		KubeTLSCerts: make(map[string][]byte),
 		DBTLSCerts:   make(map[string][]byte),
+		DBTLSKeys:    make(map[string][]byte),
 	}, nil
 }
 
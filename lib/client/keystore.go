@@ -168,6 +168,12 @@ func (fs *FSLocalKeyStore) AddKey(key *Key) error {
 			return trace.Wrap(err)
 		}
 	}
+	for db, priv := range key.DBTLSKeys {
+		path := fs.databaseKeyPath(key.KeyIndex, filepath.Clean(db))
+		if err := fs.writeBytes(priv, path); err != nil {
+			return trace.Wrap(err)
+		}
+	}
 	for app, cert := range key.AppTLSCerts {
 		path := fs.appCertPath(key.KeyIndex, filepath.Clean(app))
 		if err := fs.writeBytes(cert, path); err != nil {
@@ -545,6 +551,11 @@ func (fs *fsLocalNonSessionKeyStore) databaseCertPath(idx KeyIndex, dbname strin
 	return keypaths.DatabaseCertPath(fs.KeyDir, idx.ProxyHost, idx.Username, idx.ClusterName, dbname)
 }
 
+// databaseKeyPath returns the private key path for the given KeyIndex and database name.
+func (fs *fsLocalNonSessionKeyStore) databaseKeyPath(idx KeyIndex, dbname string) string {
+	return keypaths.DatabaseKeyPath(fs.KeyDir, idx.ProxyHost, idx.Username, idx.ClusterName, dbname)
+}
+
 // kubeCertPath returns the TLS certificate path for the given KeyIndex and kube cluster name.
 func (fs *fsLocalNonSessionKeyStore) kubeCertPath(idx KeyIndex, kubename string) string {
 	return keypaths.KubeCertPath(fs.KeyDir, idx.ProxyHost, idx.Username, idx.ClusterName, kubename)
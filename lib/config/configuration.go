@@ -986,6 +986,22 @@ func applySSHConfig(fc *FileConfig, cfg *service.Config) (err error) {
 		return trace.Wrap(err)
 	}
 
+	cfg.SSH.AllowedHostGroups = fc.SSH.AllowedHostGroups
+	cfg.SSH.HostUserToolPaths = fc.SSH.HostUserToolPaths
+	cfg.SSH.MaxSessionsPerHostUser = fc.SSH.MaxSessionsPerHostUser
+
+	if fc.SSH.HostUserAdminListenAddr != "" {
+		addr, err := utils.ParseHostPortAddr(fc.SSH.HostUserAdminListenAddr, int(defaults.SSHServerListenPort))
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		cfg.SSH.HostUserAdminAddr = *addr
+	}
+
+	cfg.SSH.HostUserUsernameTemplate = fc.SSH.HostUserUsernameTemplate
+	cfg.SSH.VerifyHostUserShadowIntegrity = fc.SSH.VerifyHostUserShadowIntegrity
+	cfg.SSH.EphemeralHostUserMOTD = fc.SSH.EphemeralHostUserMOTD
+
 	return nil
 }
 
@@ -838,6 +838,54 @@ type SSH struct {
 
 	// X11 is used to configure X11 forwarding settings
 	X11 *X11 `yaml:"x11,omitempty"`
+
+	// AllowedHostGroups is an explicit allow-list of host groups that roles
+	// are permitted to grant to host users created on this node via the
+	// host_groups role option. Any role-requested group not on this list is
+	// dropped rather than applied, so privileged groups like wheel, sudo or
+	// docker remain centrally governed on a per-node basis. If unset, no
+	// role-requested groups are applied.
+	AllowedHostGroups apiutils.Strings `yaml:"allowed_host_groups,omitempty"`
+
+	// HostUserToolPaths overrides the paths of the useradd/usermod/userdel/
+	// passwd/chage system utilities used to manage host user accounts,
+	// keyed by tool name (e.g. "useradd"). Useful on distros that don't
+	// install these tools under a directory covered by the agent's
+	// inherited PATH, such as NixOS or custom prefixes. Any tool not
+	// listed is located via PATH as usual.
+	HostUserToolPaths map[string]string `yaml:"host_user_tool_paths,omitempty"`
+
+	// MaxSessionsPerHostUser caps the number of concurrent sessions this
+	// node allows for a single Teleport-provisioned host user login. 0 (the
+	// default) means no limit.
+	MaxSessionsPerHostUser int `yaml:"max_sessions_per_host_user,omitempty"`
+
+	// HostUserAdminListenAddr, if set, starts a gRPC HostUserAdminService
+	// listener on this node at the given address, letting authenticated
+	// callers list, create and delete this node's Teleport-managed host
+	// users without shelling into the box. Unset by default.
+	HostUserAdminListenAddr string `yaml:"host_user_admin_listen_addr,omitempty"`
+
+	// HostUserUsernameTemplate, if set, is applied to the login of every
+	// static host user account this node provisions before the account is
+	// created, e.g. "tp-{{username}}" turns a request for "alice" into the
+	// local account "tp-alice". Temporary per-session host users are created
+	// under the requested login as-is. It must
+	// contain the literal placeholder "{{username}}". Unset (the default)
+	// provisions accounts under the requested login as-is.
+	HostUserUsernameTemplate string `yaml:"host_user_username_template,omitempty"`
+
+	// VerifyHostUserShadowIntegrity, if true, runs pwck/grpck in report-only
+	// mode before every host user creation or removal and refuses the
+	// operation if either finds the local passwd/group/shadow databases
+	// inconsistent. Leave unset on distributions that don't ship pwck/grpck.
+	VerifyHostUserShadowIntegrity bool `yaml:"verify_host_user_shadow_integrity,omitempty"`
+
+	// EphemeralHostUserMOTD, if true, writes a login banner on every
+	// temporary host user provisioned for the lifetime of a single session,
+	// telling whoever logs into it that the account is temporary and will
+	// be removed when the session ends.
+	EphemeralHostUserMOTD bool `yaml:"ephemeral_host_user_motd,omitempty"`
 }
 
 // AllowTCPForwarding checks whether the config file allows TCP forwarding or not.
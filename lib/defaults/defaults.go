@@ -539,6 +539,25 @@ const (
 	ProtocolCockroachDB = "cockroachdb"
 	// ProtocolSQLServer is the Microsoft SQL Server database protocol.
 	ProtocolSQLServer = "sqlserver"
+	// ProtocolCassandra is the Cassandra database protocol. It's also used
+	// for Cassandra-compatible databases such as ScyllaDB.
+	ProtocolCassandra = "cassandra"
+	// ProtocolElasticsearch is the Elasticsearch database protocol.
+	ProtocolElasticsearch = "elasticsearch"
+	// ProtocolOracle is the Oracle database protocol.
+	ProtocolOracle = "oracle"
+	// ProtocolClickHouse is the ClickHouse database protocol served over
+	// ClickHouse's native wire protocol.
+	ProtocolClickHouse = "clickhouse"
+	// ProtocolClickHouseHTTP is the ClickHouse database protocol served
+	// over ClickHouse's HTTP interface.
+	ProtocolClickHouseHTTP = "clickhousehttp"
+	// ProtocolSnowflake is the Snowflake database protocol.
+	ProtocolSnowflake = "snowflake"
+	// ProtocolDynamoDB is the DynamoDB database protocol.
+	ProtocolDynamoDB = "dynamodb"
+	// ProtocolNeo4j is the Neo4j database protocol, served over Bolt.
+	ProtocolNeo4j = "neo4j"
 )
 
 // DatabaseProtocols is a list of all supported database protocols.
@@ -549,6 +568,14 @@ var DatabaseProtocols = []string{
 	ProtocolCockroachDB,
 	ProtocolRedis,
 	ProtocolSQLServer,
+	ProtocolCassandra,
+	ProtocolElasticsearch,
+	ProtocolOracle,
+	ProtocolClickHouse,
+	ProtocolClickHouseHTTP,
+	ProtocolSnowflake,
+	ProtocolDynamoDB,
+	ProtocolNeo4j,
 }
 
 const (
@@ -566,6 +566,40 @@ type SSHConfig struct {
 
 	// X11 holds x11 forwarding configuration for Teleport.
 	X11 *x11.ServerConfig
+
+	// AllowedHostGroups is an explicit allow-list of host groups that roles
+	// are permitted to grant to host users created on this node. Any
+	// role-requested group not on this list is dropped.
+	AllowedHostGroups []string
+
+	// HostUserToolPaths overrides the paths of the system utilities used to
+	// manage host user accounts, keyed by tool name (e.g. "useradd"). A
+	// tool not present in the map is located via PATH as usual.
+	HostUserToolPaths map[string]string
+
+	// MaxSessionsPerHostUser caps the number of concurrent sessions allowed
+	// for a single Teleport-provisioned host user login. 0 means no limit.
+	MaxSessionsPerHostUser int
+
+	// HostUserAdminAddr, if set, is the address this node serves the
+	// HostUserAdminService gRPC API on. An empty address means the API isn't
+	// served.
+	HostUserAdminAddr utils.NetAddr
+
+	// HostUserUsernameTemplate, if non-empty, is applied to the login of
+	// every static host user account this node provisions before the account
+	// is created; temporary per-session host users keep the requested login.
+	// See hostusers.NewHostUsersManagement for the expected format.
+	HostUserUsernameTemplate string
+
+	// VerifyHostUserShadowIntegrity, if true, runs pwck/grpck in report-only
+	// mode before every host user creation or removal, refusing the
+	// operation if the local passwd/group/shadow databases are inconsistent.
+	VerifyHostUserShadowIntegrity bool
+
+	// EphemeralHostUserMOTD, if true, writes a login banner on every
+	// temporary host user provisioned for the lifetime of a single session.
+	EphemeralHostUserMOTD bool
 }
 
 // KubeConfig specifies configuration for kubernetes service
@@ -44,6 +44,7 @@ var (
 	listenerProxyMongo        = listenerType(teleport.Component(teleport.ComponentProxy, "mongo"))
 	listenerMetrics           = listenerType(teleport.ComponentMetrics)
 	listenerWindowsDesktop    = listenerType(teleport.ComponentWindowsDesktop)
+	listenerHostUserAdmin     = listenerType(teleport.Component(teleport.ComponentNode, "hostuseradmin"))
 )
 
 // AuthSSHAddr returns auth server SSH endpoint, if configured and started.
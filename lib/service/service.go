@@ -90,6 +90,8 @@ import (
 	"github.com/gravitational/teleport/lib/srv/app"
 	"github.com/gravitational/teleport/lib/srv/db"
 	"github.com/gravitational/teleport/lib/srv/desktop"
+	"github.com/gravitational/teleport/lib/srv/hostuseradmin"
+	"github.com/gravitational/teleport/lib/srv/hostusers"
 	"github.com/gravitational/teleport/lib/srv/regular"
 	"github.com/gravitational/teleport/lib/system"
 	"github.com/gravitational/teleport/lib/utils"
@@ -1771,6 +1773,7 @@ func (process *TeleportProcess) initSSH() error {
 	var rm restricted.Manager
 	var s *regular.Server
 	var asyncEmitter *events.AsyncEmitter
+	var hostUserAdminServer *grpc.Server
 
 	process.RegisterCriticalFunc("ssh.node", func() error {
 		var ok bool
@@ -1904,6 +1907,15 @@ func (process *TeleportProcess) initSSH() error {
 			return trace.Wrap(err)
 		}
 
+		hostUsers := hostusers.NewHostUsersManagement(
+			cfg.SSH.AllowedHostGroups,
+			cfg.SSH.HostUserToolPaths,
+			cfg.SSH.MaxSessionsPerHostUser,
+			cfg.SSH.HostUserUsernameTemplate,
+			cfg.SSH.VerifyHostUserShadowIntegrity,
+			cfg.SSH.EphemeralHostUserMOTD,
+		)
+
 		s, err = regular.New(cfg.SSH.Addr,
 			cfg.Hostname,
 			[]ssh.Signer{conn.ServerIdentity.KeySigner},
@@ -1932,11 +1944,39 @@ func (process *TeleportProcess) initSSH() error {
 			regular.SetAllowTCPForwarding(cfg.SSH.AllowTCPForwarding),
 			regular.SetLockWatcher(lockWatcher),
 			regular.SetX11ForwardingConfig(cfg.SSH.X11),
+			regular.SetHostUsers(hostUsers),
 		)
 		if err != nil {
 			return trace.Wrap(err)
 		}
 
+		go hostusers.RunReconciler(process.ExitContext(), hostUsers, hostusers.ReconcilerConfig{
+			HostID:          conn.ServerIdentity.ID.HostUUID,
+			Labels:          func() map[string]string { return cfg.SSH.Labels },
+			StaticHostUsers: conn.Client,
+			Reporter:        conn.Client,
+			RemovalChecker:  conn.Client,
+			GCPolicySource:  conn.Client,
+		})
+
+		if !cfg.SSH.HostUserAdminAddr.IsEmpty() {
+			hostUserAdminListener, err := process.importOrCreateListener(listenerHostUserAdmin, cfg.SSH.HostUserAdminAddr.Addr)
+			if err != nil {
+				return trace.Wrap(err)
+			}
+			hostUserAdminServer = grpc.NewServer(
+				grpc.UnaryInterceptor(utils.ErrorConvertUnaryInterceptor),
+				grpc.StreamInterceptor(utils.ErrorConvertStreamInterceptor),
+			)
+			hostuseradmin.NewServer(hostUsers).Register(hostUserAdminServer)
+			log.Infof("HostUserAdminService is starting on %v.", cfg.SSH.HostUserAdminAddr.Addr)
+			go func() {
+				if err := hostUserAdminServer.Serve(hostUserAdminListener); err != nil && err != grpc.ErrServerStopped {
+					log.WithError(err).Warn("HostUserAdminService exited.")
+				}
+			}()
+		}
+
 		// init uploader service for recording SSH node, if proxy is not
 		// enabled on this node, because proxy stars uploader service as well
 		if !cfg.Proxy.Enabled {
@@ -2034,6 +2074,10 @@ func (process *TeleportProcess) initSSH() error {
 			warnOnErr(asyncEmitter.Close(), log)
 		}
 
+		if hostUserAdminServer != nil {
+			hostUserAdminServer.Stop()
+		}
+
 		if conn != nil {
 			warnOnErr(conn.Close(), log)
 		}
@@ -0,0 +1,247 @@
+/*
+Copyright 2022 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+
+	"github.com/gravitational/trace"
+
+	"github.com/gravitational/teleport/api/types"
+	"github.com/gravitational/teleport/lib/utils"
+)
+
+// StaticHostUsers defines an interface for managing static host users.
+type StaticHostUsers interface {
+	// GetStaticHostUser returns a static host user by name.
+	GetStaticHostUser(ctx context.Context, name string) (types.StaticHostUser, error)
+	// GetStaticHostUsers returns all static host users.
+	GetStaticHostUsers(ctx context.Context) ([]types.StaticHostUser, error)
+	// CreateStaticHostUser creates a new static host user.
+	CreateStaticHostUser(ctx context.Context, user types.StaticHostUser) error
+	// UpdateStaticHostUser updates an existing static host user.
+	UpdateStaticHostUser(ctx context.Context, user types.StaticHostUser) error
+	// UpsertStaticHostUser creates or updates a static host user.
+	UpsertStaticHostUser(ctx context.Context, user types.StaticHostUser) error
+	// DeleteStaticHostUser removes the named static host user.
+	DeleteStaticHostUser(ctx context.Context, name string) error
+	// DeleteAllStaticHostUsers removes all static host users.
+	DeleteAllStaticHostUsers(ctx context.Context) error
+}
+
+// MarshalStaticHostUser marshals the StaticHostUser resource to JSON.
+func MarshalStaticHostUser(u types.StaticHostUser, opts ...MarshalOption) ([]byte, error) {
+	if err := u.CheckAndSetDefaults(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	cfg, err := CollectOptions(opts)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	switch u := u.(type) {
+	case *types.StaticHostUserV1:
+		if !cfg.PreserveResourceID {
+			// avoid modifying the original object
+			// to prevent unexpected data races
+			copy := *u
+			copy.SetResourceID(0)
+			u = &copy
+		}
+		return utils.FastMarshal(u)
+	default:
+		return nil, trace.BadParameter("unrecognized static host user version %T", u)
+	}
+}
+
+// UnmarshalStaticHostUser unmarshals the StaticHostUser resource from JSON.
+func UnmarshalStaticHostUser(data []byte, opts ...MarshalOption) (types.StaticHostUser, error) {
+	if len(data) == 0 {
+		return nil, trace.BadParameter("missing static host user data")
+	}
+	cfg, err := CollectOptions(opts)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var h types.ResourceHeader
+	if err := utils.FastUnmarshal(data, &h); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	switch h.Version {
+	case types.V1:
+		var u types.StaticHostUserV1
+		if err := utils.FastUnmarshal(data, &u); err != nil {
+			return nil, trace.BadParameter(err.Error())
+		}
+		if err := u.CheckAndSetDefaults(); err != nil {
+			return nil, trace.Wrap(err)
+		}
+		if cfg.ID != 0 {
+			u.SetResourceID(cfg.ID)
+		}
+		if !cfg.Expires.IsZero() {
+			u.SetExpiry(cfg.Expires)
+		}
+		return &u, nil
+	}
+	return nil, trace.BadParameter("static host user resource version %q is not supported", h.Version)
+}
+
+// HostUserRecords defines an interface for managing host user records
+// reported by nodes.
+type HostUserRecords interface {
+	// GetHostUserRecords returns host user records reported by hostID, or by
+	// every host if hostID is empty.
+	GetHostUserRecords(ctx context.Context, hostID string) ([]types.HostUserRecord, error)
+	// UpsertHostUserRecord creates or updates a host user record.
+	UpsertHostUserRecord(ctx context.Context, record types.HostUserRecord) error
+	// DeleteHostUserRecord removes a single host user record.
+	DeleteHostUserRecord(ctx context.Context, hostID, login string) error
+	// DeleteAllHostUserRecordsForHost removes all host user records reported
+	// by hostID.
+	DeleteAllHostUserRecordsForHost(ctx context.Context, hostID string) error
+}
+
+// MarshalHostUserRecord marshals the HostUserRecord resource to JSON.
+func MarshalHostUserRecord(r types.HostUserRecord, opts ...MarshalOption) ([]byte, error) {
+	if err := r.CheckAndSetDefaults(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	cfg, err := CollectOptions(opts)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	switch r := r.(type) {
+	case *types.HostUserRecordV1:
+		if !cfg.PreserveResourceID {
+			// avoid modifying the original object
+			// to prevent unexpected data races
+			copy := *r
+			copy.SetResourceID(0)
+			r = &copy
+		}
+		return utils.FastMarshal(r)
+	default:
+		return nil, trace.BadParameter("unrecognized host user record version %T", r)
+	}
+}
+
+// UnmarshalHostUserRecord unmarshals the HostUserRecord resource from JSON.
+func UnmarshalHostUserRecord(data []byte, opts ...MarshalOption) (types.HostUserRecord, error) {
+	if len(data) == 0 {
+		return nil, trace.BadParameter("missing host user record data")
+	}
+	cfg, err := CollectOptions(opts)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var h types.ResourceHeader
+	if err := utils.FastUnmarshal(data, &h); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	switch h.Version {
+	case types.V1:
+		var r types.HostUserRecordV1
+		if err := utils.FastUnmarshal(data, &r); err != nil {
+			return nil, trace.BadParameter(err.Error())
+		}
+		if err := r.CheckAndSetDefaults(); err != nil {
+			return nil, trace.Wrap(err)
+		}
+		if cfg.ID != 0 {
+			r.SetResourceID(cfg.ID)
+		}
+		if !cfg.Expires.IsZero() {
+			r.SetExpiry(cfg.Expires)
+		}
+		return &r, nil
+	}
+	return nil, trace.BadParameter("host user record resource version %q is not supported", h.Version)
+}
+
+// HostUserGCPolicies defines an interface for managing the cluster-level
+// HostUserGCPolicy singleton.
+type HostUserGCPolicies interface {
+	// GetHostUserGCPolicy returns the cluster's host user garbage
+	// collection policy.
+	GetHostUserGCPolicy(ctx context.Context) (types.HostUserGCPolicy, error)
+	// SetHostUserGCPolicy sets the cluster's host user garbage collection
+	// policy.
+	SetHostUserGCPolicy(ctx context.Context, policy types.HostUserGCPolicy) error
+}
+
+// MarshalHostUserGCPolicy marshals the HostUserGCPolicy resource to JSON.
+func MarshalHostUserGCPolicy(p types.HostUserGCPolicy, opts ...MarshalOption) ([]byte, error) {
+	if err := p.CheckAndSetDefaults(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	cfg, err := CollectOptions(opts)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	switch p := p.(type) {
+	case *types.HostUserGCPolicyV1:
+		if !cfg.PreserveResourceID {
+			// avoid modifying the original object
+			// to prevent unexpected data races
+			copy := *p
+			copy.SetResourceID(0)
+			p = &copy
+		}
+		return utils.FastMarshal(p)
+	default:
+		return nil, trace.BadParameter("unrecognized host user GC policy version %T", p)
+	}
+}
+
+// UnmarshalHostUserGCPolicy unmarshals the HostUserGCPolicy resource from JSON.
+func UnmarshalHostUserGCPolicy(data []byte, opts ...MarshalOption) (types.HostUserGCPolicy, error) {
+	if len(data) == 0 {
+		return nil, trace.BadParameter("missing host user GC policy data")
+	}
+	cfg, err := CollectOptions(opts)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var h types.ResourceHeader
+	if err := utils.FastUnmarshal(data, &h); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	switch h.Version {
+	case types.V1:
+		var p types.HostUserGCPolicyV1
+		if err := utils.FastUnmarshal(data, &p); err != nil {
+			return nil, trace.BadParameter(err.Error())
+		}
+		if err := p.CheckAndSetDefaults(); err != nil {
+			return nil, trace.Wrap(err)
+		}
+		if cfg.ID != 0 {
+			p.SetResourceID(cfg.ID)
+		}
+		if !cfg.Expires.IsZero() {
+			p.SetExpiry(cfg.Expires)
+		}
+		return &p, nil
+	}
+	return nil, trace.BadParameter("host user GC policy resource version %q is not supported", h.Version)
+}
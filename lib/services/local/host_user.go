@@ -0,0 +1,255 @@
+/*
+Copyright 2022 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package local
+
+import (
+	"context"
+
+	"github.com/gravitational/trace"
+
+	"github.com/gravitational/teleport/api/types"
+	"github.com/gravitational/teleport/lib/backend"
+	"github.com/gravitational/teleport/lib/services"
+)
+
+// StaticHostUserService manages static host user resources in the backend.
+type StaticHostUserService struct {
+	backend.Backend
+}
+
+// NewStaticHostUserService creates a new StaticHostUserService.
+func NewStaticHostUserService(backend backend.Backend) *StaticHostUserService {
+	return &StaticHostUserService{Backend: backend}
+}
+
+// GetStaticHostUser returns a static host user by name.
+func (s *StaticHostUserService) GetStaticHostUser(ctx context.Context, name string) (types.StaticHostUser, error) {
+	if name == "" {
+		return nil, trace.BadParameter("missing static host user name")
+	}
+	item, err := s.Get(ctx, backend.Key(staticHostUserPrefix, name))
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return nil, trace.NotFound("static host user %q doesn't exist", name)
+		}
+		return nil, trace.Wrap(err)
+	}
+	return services.UnmarshalStaticHostUser(item.Value,
+		services.WithResourceID(item.ID), services.WithExpires(item.Expires))
+}
+
+// GetStaticHostUsers returns all static host users.
+func (s *StaticHostUserService) GetStaticHostUsers(ctx context.Context) ([]types.StaticHostUser, error) {
+	startKey := backend.Key(staticHostUserPrefix, "")
+	result, err := s.GetRange(ctx, startKey, backend.RangeEnd(startKey), backend.NoLimit)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	users := make([]types.StaticHostUser, 0, len(result.Items))
+	for _, item := range result.Items {
+		user, err := services.UnmarshalStaticHostUser(item.Value,
+			services.WithResourceID(item.ID), services.WithExpires(item.Expires))
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+// CreateStaticHostUser creates a new static host user.
+func (s *StaticHostUserService) CreateStaticHostUser(ctx context.Context, user types.StaticHostUser) error {
+	value, err := services.MarshalStaticHostUser(user)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	item := backend.Item{
+		Key:     backend.Key(staticHostUserPrefix, user.GetName()),
+		Value:   value,
+		Expires: user.Expiry(),
+		ID:      user.GetResourceID(),
+	}
+	_, err = s.Create(ctx, item)
+	return trace.Wrap(err)
+}
+
+// UpdateStaticHostUser updates an existing static host user.
+func (s *StaticHostUserService) UpdateStaticHostUser(ctx context.Context, user types.StaticHostUser) error {
+	value, err := services.MarshalStaticHostUser(user)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	item := backend.Item{
+		Key:     backend.Key(staticHostUserPrefix, user.GetName()),
+		Value:   value,
+		Expires: user.Expiry(),
+		ID:      user.GetResourceID(),
+	}
+	_, err = s.Update(ctx, item)
+	return trace.Wrap(err)
+}
+
+// UpsertStaticHostUser creates or updates a static host user.
+func (s *StaticHostUserService) UpsertStaticHostUser(ctx context.Context, user types.StaticHostUser) error {
+	value, err := services.MarshalStaticHostUser(user)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	item := backend.Item{
+		Key:     backend.Key(staticHostUserPrefix, user.GetName()),
+		Value:   value,
+		Expires: user.Expiry(),
+		ID:      user.GetResourceID(),
+	}
+	_, err = s.Put(ctx, item)
+	return trace.Wrap(err)
+}
+
+// DeleteStaticHostUser removes the named static host user.
+func (s *StaticHostUserService) DeleteStaticHostUser(ctx context.Context, name string) error {
+	if name == "" {
+		return trace.BadParameter("missing static host user name")
+	}
+	err := s.Delete(ctx, backend.Key(staticHostUserPrefix, name))
+	if trace.IsNotFound(err) {
+		return trace.NotFound("static host user %q doesn't exist", name)
+	}
+	return trace.Wrap(err)
+}
+
+// DeleteAllStaticHostUsers removes all static host users.
+func (s *StaticHostUserService) DeleteAllStaticHostUsers(ctx context.Context) error {
+	startKey := backend.Key(staticHostUserPrefix, "")
+	return trace.Wrap(s.DeleteRange(ctx, startKey, backend.RangeEnd(startKey)))
+}
+
+const staticHostUserPrefix = "staticHostUser"
+
+// HostUserRecordService manages host user records reported by nodes in the
+// backend.
+type HostUserRecordService struct {
+	backend.Backend
+}
+
+// NewHostUserRecordService creates a new HostUserRecordService.
+func NewHostUserRecordService(backend backend.Backend) *HostUserRecordService {
+	return &HostUserRecordService{Backend: backend}
+}
+
+// GetHostUserRecords returns host user records reported by hostID, or by
+// every host if hostID is empty.
+func (s *HostUserRecordService) GetHostUserRecords(ctx context.Context, hostID string) ([]types.HostUserRecord, error) {
+	startKey := backend.Key(hostUserRecordPrefix, "")
+	result, err := s.GetRange(ctx, startKey, backend.RangeEnd(startKey), backend.NoLimit)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	records := make([]types.HostUserRecord, 0, len(result.Items))
+	for _, item := range result.Items {
+		record, err := services.UnmarshalHostUserRecord(item.Value,
+			services.WithResourceID(item.ID), services.WithExpires(item.Expires))
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		if hostID != "" && record.GetHostID() != hostID {
+			continue
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// UpsertHostUserRecord creates or updates a host user record.
+func (s *HostUserRecordService) UpsertHostUserRecord(ctx context.Context, record types.HostUserRecord) error {
+	value, err := services.MarshalHostUserRecord(record)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	item := backend.Item{
+		Key:     backend.Key(hostUserRecordPrefix, record.GetHostID(), record.GetLogin()),
+		Value:   value,
+		Expires: record.Expiry(),
+		ID:      record.GetResourceID(),
+	}
+	_, err = s.Put(ctx, item)
+	return trace.Wrap(err)
+}
+
+// DeleteHostUserRecord removes a single host user record.
+func (s *HostUserRecordService) DeleteHostUserRecord(ctx context.Context, hostID, login string) error {
+	err := s.Delete(ctx, backend.Key(hostUserRecordPrefix, hostID, login))
+	if trace.IsNotFound(err) {
+		return trace.NotFound("host user record %q/%q doesn't exist", hostID, login)
+	}
+	return trace.Wrap(err)
+}
+
+// DeleteAllHostUserRecordsForHost removes all host user records reported by
+// hostID.
+func (s *HostUserRecordService) DeleteAllHostUserRecordsForHost(ctx context.Context, hostID string) error {
+	startKey := backend.Key(hostUserRecordPrefix, hostID)
+	return trace.Wrap(s.DeleteRange(ctx, startKey, backend.RangeEnd(startKey)))
+}
+
+const hostUserRecordPrefix = "hostUserRecord"
+
+// HostUserGCPolicyService manages the cluster-level HostUserGCPolicy
+// singleton in the backend.
+type HostUserGCPolicyService struct {
+	backend.Backend
+}
+
+// NewHostUserGCPolicyService creates a new HostUserGCPolicyService.
+func NewHostUserGCPolicyService(backend backend.Backend) *HostUserGCPolicyService {
+	return &HostUserGCPolicyService{Backend: backend}
+}
+
+// GetHostUserGCPolicy returns the cluster's host user garbage collection
+// policy, or the zero-value policy (immediate deletion, matching the
+// historical behavior) if none has been set.
+func (s *HostUserGCPolicyService) GetHostUserGCPolicy(ctx context.Context) (types.HostUserGCPolicy, error) {
+	item, err := s.Get(ctx, backend.Key(hostUserGCPolicyPrefix, types.MetaNameHostUserGCPolicy))
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return types.NewHostUserGCPolicy(types.HostUserGCPolicySpecV1{})
+		}
+		return nil, trace.Wrap(err)
+	}
+	return services.UnmarshalHostUserGCPolicy(item.Value,
+		services.WithResourceID(item.ID), services.WithExpires(item.Expires))
+}
+
+// SetHostUserGCPolicy sets the cluster's host user garbage collection
+// policy.
+func (s *HostUserGCPolicyService) SetHostUserGCPolicy(ctx context.Context, policy types.HostUserGCPolicy) error {
+	value, err := services.MarshalHostUserGCPolicy(policy)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	item := backend.Item{
+		Key:     backend.Key(hostUserGCPolicyPrefix, types.MetaNameHostUserGCPolicy),
+		Value:   value,
+		Expires: policy.Expiry(),
+		ID:      policy.GetResourceID(),
+	}
+	_, err = s.Put(ctx, item)
+	return trace.Wrap(err)
+}
+
+const hostUserGCPolicyPrefix = "hostUserGCPolicy"
@@ -63,6 +63,7 @@ var DefaultImplicitRules = []types.Rule{
 	types.NewRule(types.KindApp, RO()),
 	types.NewRule(types.KindWindowsDesktopService, RO()),
 	types.NewRule(types.KindWindowsDesktop, RO()),
+	types.NewRule(types.KindHostUserRecord, RO()),
 }
 
 // DefaultCertAuthorityRules provides access the minimal set of resources
@@ -325,6 +326,11 @@ func ApplyTraits(r types.Role, traits map[string][]string) types.Role {
 		outDbUsers := applyValueTraitsSlice(inDbUsers, traits, "database user")
 		r.SetDatabaseUsers(condition, apiutils.Deduplicate(outDbUsers))
 
+		// apply templates to database roles
+		inDbRoles := r.GetDatabaseRoles(condition)
+		outDbRoles := applyValueTraitsSlice(inDbRoles, traits, "database role")
+		r.SetDatabaseRoles(condition, apiutils.Deduplicate(outDbRoles))
+
 		// apply templates to node labels
 		inLabels := r.GetNodeLabels(condition)
 		if inLabels != nil {
@@ -367,6 +373,10 @@ func ApplyTraits(r types.Role, traits map[string][]string) types.Role {
 			}
 		}
 
+		// apply templates to host groups
+		options.HostGroups = apiutils.Deduplicate(applyValueTraitsSlice(options.HostGroups, traits, "host group"))
+		r.SetOptions(options)
+
 		// apply templates to impersonation conditions
 		inCond := r.GetImpersonateConditions(condition)
 		var outCond types.ImpersonateConditions
@@ -402,15 +412,16 @@ func applyValueTraitsSlice(inputs []string, traits map[string][]string, fieldNam
 // and traits from identity provider. For example:
 //
 // cluster_labels:
-//   env: ['{{external.groups}}']
+//
+//	env: ['{{external.groups}}']
 //
 // and groups: ['admins', 'devs']
 //
 // will be interpolated to:
 //
 // cluster_labels:
-//   env: ['admins', 'devs']
 //
+//	env: ['admins', 'devs']
 func applyLabelsTraits(inLabels types.Labels, traits map[string][]string) types.Labels {
 	outLabels := make(types.Labels, len(inLabels))
 	// every key will be mapped to the first value
@@ -545,7 +556,6 @@ func MakeRuleSet(rules []types.Rule) RuleSet {
 // Specifying order solves the problem on having multiple rules, e.g. one wildcard
 // rule can override more specific rules with 'where' sections that can have
 // 'actions' lists with side effects that will not be triggered otherwise.
-//
 func (set RuleSet) Match(whereParser predicate.Parser, actionsParser predicate.Parser, resource string, verb string) (bool, error) {
 	// empty set matches nothing
 	if len(set) == 0 {
@@ -707,6 +717,11 @@ type AccessChecker interface {
 	// is allowed to use.
 	CheckDatabaseNamesAndUsers(ttl time.Duration, overrideTTL bool) (names []string, users []string, err error)
 
+	// CheckDatabaseRoles returns the database roles this role set is allowed
+	// to grant to an auto-provisioned database user, deduplicated and with
+	// any denied roles removed.
+	CheckDatabaseRoles(database types.Database) ([]string, error)
+
 	// CheckImpersonate checks whether current user is allowed to impersonate
 	// users and roles
 	CheckImpersonate(currentUser, impersonateUser types.User, impersonateRoles []types.Role) error
@@ -1132,6 +1147,38 @@ func (set RoleSet) CheckDatabaseNamesAndUsers(ttl time.Duration, overrideTTL boo
 	return utils.StringsSliceFromSet(names), utils.StringsSliceFromSet(users), nil
 }
 
+// CheckDatabaseRoles returns the database roles this role set is allowed to
+// grant to an auto-provisioned database user for the given database,
+// deduplicated and with any denied roles removed.
+func (set RoleSet) CheckDatabaseRoles(database types.Database) ([]string, error) {
+	roles := make(map[string]struct{})
+	for _, role := range set {
+		match, _, err := MatchLabels(role.GetDatabaseLabels(types.Allow), database.GetAllLabels())
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		if !match {
+			continue
+		}
+		for _, dbRole := range role.GetDatabaseRoles(types.Allow) {
+			roles[dbRole] = struct{}{}
+		}
+	}
+	for _, role := range set {
+		match, _, err := MatchLabels(role.GetDatabaseLabels(types.Deny), database.GetAllLabels())
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		if !match {
+			continue
+		}
+		for _, dbRole := range role.GetDatabaseRoles(types.Deny) {
+			delete(roles, dbRole)
+		}
+	}
+	return utils.StringsSliceFromSet(roles), nil
+}
+
 // CheckAWSRoleARNs returns a list of AWS role ARNs this role set is allowed to assume.
 func (set RoleSet) CheckAWSRoleARNs(ttl time.Duration, overrideTTL bool) ([]string, error) {
 	arns := make(map[string]struct{})
@@ -1979,6 +2026,20 @@ func (set RoleSet) EnhancedRecordingSet() map[string]bool {
 	return m
 }
 
+// HostGroups returns the set of additional OS groups every role in the set
+// grants for host users created on a matching node. Traits must already
+// have been applied via ApplyTraits on each role, since templates are
+// resolved at session time, before any host user is created.
+func (set RoleSet) HostGroups() []string {
+	m := make(map[string]struct{})
+	for _, role := range set {
+		for _, group := range role.GetOptions().HostGroups {
+			m[group] = struct{}{}
+		}
+	}
+	return utils.StringsSliceFromSet(m)
+}
+
 // certificatePriority returns the priority of the certificate format. The
 // most permissive has lowest value.
 func certificatePriority(s string) int {
@@ -2772,6 +2772,82 @@ func TestCheckDatabaseNamesAndUsers(t *testing.T) {
 	}
 }
 
+func TestCheckDatabaseRoles(t *testing.T) {
+	dbStage, err := types.NewDatabaseV3(types.Metadata{
+		Name:   "stage",
+		Labels: map[string]string{"env": "stage"},
+	}, types.DatabaseSpecV3{
+		Protocol: "postgres",
+		URI:      "localhost:5432",
+	})
+	require.NoError(t, err)
+	dbProd, err := types.NewDatabaseV3(types.Metadata{
+		Name:   "prod",
+		Labels: map[string]string{"env": "prod"},
+	}, types.DatabaseSpecV3{
+		Protocol: "postgres",
+		URI:      "localhost:5432",
+	})
+	require.NoError(t, err)
+
+	roleDev := &types.RoleV5{
+		Metadata: types.Metadata{Name: "dev", Namespace: apidefaults.Namespace},
+		Spec: types.RoleSpecV5{
+			Allow: types.RoleConditions{
+				Namespaces:     []string{apidefaults.Namespace},
+				DatabaseLabels: types.Labels{"env": []string{"stage"}},
+				DatabaseRoles:  []string{"reader", "writer"},
+			},
+		},
+	}
+	roleDenyWriter := &types.RoleV5{
+		Metadata: types.Metadata{Name: "deny-writer", Namespace: apidefaults.Namespace},
+		Spec: types.RoleSpecV5{
+			Allow: types.RoleConditions{
+				Namespaces: []string{apidefaults.Namespace},
+			},
+			Deny: types.RoleConditions{
+				Namespaces:     []string{apidefaults.Namespace},
+				DatabaseLabels: types.Labels{"env": []string{"stage"}},
+				DatabaseRoles:  []string{"writer"},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name  string
+		roles RoleSet
+		db    types.Database
+		out   []string
+	}{
+		{
+			name:  "allowed roles for matching database",
+			roles: RoleSet{roleDev},
+			db:    dbStage,
+			out:   []string{"reader", "writer"},
+		},
+		{
+			name:  "no roles for non-matching database",
+			roles: RoleSet{roleDev},
+			db:    dbProd,
+			out:   []string{},
+		},
+		{
+			name:  "deny removes matched role",
+			roles: RoleSet{roleDev, roleDenyWriter},
+			db:    dbStage,
+			out:   []string{"reader"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			roles, err := tc.roles.CheckDatabaseRoles(tc.db)
+			require.NoError(t, err)
+			require.ElementsMatch(t, tc.out, roles)
+		})
+	}
+}
+
 func TestCheckAccessToDatabaseService(t *testing.T) {
 	dbNoLabels, err := types.NewDatabaseV3(types.Metadata{
 		Name: "test",
@@ -3568,6 +3644,21 @@ func TestRoleSetLockingMode(t *testing.T) {
 	})
 }
 
+func TestRoleSetHostGroupsAppliesTraits(t *testing.T) {
+	t.Parallel()
+	role, err := types.NewRoleV3("dev", types.RoleSpecV5{
+		Options: types.RoleOptions{
+			HostGroups: []string{"dev-{{external.team}}", "docker"},
+		},
+	})
+	require.NoError(t, err)
+
+	traits := map[string][]string{"team": {"payments"}}
+	set := RoleSet{ApplyTraits(role, traits)}
+
+	require.ElementsMatch(t, []string{"dev-payments", "docker"}, set.HostGroups())
+}
+
 func TestExtractConditionForIdentifier(t *testing.T) {
 	t.Parallel()
 	set := RoleSet{}
@@ -75,6 +75,14 @@ type LocalProxyConfig struct {
 	Certs []tls.Certificate
 	// AWSCredentials are AWS Credentials used by LocalProxy for request's signature verification.
 	AWSCredentials *credentials.Credentials
+	// MinTLSVersion is the minimum acceptable TLS version the local proxy
+	// negotiates with the upstream Teleport proxy. Defaults to the Go
+	// standard library default when unset.
+	MinTLSVersion uint16
+	// CipherSuites is the allow-list of TLS cipher suites the local proxy
+	// negotiates with the upstream Teleport proxy. Defaults to the Go
+	// standard library default when unset.
+	CipherSuites []uint16
 }
 
 // CheckAndSetDefaults verifies the constraints for LocalProxyConfig.
@@ -261,6 +269,8 @@ func (l *LocalProxy) handleDownstreamConnection(ctx context.Context, downstreamC
 		InsecureSkipVerify: l.cfg.InsecureSkipVerify,
 		ServerName:         serverName,
 		Certificates:       l.cfg.Certs,
+		MinVersion:         l.cfg.MinTLSVersion,
+		CipherSuites:       l.cfg.CipherSuites,
 	})
 	if err != nil {
 		return trace.Wrap(err)
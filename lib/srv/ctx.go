@@ -41,6 +41,7 @@ import (
 	restricted "github.com/gravitational/teleport/lib/restrictedsession"
 	"github.com/gravitational/teleport/lib/services"
 	rsession "github.com/gravitational/teleport/lib/session"
+	"github.com/gravitational/teleport/lib/srv/hostusers"
 	"github.com/gravitational/teleport/lib/srv/uacc"
 	"github.com/gravitational/teleport/lib/sshutils"
 	"github.com/gravitational/teleport/lib/sshutils/x11"
@@ -164,6 +165,12 @@ type Server interface {
 
 	// GetLockWatcher gets the server's lock watcher.
 	GetLockWatcher() *services.LockWatcher
+
+	// GetHostUsers returns the server's host user manager, or nil if this
+	// server type doesn't provision host users (for example the forwarding
+	// server, which forwards sessions to another node rather than executing
+	// them locally).
+	GetHostUsers() *hostusers.HostUsersManagement
 }
 
 // IdentityContext holds all identity information associated with the user
@@ -984,6 +991,14 @@ func (c *ServerContext) ExecCommand() (*ExecCommand, error) {
 		return nil, trace.Wrap(err)
 	}
 
+	// If the session's login is a Teleport-managed host user confined to a
+	// chroot, carry that through so the child process is jailed the same
+	// way regardless of which session this is.
+	var chrootDir string
+	if hostUsers := c.srv.GetHostUsers(); hostUsers != nil {
+		chrootDir = hostUsers.ChrootDirForLogin(c.Identity.Login)
+	}
+
 	// Create the execCommand that will be sent to the child process.
 	return &ExecCommand{
 		Command:               command,
@@ -991,6 +1006,7 @@ func (c *ServerContext) ExecCommand() (*ExecCommand, error) {
 		Username:              c.Identity.TeleportUser,
 		Login:                 c.Identity.Login,
 		Roles:                 roleNames,
+		ChrootDir:             chrootDir,
 		Terminal:              c.termAllocated || command == "",
 		RequestType:           requestType,
 		PermitUserEnvironment: c.srv.PermitUserEnvironment(),
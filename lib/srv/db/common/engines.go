@@ -106,3 +106,17 @@ func (c *EngineConfig) CheckAndSetDefaults() error {
 	}
 	return nil
 }
+
+// AutoCreateUser is the default no-op implementation of auto-user
+// provisioning, promoted to engines that embed EngineConfig and don't
+// support it. The Postgres engine, which does support it, overrides it.
+func (c *EngineConfig) AutoCreateUser(ctx context.Context, sessionCtx *Session) error {
+	return nil
+}
+
+// AutoDeleteUser is the default no-op implementation of auto-user
+// cleanup, promoted to engines that embed EngineConfig and don't support
+// auto-user provisioning.
+func (c *EngineConfig) AutoDeleteUser(ctx context.Context, sessionCtx *Session) error {
+	return nil
+}
@@ -78,4 +78,12 @@ type Engine interface {
 	// HandleConnection proxies the connection received from the proxy to
 	// the particular database instance.
 	HandleConnection(context.Context, *Session) error
+	// AutoCreateUser creates or enables the session's requested database
+	// user and grants it the session's database roles, if auto-user
+	// provisioning is enabled for the database. It's a no-op otherwise.
+	AutoCreateUser(ctx context.Context, sessionCtx *Session) error
+	// AutoDeleteUser disables the database user created for this session by
+	// AutoCreateUser. It's a no-op if auto-user provisioning wasn't active
+	// for this session.
+	AutoDeleteUser(ctx context.Context, sessionCtx *Session) error
 }
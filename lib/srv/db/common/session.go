@@ -50,6 +50,12 @@ type Session struct {
 	Log logrus.FieldLogger
 	// LockTargets is a list of lock targets applicable to this session.
 	LockTargets []types.LockTarget
+	// AutoUserProvisioningActive is true if the engine auto-created or
+	// enabled DatabaseUser for the duration of this session.
+	AutoUserProvisioningActive bool
+	// AutoUserDatabaseRoles is the list of database roles granted to
+	// DatabaseUser when AutoUserProvisioningActive is true.
+	AutoUserDatabaseRoles []string
 }
 
 // String returns string representation of the session parameters.
@@ -114,6 +114,17 @@ func (e *Engine) HandleConnection(ctx context.Context, sessionCtx *common.Sessio
 	if err != nil {
 		return trace.Wrap(err)
 	}
+	// Automatically create or enable the requested database user, granting
+	// it the database roles mapped from the Teleport user's roles, if the
+	// database has auto-user provisioning configured.
+	if err := e.AutoCreateUser(ctx, sessionCtx); err != nil {
+		return trace.Wrap(err)
+	}
+	defer func() {
+		if err := e.AutoDeleteUser(ctx, sessionCtx); err != nil {
+			e.Log.WithError(err).Error("Failed to disable auto-created database user.")
+		}
+	}()
 	// This is where we connect to the actual Postgres database.
 	server, hijackedConn, err := e.connect(ctx, sessionCtx)
 	if err != nil {
@@ -0,0 +1,131 @@
+/*
+Copyright 2022 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gravitational/teleport/lib/srv/db/common"
+
+	"github.com/jackc/pgconn"
+
+	"github.com/gravitational/trace"
+)
+
+// AutoCreateUser creates or enables the session's requested database user
+// and grants it the database roles mapped from the connecting Teleport
+// user's role set, if the database has auto-user provisioning configured.
+// It's a no-op otherwise.
+func (e *Engine) AutoCreateUser(ctx context.Context, sessionCtx *common.Session) error {
+	if !sessionCtx.Database.SupportsAutoUsers() {
+		return nil
+	}
+	roles, err := sessionCtx.Checker.CheckDatabaseRoles(sessionCtx.Database)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if len(roles) == 0 {
+		return trace.AccessDenied("%v is not allowed any database roles for %v",
+			sessionCtx.Identity.Username, sessionCtx.Database.GetName())
+	}
+	conn, err := e.connectAsAdmin(ctx, sessionCtx)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	defer conn.Close(ctx)
+	e.Log.Debugf("Auto-creating database user %q with roles %v.", sessionCtx.DatabaseUser, roles)
+	if _, err := conn.Exec(ctx, createOrEnableUserSQL(sessionCtx.DatabaseUser)).ReadAll(); err != nil {
+		return trace.Wrap(err)
+	}
+	if _, err := conn.Exec(ctx, grantRolesSQL(sessionCtx.DatabaseUser, roles)).ReadAll(); err != nil {
+		return trace.Wrap(err)
+	}
+	sessionCtx.AutoUserProvisioningActive = true
+	sessionCtx.AutoUserDatabaseRoles = roles
+	return nil
+}
+
+// AutoDeleteUser disables the database user created for this session by
+// AutoCreateUser. It's a no-op if auto-user provisioning wasn't active for
+// this session.
+func (e *Engine) AutoDeleteUser(ctx context.Context, sessionCtx *common.Session) error {
+	if !sessionCtx.AutoUserProvisioningActive {
+		return nil
+	}
+	conn, err := e.connectAsAdmin(ctx, sessionCtx)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	defer conn.Close(ctx)
+	e.Log.Debugf("Disabling auto-created database user %q.", sessionCtx.DatabaseUser)
+	if _, err := conn.Exec(ctx, disableUserSQL(sessionCtx.DatabaseUser)).ReadAll(); err != nil {
+		return trace.Wrap(err)
+	}
+	return nil
+}
+
+// connectAsAdmin connects to the database as the database's configured
+// admin user in order to perform user provisioning operations.
+func (e *Engine) connectAsAdmin(ctx context.Context, sessionCtx *common.Session) (*pgconn.PgConn, error) {
+	adminSessionCtx := *sessionCtx
+	adminSessionCtx.DatabaseUser = sessionCtx.Database.GetAdminUser().Name
+	adminSessionCtx.DatabaseName = "postgres"
+	config, err := e.getConnectConfig(ctx, &adminSessionCtx)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	conn, err := pgconn.ConnectConfig(ctx, config)
+	if err != nil {
+		return nil, common.ConvertConnectError(err, &adminSessionCtx)
+	}
+	return conn, nil
+}
+
+// createOrEnableUserSQL returns the SQL statement that creates the given
+// database user if it doesn't exist yet, or re-enables its login privilege
+// if it does.
+func createOrEnableUserSQL(username string) string {
+	return "DO $$ BEGIN " +
+		"CREATE ROLE " + quoteIdentifier(username) + " LOGIN; " +
+		"EXCEPTION WHEN duplicate_object THEN " +
+		"ALTER ROLE " + quoteIdentifier(username) + " LOGIN; " +
+		"END $$;"
+}
+
+// disableUserSQL returns the SQL statement that revokes the given database
+// user's login privilege without dropping it, so its audit trail (e.g.
+// ownership of objects) is preserved.
+func disableUserSQL(username string) string {
+	return "ALTER ROLE " + quoteIdentifier(username) + " NOLOGIN;"
+}
+
+// grantRolesSQL returns the SQL statement that grants the provided database
+// roles to the given database user.
+func grantRolesSQL(username string, roles []string) string {
+	quoted := make([]string, len(roles))
+	for i, role := range roles {
+		quoted[i] = quoteIdentifier(role)
+	}
+	return "GRANT " + strings.Join(quoted, ", ") + " TO " + quoteIdentifier(username) + ";"
+}
+
+// quoteIdentifier quotes the provided Postgres identifier (e.g. role name)
+// so it can be safely interpolated into a SQL statement.
+func quoteIdentifier(ident string) string {
+	return `"` + strings.ReplaceAll(ident, `"`, `""`) + `"`
+}
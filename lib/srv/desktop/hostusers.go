@@ -0,0 +1,58 @@
+/*
+Copyright 2022 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package desktop
+
+import "github.com/gravitational/trace"
+
+// DesktopHostUsersBackend provisions and removes local OS user accounts on
+// a Windows desktop for the lifetime of a session, mirroring the
+// create_host_user behavior SSH nodes provide via
+// lib/srv/hostusers.HostUsersBackend.
+//
+// Unlike an SSH node, the Windows Desktop Service does not run on the host
+// it serves sessions for: it proxies RDP connections to a separate Windows
+// machine. Provisioning a local user therefore requires an out-of-band
+// execution channel on that machine (e.g. WinRM, a logon script, or a
+// custom RDP virtual channel) that this implementation does not yet have.
+type DesktopHostUsersBackend interface {
+	// CreateUser creates a local Windows user and adds it to group,
+	// returning true if the user was created (as opposed to already
+	// existing).
+	CreateUser(username, group string) (created bool, err error)
+	// DeleteUser removes a local Windows user previously created by
+	// CreateUser.
+	DeleteUser(username string) error
+}
+
+// NewDesktopHostUsersBackend returns the default DesktopHostUsersBackend
+// for the Windows Desktop Service. There is currently no remote execution
+// transport available to run user-provisioning commands against the
+// target desktop, so it reports trace.NotImplemented rather than silently
+// doing nothing.
+func NewDesktopHostUsersBackend() DesktopHostUsersBackend {
+	return unimplementedDesktopHostUsers{}
+}
+
+type unimplementedDesktopHostUsers struct{}
+
+func (unimplementedDesktopHostUsers) CreateUser(username, group string) (bool, error) {
+	return false, trace.NotImplemented("automatic local Windows user provisioning requires a remote execution transport that is not yet implemented")
+}
+
+func (unimplementedDesktopHostUsers) DeleteUser(username string) error {
+	return trace.NotImplemented("automatic local Windows user provisioning requires a remote execution transport that is not yet implemented")
+}
@@ -165,6 +165,14 @@ type WindowsServiceConfig struct {
 	DiscoveryLDAPFilters []string
 	// Hostname of the windows desktop service
 	Hostname string
+	// CreateDesktopUserGroup is the local Windows group that auto-created
+	// desktop session users are added to. If empty, desktop sessions will
+	// not attempt to auto-create local users, mirroring how create_host_user
+	// is opt-in for SSH nodes.
+	CreateDesktopUserGroup string
+	// HostUsers provisions and removes the local Windows users created for
+	// CreateDesktopUserGroup. Defaults to NewDesktopHostUsersBackend().
+	HostUsers DesktopHostUsersBackend
 }
 
 // LDAPConfig contains parameters for connecting to an LDAP server.
@@ -278,6 +286,9 @@ func (cfg *WindowsServiceConfig) CheckAndSetDefaults() error {
 	if err := cfg.checkAndSetDiscoveryDefaults(); err != nil {
 		return trace.Wrap(err)
 	}
+	if cfg.HostUsers == nil {
+		cfg.HostUsers = NewDesktopHostUsersBackend()
+	}
 
 	return nil
 }
@@ -795,16 +806,29 @@ func (s *WindowsService) connectRDP(ctx context.Context, log logrus.FieldLogger,
 	}()
 
 	var windowsUser string
+	var createdDesktopUser bool
 	authorize := func(login string) error {
 		windowsUser = login // capture attempted login user
 		mfaParams := services.AccessMFAParams{
 			Verified:       identity.MFAVerified != "",
 			AlwaysRequired: authPref.GetRequireSessionMFA(),
 		}
-		return authCtx.Checker.CheckAccess(
+		if err := authCtx.Checker.CheckAccess(
 			desktop,
 			mfaParams,
-			services.NewWindowsLoginMatcher(login))
+			services.NewWindowsLoginMatcher(login)); err != nil {
+			return err
+		}
+
+		if s.cfg.CreateDesktopUserGroup != "" && desktop.GetDomain() == "" {
+			created, err := s.cfg.HostUsers.CreateUser(login, s.cfg.CreateDesktopUserGroup)
+			if err != nil && !trace.IsAlreadyExists(err) {
+				log.WithError(err).Warnf("unable to auto-create local Windows user %q on %v", login, desktop.GetAddr())
+			}
+			createdDesktopUser = created
+		}
+
+		return nil
 	}
 
 	// Use a context that is canceled when we're done handling
@@ -867,6 +891,12 @@ func (s *WindowsService) connectRDP(ctx context.Context, log logrus.FieldLogger,
 	err = rdpc.Wait()
 	s.onSessionEnd(ctx, sw, &identity, sessionStartTime, recordSession, windowsUser, string(sessionID), desktop)
 
+	if createdDesktopUser {
+		if err := s.cfg.HostUsers.DeleteUser(windowsUser); err != nil {
+			log.WithError(err).Warnf("unable to clean up auto-created local Windows user %q on %v", windowsUser, desktop.GetAddr())
+		}
+	}
+
 	return trace.Wrap(err)
 }
 
@@ -1073,18 +1103,21 @@ func (s *WindowsService) updateCA(ctx context.Context) error {
 // private key archival.
 //
 // This function is equivalent to running:
-//     certutil –dspublish –f <PathToCertFile.cer> NTAuthCA
+//
+//	certutil –dspublish –f <PathToCertFile.cer> NTAuthCA
 //
 // You can confirm the cert is present by running:
-//     certutil -viewstore "ldap:///CN=NTAuthCertificates,CN=Public Key Services,CN=Services,CN=Configuration,DC=example,DC=com>?caCertificate"
+//
+//	certutil -viewstore "ldap:///CN=NTAuthCertificates,CN=Public Key Services,CN=Services,CN=Configuration,DC=example,DC=com>?caCertificate"
 //
 // Once the CA is published to LDAP, it should eventually sync and be present in the
 // machine's enterprise NTAuth store. You can check that with:
-//     certutil -viewstore -enterprise NTAuth
+//
+//	certutil -viewstore -enterprise NTAuth
 //
 // You can expedite the synchronization by running:
-//     certutil -pulse
 //
+//	certutil -pulse
 func (s *WindowsService) updateCAInNTAuthStore(ctx context.Context, caDER []byte) error {
 	// Check if our CA is already in the store. The LDAP entry for NTAuth store
 	// is constant and it should always exist.
@@ -49,6 +49,7 @@ import (
 	"github.com/gravitational/teleport/lib/pam"
 	restricted "github.com/gravitational/teleport/lib/restrictedsession"
 	"github.com/gravitational/teleport/lib/services"
+	"github.com/gravitational/teleport/lib/srv/hostusers"
 	rsession "github.com/gravitational/teleport/lib/session"
 	"github.com/gravitational/teleport/lib/sshutils"
 	"github.com/gravitational/teleport/lib/utils"
@@ -220,6 +221,37 @@ func (s *ExecSuite) TestOSCommandPrep(c *check.C) {
 	c.Assert(cmd.Env, check.DeepEquals, expectedEnv)
 }
 
+// TestExecCommandChrootDir verifies that ExecCommand populates ChrootDir
+// when the session's login is a host user this node manages under a chroot,
+// and leaves it empty otherwise.
+func (s *ExecSuite) TestExecCommandChrootDir(c *check.C) {
+	fakeServer, ok := s.ctx.srv.(*fakeServer)
+	c.Assert(ok, check.Equals, true)
+
+	backend := hostusers.NewFakeHostUsersBackend()
+	hostUsersMgmt := hostusers.NewHostUsersManagementForTesting(backend, nil)
+	u, err := types.NewStaticHostUser("jailed", types.StaticHostUserSpecV1{
+		Login:     "jailed",
+		ChrootDir: "/srv/jails/jailed",
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(hostUsersMgmt.ReconcileStaticHostUser(u), check.IsNil)
+	fakeServer.hostUsers = hostUsersMgmt
+
+	origLogin := s.ctx.Identity.Login
+	defer func() { s.ctx.Identity.Login = origLogin }()
+
+	s.ctx.Identity.Login = "jailed"
+	execCmd, err := s.ctx.ExecCommand()
+	c.Assert(err, check.IsNil)
+	c.Assert(execCmd.ChrootDir, check.Equals, "/srv/jails/jailed")
+
+	s.ctx.Identity.Login = "someone-else"
+	execCmd, err = s.ctx.ExecCommand()
+	c.Assert(err, check.IsNil)
+	c.Assert(execCmd.ChrootDir, check.Equals, "")
+}
+
 func (s *ExecSuite) TestLoginDefsParser(c *check.C) {
 	expectedEnvSuPath := "PATH=/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin:/bar"
 	expectedSuPath := "PATH=/usr/local/bin:/usr/bin:/bin:/foo"
@@ -437,6 +469,7 @@ type fakeServer struct {
 	eventstest.MockEmitter
 	accessPoint AccessPoint
 	id          string
+	hostUsers   *hostusers.HostUsersManagement
 }
 
 func (f *fakeServer) Context() context.Context {
@@ -530,3 +563,7 @@ func (f *fakeServer) GetRestrictedSessionManager() restricted.Manager {
 func (f *fakeServer) GetLockWatcher() *services.LockWatcher {
 	return nil
 }
+
+func (f *fakeServer) GetHostUsers() *hostusers.HostUsersManagement {
+	return f.hostUsers
+}
@@ -37,6 +37,7 @@ import (
 	"github.com/gravitational/teleport/lib/pam"
 	restricted "github.com/gravitational/teleport/lib/restrictedsession"
 	"github.com/gravitational/teleport/lib/services"
+	"github.com/gravitational/teleport/lib/srv/hostusers"
 	"github.com/gravitational/teleport/lib/session"
 	"github.com/gravitational/teleport/lib/srv"
 	"github.com/gravitational/teleport/lib/sshutils"
@@ -446,6 +447,13 @@ func (s *Server) GetLockWatcher() *services.LockWatcher {
 	return s.lockWatcher
 }
 
+// GetHostUsers returns nil: the forwarding server forwards sessions to
+// another node rather than executing them locally, so it never provisions
+// host users itself.
+func (s *Server) GetHostUsers() *hostusers.HostUsersManagement {
+	return nil
+}
+
 func (s *Server) Serve() {
 	config := &ssh.ServerConfig{}
 
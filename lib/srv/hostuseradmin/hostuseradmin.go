@@ -0,0 +1,88 @@
+/*
+Copyright 2022 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package hostuseradmin implements the server side of the
+// HostUserAdminService gRPC API defined in
+// api/proto/v1/hostuseradmin.proto, so that orchestration tools can list,
+// create and delete this node's Teleport-managed host users without
+// shelling into the box.
+package hostuseradmin
+
+import (
+	"context"
+
+	"github.com/gravitational/trace"
+	"google.golang.org/grpc"
+
+	hostuseradminv1 "github.com/gravitational/teleport/lib/srv/hostuseradmin/api/protogen/golang/v1"
+	"github.com/gravitational/teleport/lib/srv/hostusers"
+)
+
+// Server implements the HostUserAdminServiceServer RPC handlers by wrapping
+// a node's *hostusers.HostUsersManagement.
+type Server struct {
+	hostuseradminv1.UnimplementedHostUserAdminServiceServer
+
+	hostUsers *hostusers.HostUsersManagement
+}
+
+// NewServer returns a Server that serves the HostUserAdminService API on
+// behalf of hostUsers.
+func NewServer(hostUsers *hostusers.HostUsersManagement) *Server {
+	return &Server{hostUsers: hostUsers}
+}
+
+// Register registers s as the HostUserAdminService implementation on
+// grpcServer.
+func (s *Server) Register(grpcServer *grpc.Server) {
+	hostuseradminv1.RegisterHostUserAdminServiceServer(grpcServer, s)
+}
+
+// ListHostUsers implements the ListHostUsers RPC.
+func (s *Server) ListHostUsers(ctx context.Context, req *hostuseradminv1.ListHostUsersRequest) (*hostuseradminv1.ListHostUsersResponse, error) {
+	managed := s.hostUsers.ListManagedHostUsers()
+	users := make([]*hostuseradminv1.HostUser, 0, len(managed))
+	for _, u := range managed {
+		users = append(users, &hostuseradminv1.HostUser{
+			Login:  u.Login,
+			Groups: u.Groups,
+			Uid:    u.UID,
+		})
+	}
+	return &hostuseradminv1.ListHostUsersResponse{Users: users}, nil
+}
+
+// CreateHostUser implements the CreateHostUser RPC.
+func (s *Server) CreateHostUser(ctx context.Context, req *hostuseradminv1.CreateHostUserRequest) (*hostuseradminv1.HostUser, error) {
+	if req.Login == "" {
+		return nil, trace.BadParameter("login is required")
+	}
+	if err := s.hostUsers.CreateManagedHostUser(req.Login, req.Groups); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &hostuseradminv1.HostUser{Login: req.Login, Groups: req.Groups}, nil
+}
+
+// DeleteHostUser implements the DeleteHostUser RPC.
+func (s *Server) DeleteHostUser(ctx context.Context, req *hostuseradminv1.DeleteHostUserRequest) (*hostuseradminv1.DeleteHostUserResponse, error) {
+	if req.Login == "" {
+		return nil, trace.BadParameter("login is required")
+	}
+	if err := s.hostUsers.DeleteManagedHostUser(req.Login); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &hostuseradminv1.DeleteHostUserResponse{}, nil
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2022 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostuseradmin
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	hostuseradminv1 "github.com/gravitational/teleport/lib/srv/hostuseradmin/api/protogen/golang/v1"
+	"github.com/gravitational/teleport/lib/srv/hostusers"
+	"github.com/gravitational/teleport/lib/utils"
+)
+
+func newTestClient(t *testing.T, hostUsers *hostusers.HostUsersManagement) hostuseradminv1.HostUserAdminServiceClient {
+	lis := bufconn.Listen(1024)
+	grpcServer := grpc.NewServer(
+		grpc.UnaryInterceptor(utils.ErrorConvertUnaryInterceptor),
+		grpc.StreamInterceptor(utils.ErrorConvertStreamInterceptor),
+	)
+	NewServer(hostUsers).Register(grpcServer)
+	go grpcServer.Serve(lis)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.DialContext(
+		context.Background(),
+		"bufconn",
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}))
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, conn.Close()) })
+	return hostuseradminv1.NewHostUserAdminServiceClient(conn)
+}
+
+func TestHostUserAdminServiceCreateListDelete(t *testing.T) {
+	backend := hostusers.NewFakeHostUsersBackend()
+	hostUsers := hostusers.NewHostUsersManagementForTesting(backend, nil)
+	client := newTestClient(t, hostUsers)
+	ctx := context.Background()
+
+	_, err := client.CreateHostUser(ctx, &hostuseradminv1.CreateHostUserRequest{Login: "mallory", Groups: []string{"docker"}})
+	require.NoError(t, err)
+
+	listResp, err := client.ListHostUsers(ctx, &hostuseradminv1.ListHostUsersRequest{})
+	require.NoError(t, err)
+	require.Len(t, listResp.Users, 1)
+	require.Equal(t, "mallory", listResp.Users[0].Login)
+	require.Equal(t, []string{"docker"}, listResp.Users[0].Groups)
+
+	_, err = client.DeleteHostUser(ctx, &hostuseradminv1.DeleteHostUserRequest{Login: "mallory"})
+	require.NoError(t, err)
+
+	listResp, err = client.ListHostUsers(ctx, &hostuseradminv1.ListHostUsersRequest{})
+	require.NoError(t, err)
+	require.Empty(t, listResp.Users)
+}
+
+func TestHostUserAdminServiceDeleteUnmanagedReturnsNotFound(t *testing.T) {
+	backend := hostusers.NewFakeHostUsersBackend()
+	hostUsers := hostusers.NewHostUsersManagementForTesting(backend, nil)
+	client := newTestClient(t, hostUsers)
+
+	_, err := client.DeleteHostUser(context.Background(), &hostuseradminv1.DeleteHostUserRequest{Login: "ghost"})
+	require.Error(t, err)
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
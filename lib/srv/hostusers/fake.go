@@ -0,0 +1,248 @@
+/*
+Copyright 2022 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostusers
+
+import (
+	"os/user"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gravitational/trace"
+
+	"github.com/gravitational/teleport/api/types"
+)
+
+// fakeUser is the state FakeHostUsersBackend tracks for a single local
+// account, mirroring the fields of *os/user.User that HostUsersManagement
+// reads back via Lookup.
+type fakeUser struct {
+	uid         string
+	gid         string
+	groups      []string
+	homeDir     string
+	homeDirMode string
+	system      bool
+}
+
+// FakeHostUsersBackend is an in-memory HostUsersBackend for exercising
+// HostUsersManagement without shelling out to useradd/usermod. It's exported
+// so that callers outside this package (Discover install scripts, install
+// helpers, and tests of code built on HostUsersManagement) can test their
+// own logic against realistic account lifecycle behavior.
+type FakeHostUsersBackend struct {
+	mu sync.Mutex
+	// NextUID is the UID assigned to the next user CreateUser provisions. It
+	// is incremented after each creation.
+	NextUID int
+	users   map[string]*fakeUser
+	// Sudoers maps a login to the sudoers entries most recently written for
+	// it via WriteSudoersFile.
+	Sudoers map[string][]string
+	// Lingering is the set of logins SetupUserEnvironment has enabled
+	// lingering for and TeardownUserEnvironment hasn't yet disabled.
+	Lingering map[string]bool
+	// Groups maps a group name to its GID, for LookupGroup. Unlike real
+	// useradd, CreateUser doesn't model groups as entities with their own
+	// GIDs, so this isn't populated automatically; tests that exercise
+	// LookupGroup should populate it directly.
+	Groups map[string]string
+	// MOTD maps a login to the login banner most recently written for it via
+	// WriteMOTD. A login with no entry hasn't had one written, or has had it
+	// removed by RemoveMOTD.
+	MOTD map[string]string
+	// LockedPasswords is the set of logins LockPassword has been called for.
+	LockedPasswords map[string]bool
+	// MaxPasswordAge maps a login to the most recent value SetMaxPasswordAge
+	// was called with for it.
+	MaxPasswordAge map[string]int
+}
+
+// NewFakeHostUsersBackend returns an empty FakeHostUsersBackend, assigning
+// UIDs starting at 10000 so they don't collide with typical system ranges.
+func NewFakeHostUsersBackend() *FakeHostUsersBackend {
+	return &FakeHostUsersBackend{
+		NextUID:         10000,
+		users:           make(map[string]*fakeUser),
+		Sudoers:         make(map[string][]string),
+		Lingering:       make(map[string]bool),
+		Groups:          make(map[string]string),
+		MOTD:            make(map[string]string),
+		LockedPasswords: make(map[string]bool),
+		MaxPasswordAge:  make(map[string]int),
+	}
+}
+
+func (f *FakeHostUsersBackend) Lookup(login string) (*user.User, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	u, ok := f.users[login]
+	if !ok {
+		return nil, user.UnknownUserError(login)
+	}
+	return &user.User{
+		Username: login,
+		Uid:      u.uid,
+		Gid:      u.gid,
+		HomeDir:  u.homeDir,
+	}, nil
+}
+
+func (f *FakeHostUsersBackend) LookupGroup(name string) (*user.Group, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	gid, ok := f.Groups[name]
+	if !ok {
+		return nil, user.UnknownGroupError(name)
+	}
+	return &user.Group{Name: name, Gid: gid}, nil
+}
+
+func (f *FakeHostUsersBackend) CreateUser(login string, groups []string, homeDirMode string, system bool, chrootDir string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.users[login]; ok {
+		return trace.AlreadyExists("host user %q already exists", login)
+	}
+	uid := f.NextUID
+	f.NextUID++
+	homeDir := "/home/" + login
+	if chrootDir != "" {
+		homeDir = chrootDir
+	}
+	f.users[login] = &fakeUser{
+		uid:         strconv.Itoa(uid),
+		gid:         strconv.Itoa(uid),
+		groups:      append([]string(nil), groups...),
+		homeDir:     homeDir,
+		homeDirMode: homeDirMode,
+		system:      system,
+	}
+	return nil
+}
+
+func (f *FakeHostUsersBackend) SetUserGroups(login string, groups []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	u, ok := f.users[login]
+	if !ok {
+		return user.UnknownUserError(login)
+	}
+	u.groups = append([]string(nil), groups...)
+	return nil
+}
+
+func (f *FakeHostUsersBackend) WriteSudoersFile(login string, sudoers []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(sudoers) == 0 {
+		delete(f.Sudoers, login)
+		return nil
+	}
+	f.Sudoers[login] = append([]string(nil), sudoers...)
+	return nil
+}
+
+func (f *FakeHostUsersBackend) RemoveUser(login string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.users[login]; !ok {
+		return trace.NotFound("host user %q does not exist", login)
+	}
+	delete(f.users, login)
+	delete(f.Sudoers, login)
+	delete(f.Lingering, login)
+	delete(f.LockedPasswords, login)
+	delete(f.MaxPasswordAge, login)
+	return nil
+}
+
+func (f *FakeHostUsersBackend) LockPassword(login string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.users[login]; !ok {
+		return user.UnknownUserError(login)
+	}
+	f.LockedPasswords[login] = true
+	return nil
+}
+
+func (f *FakeHostUsersBackend) SetMaxPasswordAge(login string, days int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.users[login]; !ok {
+		return user.UnknownUserError(login)
+	}
+	f.MaxPasswordAge[login] = days
+	return nil
+}
+
+func (f *FakeHostUsersBackend) AssignCgroupSlice(uid, cpuQuota, memoryMax string) error {
+	return nil
+}
+
+func (f *FakeHostUsersBackend) RemoveCgroupSlice(uid string) error {
+	return nil
+}
+
+func (f *FakeHostUsersBackend) SetupUserEnvironment(login string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.users[login]; !ok {
+		return user.UnknownUserError(login)
+	}
+	f.Lingering[login] = true
+	return nil
+}
+
+func (f *FakeHostUsersBackend) TeardownUserEnvironment(login string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.Lingering, login)
+	return nil
+}
+
+func (f *FakeHostUsersBackend) WriteMOTD(login, message string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.MOTD[login] = message
+	return nil
+}
+
+func (f *FakeHostUsersBackend) RemoveMOTD(login string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.MOTD, login)
+	return nil
+}
+
+// NewHostUsersManagementForTesting returns a HostUsersManagement backed by
+// backend with a capable preflight result, bypassing the real
+// useradd/usermod-backed NewHostUsersManagement constructor so tests outside
+// this package don't shell out. allowedHostGroups is this node's
+// allowed_host_groups policy, as in NewHostUsersManagement.
+func NewHostUsersManagementForTesting(backend HostUsersBackend, allowedHostGroups []string) *HostUsersManagement {
+	return &HostUsersManagement{
+		backend:           backend,
+		loginDefaults:     readLoginDefaults(),
+		allowedHostGroups: allowedHostGroups,
+		capability:        types.HostUserProvisioningCapability{Capable: true},
+		pendingGC:         make(map[string]time.Time),
+		managed:           make(map[string]types.StaticHostUser),
+		sessions:          make(map[string]int),
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2022 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostusers
+
+import (
+	"errors"
+	"os/exec"
+	"os/user"
+	"strings"
+
+	"github.com/gravitational/trace"
+	log "github.com/sirupsen/logrus"
+)
+
+// getentNotFoundExitCode is the exit status getent uses to report that the
+// requested key doesn't exist in the database it consulted, as opposed to
+// getent itself being unusable (missing binary, bad arguments, database
+// that doesn't support enumeration, etc).
+const getentNotFoundExitCode = 2
+
+// lookupViaGetent resolves login to a *user.User via `getent passwd`,
+// falling back to os/user.Lookup if the getent binary can't be run at all.
+//
+// Go's os/user only consults NSS (and therefore nsswitch.conf sources like
+// ldap or sss) when built with cgo; a binary built with CGO_ENABLED=0, or
+// cross-compiled, silently falls back to reading /etc/passwd directly and
+// will never find a directory-provided account, even on a host where
+// checkNSSConfiguredForFiles and every other tool agree NSS is configured
+// correctly. getent always goes through NSS, so shelling out to it avoids
+// that discrepancy regardless of how this binary happened to be built.
+func (b *hostUsersBackend) lookupViaGetent(login string) (*user.User, error) {
+	out, err := exec.Command(b.tool("getent"), "passwd", login).Output()
+	switch {
+	case err == nil:
+		return parseGetentPasswd(string(out))
+	case isGetentNotFound(err):
+		return nil, user.UnknownUserError(login)
+	default:
+		log.WithError(err).Debugf("getent passwd lookup for %q failed, falling back to os/user.Lookup.", login)
+		return user.Lookup(login)
+	}
+}
+
+// lookupGroupViaGetent resolves name to a *user.Group via `getent group`,
+// falling back to os/user.LookupGroup if the getent binary can't be run at
+// all. See lookupViaGetent for why getent is preferred.
+func (b *hostUsersBackend) lookupGroupViaGetent(name string) (*user.Group, error) {
+	out, err := exec.Command(b.tool("getent"), "group", name).Output()
+	switch {
+	case err == nil:
+		return parseGetentGroup(string(out))
+	case isGetentNotFound(err):
+		return nil, user.UnknownGroupError(name)
+	default:
+		log.WithError(err).Debugf("getent group lookup for %q failed, falling back to os/user.LookupGroup.", name)
+		return user.LookupGroup(name)
+	}
+}
+
+// isGetentNotFound reports whether err is the exit status getent uses when
+// the requested entry doesn't exist, rather than getent itself being
+// unusable.
+func isGetentNotFound(err error) bool {
+	var exitErr *exec.ExitError
+	return errors.As(err, &exitErr) && exitErr.ExitCode() == getentNotFoundExitCode
+}
+
+// parseGetentPasswd parses a single `getent passwd` line:
+// name:password:uid:gid:gecos:home:shell.
+func parseGetentPasswd(line string) (*user.User, error) {
+	fields := strings.Split(strings.TrimSpace(line), ":")
+	if len(fields) < 7 {
+		return nil, trace.BadParameter("unexpected getent passwd output: %q", line)
+	}
+	return &user.User{
+		Username: fields[0],
+		Uid:      fields[2],
+		Gid:      fields[3],
+		Name:     fields[4],
+		HomeDir:  fields[5],
+	}, nil
+}
+
+// parseGetentGroup parses a single `getent group` line: name:password:gid:members.
+func parseGetentGroup(line string) (*user.Group, error) {
+	fields := strings.Split(strings.TrimSpace(line), ":")
+	if len(fields) < 3 {
+		return nil, trace.BadParameter("unexpected getent group output: %q", line)
+	}
+	return &user.Group{
+		Name: fields[0],
+		Gid:  fields[2],
+	}, nil
+}
@@ -0,0 +1,964 @@
+/*
+Copyright 2022 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package hostusers creates and updates local OS user accounts on behalf of
+// Teleport, reconciling them against StaticHostUser resources. It was split
+// out of lib/srv so that components other than the SSH server — Discover
+// install scripts, local install helpers, and external tools built against
+// this module — can reuse the same account provisioning and preflight logic
+// without importing the whole server package. A FakeHostUsersBackend is
+// provided so those callers can exercise reconciliation logic in tests
+// without shelling out to useradd/usermod.
+package hostusers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gravitational/trace"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/gravitational/teleport/api/types"
+	"github.com/gravitational/teleport/lib/services"
+)
+
+// HostUserReporter reports host user records to the auth server. It is
+// satisfied by auth.ClientI.
+type HostUserReporter interface {
+	UpsertHostUserRecord(ctx context.Context, record types.HostUserRecord) error
+}
+
+// HostUserRemovalChecker fetches this node's reported host users so that
+// ones marked for removal can be cleaned up locally. It is satisfied by
+// auth.ClientI.
+type HostUserRemovalChecker interface {
+	GetHostUserRecords(ctx context.Context, hostID string) ([]types.HostUserRecord, error)
+	DeleteHostUserRecord(ctx context.Context, hostID, login string) error
+}
+
+// HostUsersBackend provides the OS primitives a HostUserManagement needs in
+// order to create and update local user accounts. It exists so that the
+// reconciliation logic below can be exercised without shelling out.
+type HostUsersBackend interface {
+	// Lookup looks up an existing local user by login.
+	Lookup(login string) (*user.User, error)
+	// LookupGroup looks up an existing local group by name.
+	LookupGroup(name string) (*user.Group, error)
+	// CreateUser creates a local user account belonging to the given groups.
+	// homeDirMode is an octal permission mode, e.g. "0700", applied to the
+	// new home directory, or "" to use the node's default. If system is
+	// true, the account is created as a system account (useradd -r).
+	// chrootDir, if non-empty, is created if necessary and used as the
+	// login's home directory, confining it as the root of a chroot jail.
+	CreateUser(login string, groups []string, homeDirMode string, system bool, chrootDir string) error
+	// SetUserGroups sets the supplementary groups of an existing local user.
+	SetUserGroups(login string, groups []string) error
+	// WriteSudoersFile writes the sudoers entries for login, replacing any
+	// previously written entries.
+	WriteSudoersFile(login string, sudoers []string) error
+	// RemoveUser removes a local user account.
+	RemoveUser(login string) error
+	// LockPassword locks the local user's password, preventing it from
+	// being used for authentication even if one is set out-of-band.
+	LockPassword(login string) error
+	// SetMaxPasswordAge sets the maximum number of days the local user's
+	// password may be used before it must be changed.
+	SetMaxPasswordAge(login string, days int) error
+	// AssignCgroupSlice configures the systemd user slice for the given UID
+	// with the given CPUQuota/MemoryMax (e.g. "20%"/"512M"), either of which
+	// may be "" to leave that limit unset. The slice itself is created by
+	// systemd-logind on first login; this only pre-configures its limits.
+	AssignCgroupSlice(uid, cpuQuota, memoryMax string) error
+	// RemoveCgroupSlice removes any systemd user slice configuration
+	// previously written by AssignCgroupSlice for the given UID.
+	RemoveCgroupSlice(uid string) error
+	// SetupUserEnvironment creates a correctly owned ~/.ssh directory for
+	// login and enables systemd-logind lingering for it, so XDG_RUNTIME_DIR
+	// (and anything that depends on it, e.g. systemd --user, ssh-agent) is
+	// available without a prior interactive login creating it first.
+	SetupUserEnvironment(login string) error
+	// TeardownUserEnvironment disables the lingering enabled by
+	// SetupUserEnvironment for login.
+	TeardownUserEnvironment(login string) error
+	// WriteMOTD writes a login banner for login stating message, shown by
+	// pam_motd the next time the account is used interactively.
+	WriteMOTD(login, message string) error
+	// RemoveMOTD removes the login banner previously written by WriteMOTD
+	// for login, if any.
+	RemoveMOTD(login string) error
+}
+
+// HostUsersManagement creates and updates local OS users based on
+// StaticHostUser resources that target this node.
+type HostUsersManagement struct {
+	backend       HostUsersBackend
+	loginDefaults loginDefaults
+
+	// allowedHostGroups is this node's explicit allow-list of host groups
+	// that roles are permitted to grant via the host_groups role option. A
+	// nil or empty list means no role-requested groups are applied.
+	allowedHostGroups []string
+
+	// capability is the result of this node's startup provisioning
+	// preflight, checked before any host user is created or reconciled.
+	capability types.HostUserProvisioningCapability
+
+	// maxSessionsPerUser is the maximum number of concurrent sessions this
+	// node allows for a single provisioned host user login, or 0 for no
+	// limit. It exists so a node can bound resource usage of an ephemeral
+	// account even when many sessions are requested for the same login in
+	// quick succession.
+	maxSessionsPerUser int
+
+	// usernameTemplate, if set, is applied to the login of every account
+	// ReconcileStaticHostUser provisions (including CreateManagedHostUser's)
+	// before it's created, e.g. "tp-{{username}}" turns a request for "alice"
+	// into the local account "tp-alice". CreateTemporaryUser doesn't apply
+	// it: per-session temporary accounts keep the requested login. This keeps Teleport-managed
+	// accounts visually distinguishable from permanent ones and lets nodes
+	// satisfy local username policies the raw Teleport username wouldn't.
+	// The original requested login is preserved in HostUserRecord.RequestedLogin
+	// for inventory and audit visibility.
+	usernameTemplate string
+
+	// ephemeralUserMOTD, if true, writes a login banner on every temporary
+	// host user CreateTemporaryUser provisions, telling whoever logs into it
+	// that the account is temporary and will be removed when the session
+	// ends. StaticHostUser accounts aren't temporary in this sense, so this
+	// doesn't apply to ReconcileStaticHostUser.
+	ephemeralUserMOTD bool
+
+	// gcPolicy governs how long a temporary host user may remain after its
+	// last session ends; nil means the historical behavior of removing it
+	// immediately. Set via SetHostUserGCPolicy.
+	gcPolicy types.HostUserGCPolicy
+
+	mu sync.Mutex
+	// pendingGC maps a temporary host user's login to when its last session
+	// ended, for logins whose removal gcPolicy has deferred; ApplyHostUserGC
+	// consults this to act on them once their grace period elapses.
+	pendingGC map[string]time.Time
+	managed   map[string]types.StaticHostUser
+	sessions  map[string]int
+}
+
+// NewHostUsersManagement creates a HostUsersManagement that manages local
+// OS accounts via useradd/usermod, honoring this node's /etc/login.defs and
+// `useradd -D` defaults. allowedHostGroups is this node's allow-list of
+// groups roles are permitted to grant via the host_groups role option.
+// toolPaths overrides the paths of the useradd/usermod/userdel/passwd/chage
+// utilities, keyed by tool name, for distros that don't install them under
+// a directory covered by the agent's inherited PATH; a tool not present in
+// the map is located via PATH as usual. maxSessionsPerUser caps the number
+// of concurrent sessions AcquireSession allows for a single login, or 0 for
+// no limit. usernameTemplate, if non-empty, is applied via ProvisionedLogin
+// to the login of every StaticHostUser account before it's provisioned
+// (temporary accounts from CreateTemporaryUser keep the requested login); it
+// must contain the
+// literal placeholder "{{username}}", or every account will be provisioned
+// under the same fixed name. If verifyShadowIntegrity is true, pwck/grpck
+// are run in report-only mode before every account creation or removal,
+// and the operation is refused if either finds the local passwd/group/
+// shadow databases inconsistent; leave it false on distributions that don't
+// ship pwck/grpck. If ephemeralUserMOTD is true, every temporary host user
+// CreateTemporaryUser provisions is given a login banner explaining that the
+// account is temporary and naming when it was created. A capability
+// preflight is run immediately so that provisioning failures are known
+// before any session tries to create a host user.
+func NewHostUsersManagement(allowedHostGroups []string, toolPaths map[string]string, maxSessionsPerUser int, usernameTemplate string, verifyShadowIntegrity, ephemeralUserMOTD bool) *HostUsersManagement {
+	return &HostUsersManagement{
+		backend:            &hostUsersBackend{toolPaths: toolPaths, verifyShadowIntegrity: verifyShadowIntegrity},
+		loginDefaults:      readLoginDefaults(),
+		allowedHostGroups:  allowedHostGroups,
+		capability:         PreflightHostUserProvisioning(toolPaths),
+		maxSessionsPerUser: maxSessionsPerUser,
+		usernameTemplate:   usernameTemplate,
+		ephemeralUserMOTD:  ephemeralUserMOTD,
+		pendingGC:          make(map[string]time.Time),
+		managed:            make(map[string]types.StaticHostUser),
+		sessions:           make(map[string]int),
+	}
+}
+
+// Capability returns the result of this node's startup provisioning
+// preflight, suitable for reporting in the node's heartbeat.
+func (m *HostUsersManagement) Capability() types.HostUserProvisioningCapability {
+	return m.capability
+}
+
+// FilterAllowedHostGroups intersects requested, the host groups a role has
+// asked to grant via the host_groups role option, with this node's
+// AllowedHostGroups policy, dropping and logging any group that isn't on
+// the allow-list. This keeps privileged groups such as wheel, sudo or
+// docker centrally governed and auditable on a per-node basis, rather than
+// letting any role grant them implicitly.
+func (m *HostUsersManagement) FilterAllowedHostGroups(requested []string) []string {
+	if len(m.allowedHostGroups) == 0 {
+		if len(requested) > 0 {
+			log.Warnf("Dropping requested host groups %v: node has no allowed_host_groups policy configured.", requested)
+		}
+		return nil
+	}
+	allowed := make(map[string]struct{}, len(m.allowedHostGroups))
+	for _, group := range m.allowedHostGroups {
+		allowed[group] = struct{}{}
+	}
+	var out []string
+	for _, group := range requested {
+		if _, ok := allowed[group]; ok {
+			out = append(out, group)
+			continue
+		}
+		log.Warnf("Dropping host group %q: not in this node's allowed_host_groups policy.", group)
+	}
+	return out
+}
+
+// ReconcileStaticHostUser ensures a single local OS user exists and matches
+// the login, groups and sudoers of the given StaticHostUser.
+func (m *HostUsersManagement) ReconcileStaticHostUser(u types.StaticHostUser) error {
+	if !m.capability.Capable {
+		return trace.BadParameter("cannot provision host users on this node: %s", m.capability.Error)
+	}
+	login := m.ProvisionedLogin(u.GetLogin())
+	groups := m.FilterAllowedHostGroups(u.GetGroups())
+	_, err := m.backend.Lookup(login)
+	switch {
+	case err == user.UnknownUserError(login):
+		homeDirMode := u.GetHomeDirMode()
+		if homeDirMode == "" {
+			homeDirMode = m.loginDefaults.homeMode
+		}
+		if err := m.backend.CreateUser(login, groups, homeDirMode, u.IsSystemAccount(), u.GetChrootDir()); err != nil {
+			return trace.Wrap(err, "creating host user %q", login)
+		}
+	case err != nil:
+		return trace.Wrap(err, "looking up host user %q", login)
+	default:
+		// The login already exists. This is the common steady-state case,
+		// but it's also how an account left behind by a crash mid-creation
+		// (useradd succeeded but a later step such as group assignment
+		// never ran) gets adopted: every step below is safe to re-apply, so
+		// running them unconditionally converges the account to the
+		// desired state instead of treating "exists" as "fully
+		// provisioned".
+		if err := m.backend.SetUserGroups(login, groups); err != nil {
+			return trace.Wrap(err, "updating groups for host user %q", login)
+		}
+	}
+	// Apply password aging policy explicitly, so a temporary account can
+	// never be given a usable password out-of-band without it being locked
+	// and flagged for expiry. Re-applying on every reconcile, not just at
+	// creation, repairs accounts a crash left partially provisioned.
+	// System accounts aren't subject to password aging.
+	if err := m.backend.LockPassword(login); err != nil {
+		return trace.Wrap(err, "locking password for host user %q", login)
+	}
+	if days := u.GetMaxPasswordAgeDays(); days > 0 && !u.IsSystemAccount() {
+		if err := m.backend.SetMaxPasswordAge(login, days); err != nil {
+			return trace.Wrap(err, "setting password aging policy for host user %q", login)
+		}
+	}
+	if cpuQuota, memoryMax := u.GetCPUQuota(), u.GetMemoryMax(); cpuQuota != "" || memoryMax != "" {
+		osUser, err := m.backend.Lookup(login)
+		if err != nil {
+			return trace.Wrap(err, "looking up host user %q", login)
+		}
+		if err := m.backend.AssignCgroupSlice(osUser.Uid, cpuQuota, memoryMax); err != nil {
+			return trace.Wrap(err, "assigning cgroup slice limits for host user %q", login)
+		}
+	}
+	if err := m.backend.SetupUserEnvironment(login); err != nil {
+		return trace.Wrap(err, "setting up environment for host user %q", login)
+	}
+	if err := m.backend.WriteSudoersFile(login, u.GetSudoers()); err != nil {
+		return trace.Wrap(err, "writing sudoers file for host user %q", login)
+	}
+	m.mu.Lock()
+	m.managed[login] = u
+	m.mu.Unlock()
+	return nil
+}
+
+// CreateTemporaryUser creates a local OS user outside of the normal
+// StaticHostUser reconciliation, for callers that provision a host user for
+// the lifetime of a single operation (for example session setup) rather
+// than managing it declaratively. If the login already exists, it is left
+// untouched and rollback is a no-op, since this method didn't create it.
+//
+// The returned rollback func ends the account's use as endEphemeralSession
+// describes: under the default HostUserGCPolicy it removes the user it just
+// created, so a caller that invokes it after a later setup step fails leaves
+// no half-provisioned account behind. Under a policy with a MaxAge, or with
+// the keep or archive action, the account stays in place for that policy.
+func (m *HostUsersManagement) CreateTemporaryUser(login string, groups []string) (rollback func(), err error) {
+	if !m.capability.Capable {
+		return nil, trace.BadParameter("cannot provision host users on this node: %s", m.capability.Error)
+	}
+	_, err = m.backend.Lookup(login)
+	switch {
+	case err == nil:
+		return func() {}, nil
+	case err != user.UnknownUserError(login):
+		return nil, trace.Wrap(err, "looking up host user %q", login)
+	}
+	if err := m.backend.CreateUser(login, groups, "", false, ""); err != nil {
+		return nil, trace.Wrap(err, "creating temporary host user %q", login)
+	}
+	if err := m.backend.SetupUserEnvironment(login); err != nil {
+		if rollbackErr := m.backend.RemoveUser(login); rollbackErr != nil && !trace.IsNotFound(rollbackErr) {
+			log.WithError(rollbackErr).Warnf("Failed to roll back temporary host user %q.", login)
+		}
+		return nil, trace.Wrap(err, "setting up environment for temporary host user %q", login)
+	}
+	if m.ephemeralUserMOTD {
+		if err := m.backend.WriteMOTD(login, temporaryUserMOTD(login)); err != nil {
+			log.WithError(err).Warnf("Failed to write login banner for temporary host user %q.", login)
+		}
+	}
+	return func() {
+		m.endEphemeralSession(login)
+	}, nil
+}
+
+// endEphemeralSession is called when the last session using a temporary
+// host user provisioned by CreateTemporaryUser ends. Per the configured
+// HostUserGCPolicy (see SetHostUserGCPolicy), it applies the policy's Action
+// right away (removing the account, the default and historical behavior;
+// locking its password for archive; leaving it alone for keep), or, if
+// MaxAge is set, defers that action until MaxAge has elapsed by handing the
+// login to ApplyHostUserGC.
+func (m *HostUsersManagement) endEphemeralSession(login string) {
+	policy := m.currentGCPolicy()
+	if policy.GetMaxAge() > 0 {
+		m.mu.Lock()
+		m.pendingGC[login] = time.Now()
+		m.mu.Unlock()
+		return
+	}
+	m.applyHostUserGCAction(login, policy)
+}
+
+// applyHostUserGCAction applies policy's Action to an ephemeral host user
+// whose GC grace period (if any) has elapsed.
+func (m *HostUsersManagement) applyHostUserGCAction(login string, policy types.HostUserGCPolicy) {
+	switch policy.GetAction() {
+	case types.HostUserGCActionKeep:
+		return
+	case types.HostUserGCActionArchive:
+		if err := m.backend.LockPassword(login); err != nil && !trace.IsNotFound(err) {
+			log.WithError(err).Warnf("Failed to lock password while archiving temporary host user %q.", login)
+		}
+		return
+	default: // types.HostUserGCActionDelete
+		if m.ephemeralUserMOTD {
+			if err := m.backend.RemoveMOTD(login); err != nil {
+				log.WithError(err).Warnf("Failed to remove login banner for temporary host user %q.", login)
+			}
+		}
+		if err := m.backend.TeardownUserEnvironment(login); err != nil {
+			log.WithError(err).Warnf("Failed to tear down environment for temporary host user %q.", login)
+		}
+		if err := m.backend.RemoveUser(login); err != nil && !trace.IsNotFound(err) {
+			log.WithError(err).Warnf("Failed to remove temporary host user %q.", login)
+		}
+	}
+}
+
+// ApplyHostUserGC applies the configured HostUserGCPolicy's Action to every
+// temporary host user whose last session ended at least MaxAge ago. Nodes
+// should call this periodically (for example alongside ApplyPendingRemovals)
+// so that accounts deferred by endEphemeralSession are eventually acted on.
+func (m *HostUsersManagement) ApplyHostUserGC() {
+	policy := m.currentGCPolicy()
+	maxAge := policy.GetMaxAge().Duration()
+
+	now := time.Now()
+	m.mu.Lock()
+	var due []string
+	for login, endedAt := range m.pendingGC {
+		if now.Sub(endedAt) >= maxAge {
+			due = append(due, login)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, login := range due {
+		m.applyHostUserGCAction(login, policy)
+		m.mu.Lock()
+		delete(m.pendingGC, login)
+		m.mu.Unlock()
+	}
+}
+
+// defaultHostUserGCPolicy is consulted when no HostUserGCPolicy has been set
+// via SetHostUserGCPolicy, preserving the historical behavior of removing a
+// temporary host user as soon as its last session ends.
+var defaultHostUserGCPolicy = func() types.HostUserGCPolicy {
+	policy, err := types.NewHostUserGCPolicy(types.HostUserGCPolicySpecV1{})
+	if err != nil {
+		panic(err)
+	}
+	return policy
+}()
+
+// currentGCPolicy returns the HostUserGCPolicy most recently set via
+// SetHostUserGCPolicy, or defaultHostUserGCPolicy if none has been set.
+func (m *HostUsersManagement) currentGCPolicy() types.HostUserGCPolicy {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.gcPolicy != nil {
+		return m.gcPolicy
+	}
+	return defaultHostUserGCPolicy
+}
+
+// SetHostUserGCPolicy updates the garbage collection policy this node
+// consults for how long a temporary host user provisioned by
+// CreateTemporaryUser may remain after its last session ends. Pass nil to
+// restore the default of removing it immediately. Typically called whenever
+// the cluster-level HostUserGCPolicy resource changes.
+func (m *HostUsersManagement) SetHostUserGCPolicy(policy types.HostUserGCPolicy) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.gcPolicy = policy
+}
+
+// temporaryUserMOTD returns the login banner text written for a temporary
+// host user provisioned by CreateTemporaryUser, telling whoever logs into
+// login that the account is temporary and naming when it was created.
+func temporaryUserMOTD(login string) string {
+	return fmt.Sprintf(
+		"*** %s is a temporary account, automatically created by Teleport for this session. ***\n"+
+			"It was provisioned on %s and will be removed once the session ends.\n",
+		login, time.Now().UTC().Format(time.RFC1123))
+}
+
+// AcquireSession reserves a concurrent-session slot for login, refusing with
+// a trace.LimitExceeded error once maxSessionsPerUser sessions for that
+// login are already outstanding. It exists for hosts where resource
+// isolation per ephemeral account matters, so a single provisioned user
+// can't be used to spawn an unbounded number of sessions on one node.
+//
+// The returned release func must be called exactly once, when the session
+// ends, to free the slot; callers typically defer it alongside the rollback
+// func returned by CreateTemporaryUser.
+func (m *HostUsersManagement) AcquireSession(login string) (release func(), err error) {
+	if m.maxSessionsPerUser <= 0 {
+		return func() {}, nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.sessions[login] >= m.maxSessionsPerUser {
+		return nil, trace.LimitExceeded(
+			"host user %q has reached its limit of %d concurrent session(s)", login, m.maxSessionsPerUser)
+	}
+	m.sessions[login]++
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.sessions[login]--
+		if m.sessions[login] <= 0 {
+			delete(m.sessions, login)
+		}
+	}, nil
+}
+
+// ReconcileStaticHostUsers applies every StaticHostUser whose NodeLabels
+// selector matches labels to the local host, logging and continuing past
+// any individual failures so that one bad resource can't block the rest.
+func (m *HostUsersManagement) ReconcileStaticHostUsers(users []types.StaticHostUser, labels map[string]string) {
+	for _, u := range users {
+		match, _, err := services.MatchLabels(u.GetNodeLabels(), labels)
+		if err != nil {
+			log.WithError(err).Warnf("Failed to match node labels against static host user %q.", u.GetName())
+			continue
+		}
+		if !match {
+			continue
+		}
+		if err := m.ReconcileStaticHostUser(u); err != nil {
+			log.WithError(err).Warnf("Failed to reconcile static host user %q.", u.GetName())
+		}
+	}
+}
+
+// ReportHostUserRecords reports every host user this node currently manages
+// to the auth server's inventory, so that Teleport-created accounts are
+// visible cluster-wide.
+func (m *HostUsersManagement) ReportHostUserRecords(ctx context.Context, reporter HostUserReporter, hostID string) {
+	m.mu.Lock()
+	managed := make(map[string]types.StaticHostUser, len(m.managed))
+	for login, u := range m.managed {
+		managed[login] = u
+	}
+	m.mu.Unlock()
+
+	for login, u := range managed {
+		uid := ""
+		if osUser, err := m.backend.Lookup(login); err == nil {
+			uid = osUser.Uid
+		}
+		record, err := types.NewHostUserRecord(hostID, types.HostUserRecordSpecV1{
+			HostID:         hostID,
+			Login:          login,
+			RequestedLogin: u.GetLogin(),
+			UID:            uid,
+			Groups:         m.FilterAllowedHostGroups(u.GetGroups()),
+			CreatedBy:      u.GetName(),
+		})
+		if err != nil {
+			log.WithError(err).Warnf("Failed to build host user record for %q.", login)
+			continue
+		}
+		if err := reporter.UpsertHostUserRecord(ctx, record); err != nil {
+			log.WithError(err).Warnf("Failed to report host user record for %q.", login)
+		}
+	}
+}
+
+// ApplyPendingRemovals fetches this node's reported host users from the
+// auth server and removes any local account whose record has been marked
+// for deletion, for example via `tctl hostusers rm`.
+func (m *HostUsersManagement) ApplyPendingRemovals(ctx context.Context, checker HostUserRemovalChecker, hostID string) {
+	records, err := checker.GetHostUserRecords(ctx, hostID)
+	if err != nil {
+		log.WithError(err).Warn("Failed to fetch host user records for pending removals.")
+		return
+	}
+	for _, record := range records {
+		if !record.IsDeletionRequested() {
+			continue
+		}
+		login := record.GetLogin()
+		osUser, lookupErr := m.backend.Lookup(login)
+		switch {
+		case lookupErr == nil:
+			if !m.loginDefaults.isManagedUID(osUser.Uid) {
+				log.Warnf("Refusing to remove host user %q: UID %s falls outside the managed range [%d-%d].",
+					login, osUser.Uid, m.loginDefaults.uidMin, m.loginDefaults.uidMax)
+				continue
+			}
+			if err := m.backend.RemoveCgroupSlice(osUser.Uid); err != nil {
+				log.WithError(err).Warnf("Failed to remove cgroup slice limits for host user %q.", login)
+			}
+			if err := m.backend.TeardownUserEnvironment(login); err != nil {
+				log.WithError(err).Warnf("Failed to tear down environment for host user %q.", login)
+			}
+			if err := m.backend.RemoveUser(login); err != nil && !trace.IsNotFound(err) {
+				log.WithError(err).Warnf("Failed to remove host user %q.", login)
+				continue
+			}
+		case lookupErr != user.UnknownUserError(login):
+			log.WithError(lookupErr).Warnf("Failed to look up host user %q before removal.", login)
+			continue
+		}
+		if err := checker.DeleteHostUserRecord(ctx, hostID, login); err != nil {
+			log.WithError(err).Warnf("Failed to delete host user record for %q.", login)
+			continue
+		}
+		m.mu.Lock()
+		delete(m.managed, login)
+		m.mu.Unlock()
+	}
+}
+
+// ManagedHostUser describes a single Teleport-managed local OS user account,
+// suitable for listing via an external admin interface.
+type ManagedHostUser struct {
+	// Login is the local OS username.
+	Login string
+	// Groups are the groups requested for Login by the StaticHostUser that
+	// created it. Groups this node's allowed host groups policy dropped are
+	// still listed.
+	Groups []string
+	// UID is the local OS user ID, or "" if it could not be looked up.
+	UID string
+	// RequestedLogin is the login as requested by the StaticHostUser that
+	// created this account, before any usernameTemplate substitution. It
+	// equals Login unless NewHostUsersManagement was given a template.
+	RequestedLogin string
+	// ChrootDir is the directory Login's sessions are confined to via
+	// chroot, or "" if the account isn't chrooted.
+	ChrootDir string
+}
+
+// ListManagedHostUsers returns every local OS user this node currently
+// manages via StaticHostUser reconciliation. It exists so that an external
+// admin interface (see lib/srv/hostuseradmin) can report on provisioned
+// accounts without shelling into the node.
+func (m *HostUsersManagement) ListManagedHostUsers() []ManagedHostUser {
+	m.mu.Lock()
+	managed := make(map[string]types.StaticHostUser, len(m.managed))
+	for login, u := range m.managed {
+		managed[login] = u
+	}
+	m.mu.Unlock()
+
+	out := make([]ManagedHostUser, 0, len(managed))
+	for login, u := range managed {
+		uid := ""
+		if osUser, err := m.backend.Lookup(login); err == nil {
+			uid = osUser.Uid
+		}
+		out = append(out, ManagedHostUser{
+			Login:          login,
+			Groups:         u.GetGroups(),
+			UID:            uid,
+			RequestedLogin: u.GetLogin(),
+			ChrootDir:      u.GetChrootDir(),
+		})
+	}
+	return out
+}
+
+// ChrootDirForLogin returns the chroot directory this node confines login's
+// sessions to, or "" if login isn't managed by this node or isn't chrooted.
+// It's used to populate ExecCommand.ChrootDir when a session is set up for a
+// Teleport-managed host user.
+func (m *HostUsersManagement) ChrootDirForLogin(login string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	u, ok := m.managed[login]
+	if !ok {
+		return ""
+	}
+	return u.GetChrootDir()
+}
+
+// ProvisionedLogin returns the local OS username to provision for a
+// requested login, applying this node's usernameTemplate if one is
+// configured. The template must contain the literal placeholder
+// "{{username}}", which is replaced with requested; if no template is
+// configured, requested is returned unchanged.
+func (m *HostUsersManagement) ProvisionedLogin(requested string) string {
+	if m.usernameTemplate == "" {
+		return requested
+	}
+	return strings.ReplaceAll(m.usernameTemplate, "{{username}}", requested)
+}
+
+// CreateManagedHostUser provisions a local OS user belonging to groups and
+// starts managing it, as if it had been reconciled from a StaticHostUser
+// resource with those groups and no other policy set. It exists so that an
+// external admin interface can create accounts without shelling into the
+// node.
+func (m *HostUsersManagement) CreateManagedHostUser(login string, groups []string) error {
+	u, err := types.NewStaticHostUser(login, types.StaticHostUserSpecV1{
+		Login:  login,
+		Groups: groups,
+	})
+	if err != nil {
+		return trace.Wrap(err, "building static host user for %q", login)
+	}
+	return trace.Wrap(m.ReconcileStaticHostUser(u))
+}
+
+// DeleteManagedHostUser removes a local OS user previously created by
+// ReconcileStaticHostUser or CreateManagedHostUser, tearing down its
+// environment and cgroup slice limits the same way ApplyPendingRemovals
+// does. It refuses to remove a login this node isn't managing, so an admin
+// API built on top of it can't be used to delete arbitrary local accounts.
+func (m *HostUsersManagement) DeleteManagedHostUser(login string) error {
+	m.mu.Lock()
+	_, managed := m.managed[login]
+	m.mu.Unlock()
+	if !managed {
+		return trace.NotFound("host user %q is not managed by this node", login)
+	}
+
+	osUser, err := m.backend.Lookup(login)
+	if err != nil {
+		return trace.Wrap(err, "looking up host user %q", login)
+	}
+	if err := m.backend.RemoveCgroupSlice(osUser.Uid); err != nil {
+		log.WithError(err).Warnf("Failed to remove cgroup slice limits for host user %q.", login)
+	}
+	if err := m.backend.TeardownUserEnvironment(login); err != nil {
+		log.WithError(err).Warnf("Failed to tear down environment for host user %q.", login)
+	}
+	if err := m.backend.RemoveUser(login); err != nil && !trace.IsNotFound(err) {
+		return trace.Wrap(err, "removing host user %q", login)
+	}
+
+	m.mu.Lock()
+	delete(m.managed, login)
+	m.mu.Unlock()
+	return nil
+}
+
+// hostUsersBackend is the Linux implementation of HostUsersBackend, backed
+// by the useradd/usermod system utilities.
+type hostUsersBackend struct {
+	// toolPaths overrides the path of a system utility, keyed by tool name
+	// (e.g. "useradd"). A tool not present in the map is located via PATH
+	// as usual.
+	toolPaths map[string]string
+	// verifyShadowIntegrity, if true, runs pwck/grpck in report-only mode
+	// before CreateUser and RemoveUser, refusing to proceed if either finds
+	// the passwd/group/shadow databases inconsistent. It exists so a
+	// destructive operation never runs against a user database already
+	// known to be corrupt, which could otherwise make the corruption worse
+	// or silently affect the wrong account.
+	verifyShadowIntegrity bool
+}
+
+// checkShadowFileIntegrity runs pwck/grpck in report-only (-r) mode and
+// returns a clear error if either finds the local passwd/group/shadow
+// databases inconsistent. It's a no-op unless verifyShadowIntegrity is set,
+// since pwck/grpck aren't installed on every distribution this backend
+// otherwise supports.
+func (b *hostUsersBackend) checkShadowFileIntegrity() error {
+	if !b.verifyShadowIntegrity {
+		return nil
+	}
+	for _, tool := range []string{"pwck", "grpck"} {
+		cmd := exec.Command(b.tool(tool), "-r")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return trace.Errorf("refusing to modify host users: %s -r reported the local user database is inconsistent: %s", tool, out)
+		}
+	}
+	return nil
+}
+
+// tool returns the path to use for the named system utility, honoring any
+// configured override.
+func (b *hostUsersBackend) tool(name string) string {
+	if path, ok := b.toolPaths[name]; ok && path != "" {
+		return path
+	}
+	return name
+}
+
+func (b *hostUsersBackend) Lookup(login string) (*user.User, error) {
+	return b.lookupViaGetent(login)
+}
+
+func (b *hostUsersBackend) LookupGroup(name string) (*user.Group, error) {
+	return b.lookupGroupViaGetent(name)
+}
+
+func (b *hostUsersBackend) CreateUser(login string, groups []string, homeDirMode string, system bool, chrootDir string) error {
+	if err := b.checkShadowFileIntegrity(); err != nil {
+		return err
+	}
+	if chrootDir != "" {
+		// chrootDir becomes the login's home directory, so the session
+		// later chroots into the same path its home is reported as; useradd
+		// creates it via -m below, but only once its parent exists.
+		if err := os.MkdirAll(filepath.Dir(chrootDir), 0755); err != nil {
+			return trace.Wrap(err, "creating parent of chroot directory %q", chrootDir)
+		}
+	}
+	args := []string{"-m"}
+	if len(groups) > 0 {
+		args = append(args, "-G", strings.Join(groups, ","))
+	}
+	if homeDirMode != "" {
+		args = append(args, "-K", "HOME_MODE="+homeDirMode)
+	}
+	if system {
+		args = append(args, "-r")
+	}
+	if chrootDir != "" {
+		args = append(args, "-d", chrootDir)
+	}
+	args = append(args, login)
+	cmd := exec.Command(b.tool("useradd"), args...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return trace.Wrap(err, "useradd failed: %s", out)
+	}
+	return nil
+}
+
+func (b *hostUsersBackend) SetUserGroups(login string, groups []string) error {
+	cmd := exec.Command(b.tool("usermod"), "-G", strings.Join(groups, ","), login)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return trace.Wrap(err, "usermod failed: %s", out)
+	}
+	return nil
+}
+
+// WriteSudoersFile writes no sudoers entries on this backend: it returns nil
+// when sudoers is empty and trace.NotImplemented otherwise, so reconciling a
+// StaticHostUser that lists sudoers entries fails here.
+func (*hostUsersBackend) WriteSudoersFile(login string, sudoers []string) error {
+	if len(sudoers) == 0 {
+		return nil
+	}
+	return trace.NotImplemented("writing sudoers entries for %q is not yet supported", login)
+}
+
+func (b *hostUsersBackend) RemoveUser(login string) error {
+	if err := b.checkShadowFileIntegrity(); err != nil {
+		return err
+	}
+	cmd := exec.Command(b.tool("userdel"), "-r", login)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if _, lookupErr := b.Lookup(login); lookupErr == user.UnknownUserError(login) {
+			return trace.NotFound("host user %q does not exist", login)
+		}
+		return trace.Wrap(err, "userdel failed: %s", out)
+	}
+	return nil
+}
+
+func (b *hostUsersBackend) LockPassword(login string) error {
+	cmd := exec.Command(b.tool("passwd"), "-l", login)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return trace.Wrap(err, "passwd -l failed: %s", out)
+	}
+	return nil
+}
+
+func (b *hostUsersBackend) SetMaxPasswordAge(login string, days int) error {
+	cmd := exec.Command(b.tool("chage"), "-M", strconv.Itoa(days), login)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return trace.Wrap(err, "chage -M failed: %s", out)
+	}
+	return nil
+}
+
+// cgroupSliceDropInDir returns the path of the systemd drop-in directory
+// that configures resource limits for uid's user slice. systemd-logind
+// creates user-<uid>.slice lazily on first login, so limits are applied via
+// a drop-in file rather than `systemctl set-property`, which requires the
+// unit to already exist.
+func cgroupSliceDropInDir(uid string) string {
+	return filepath.Join("/etc/systemd/system", fmt.Sprintf("user-%s.slice.d", uid))
+}
+
+func (b *hostUsersBackend) AssignCgroupSlice(uid, cpuQuota, memoryMax string) error {
+	dir := cgroupSliceDropInDir(uid)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return trace.Wrap(err, "creating slice drop-in directory %q", dir)
+	}
+
+	lines := []string{"[Slice]"}
+	if cpuQuota != "" {
+		lines = append(lines, "CPUQuota="+cpuQuota)
+	}
+	if memoryMax != "" {
+		lines = append(lines, "MemoryMax="+memoryMax)
+	}
+	content := strings.Join(lines, "\n") + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "teleport.conf"), []byte(content), 0644); err != nil {
+		return trace.Wrap(err, "writing slice drop-in file")
+	}
+
+	cmd := exec.Command(b.tool("systemctl"), "daemon-reload")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return trace.Wrap(err, "systemctl daemon-reload failed: %s", out)
+	}
+	return nil
+}
+
+func (b *hostUsersBackend) RemoveCgroupSlice(uid string) error {
+	dir := cgroupSliceDropInDir(uid)
+	if err := os.RemoveAll(dir); err != nil {
+		return trace.Wrap(err, "removing slice drop-in directory %q", dir)
+	}
+
+	cmd := exec.Command(b.tool("systemctl"), "daemon-reload")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return trace.Wrap(err, "systemctl daemon-reload failed: %s", out)
+	}
+	return nil
+}
+
+func (b *hostUsersBackend) SetupUserEnvironment(login string) error {
+	osUser, err := b.Lookup(login)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	uid, err := strconv.Atoi(osUser.Uid)
+	if err != nil {
+		return trace.Wrap(err, "parsing UID %q for host user %q", osUser.Uid, login)
+	}
+	gid, err := strconv.Atoi(osUser.Gid)
+	if err != nil {
+		return trace.Wrap(err, "parsing GID %q for host user %q", osUser.Gid, login)
+	}
+
+	sshDir := filepath.Join(osUser.HomeDir, ".ssh")
+	if err := os.MkdirAll(sshDir, 0700); err != nil {
+		return trace.Wrap(err, "creating %q", sshDir)
+	}
+	if err := os.Chown(sshDir, uid, gid); err != nil {
+		return trace.Wrap(err, "setting ownership of %q", sshDir)
+	}
+
+	// Lingering makes systemd-logind create and keep XDG_RUNTIME_DIR
+	// (/run/user/<uid>) for this user even without an active login session,
+	// so tooling that depends on it (systemd --user, ssh-agent) works from
+	// the very first session.
+	cmd := exec.Command(b.tool("loginctl"), "enable-linger", login)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return trace.Wrap(err, "loginctl enable-linger failed: %s", out)
+	}
+	return nil
+}
+
+func (b *hostUsersBackend) TeardownUserEnvironment(login string) error {
+	cmd := exec.Command(b.tool("loginctl"), "disable-linger", login)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return trace.Wrap(err, "loginctl disable-linger failed: %s", out)
+	}
+	return nil
+}
+
+// motdDropInDir is the directory pam_motd's dynamic motd support
+// (MOTD_DIR in /etc/pam.d/*, e.g. Debian/Ubuntu's libpam-modules) reads
+// login banner fragments from, in addition to the static /etc/motd.
+const motdDropInDir = "/etc/motd.d"
+
+func motdDropInPath(login string) string {
+	return filepath.Join(motdDropInDir, "teleport-"+login+".motd")
+}
+
+func (b *hostUsersBackend) WriteMOTD(login, message string) error {
+	if err := os.MkdirAll(motdDropInDir, 0755); err != nil {
+		return trace.Wrap(err, "creating %q", motdDropInDir)
+	}
+	if err := os.WriteFile(motdDropInPath(login), []byte(message), 0644); err != nil {
+		return trace.Wrap(err, "writing login banner for host user %q", login)
+	}
+	return nil
+}
+
+func (b *hostUsersBackend) RemoveMOTD(login string) error {
+	if err := os.Remove(motdDropInPath(login)); err != nil && !os.IsNotExist(err) {
+		return trace.Wrap(err, "removing login banner for host user %q", login)
+	}
+	return nil
+}
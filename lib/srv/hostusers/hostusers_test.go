@@ -0,0 +1,284 @@
+/*
+Copyright 2022 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostusers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gravitational/trace"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gravitational/teleport/api/types"
+)
+
+// newTestHostUsersManagement is a package-local alias for
+// NewHostUsersManagementForTesting, kept so in-package tests don't need the
+// package-qualified name.
+func newTestHostUsersManagement(backend HostUsersBackend, allowedHostGroups []string) *HostUsersManagement {
+	return NewHostUsersManagementForTesting(backend, allowedHostGroups)
+}
+
+func TestReconcileStaticHostUsersMatchesLabels(t *testing.T) {
+	backend := NewFakeHostUsersBackend()
+	m := newTestHostUsersManagement(backend, []string{"docker"})
+
+	matching, err := types.NewStaticHostUser("match", types.StaticHostUserSpecV1{
+		Login:      "match",
+		NodeLabels: types.Labels{"env": []string{"prod"}},
+	})
+	require.NoError(t, err)
+	nonMatching, err := types.NewStaticHostUser("nomatch", types.StaticHostUserSpecV1{
+		Login:      "nomatch",
+		NodeLabels: types.Labels{"env": []string{"staging"}},
+	})
+	require.NoError(t, err)
+
+	m.ReconcileStaticHostUsers([]types.StaticHostUser{matching, nonMatching}, map[string]string{"env": "prod"})
+
+	_, ok := backend.users["match"]
+	require.True(t, ok)
+	_, ok = backend.users["nomatch"]
+	require.False(t, ok)
+}
+
+func TestReconcileStaticHostUserFiltersGroups(t *testing.T) {
+	backend := NewFakeHostUsersBackend()
+	m := newTestHostUsersManagement(backend, []string{"docker"})
+
+	u, err := types.NewStaticHostUser("gwen", types.StaticHostUserSpecV1{
+		Login:  "gwen",
+		Groups: []string{"docker", "sudo"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, m.ReconcileStaticHostUser(u))
+
+	fakeUser, ok := backend.users["gwen"]
+	require.True(t, ok)
+	require.Equal(t, []string{"docker"}, fakeUser.groups)
+}
+
+func TestReconcileStaticHostUserNoAllowedGroupsDropsAll(t *testing.T) {
+	backend := NewFakeHostUsersBackend()
+	m := newTestHostUsersManagement(backend, nil)
+
+	u, err := types.NewStaticHostUser("harry", types.StaticHostUserSpecV1{
+		Login:  "harry",
+		Groups: []string{"docker"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, m.ReconcileStaticHostUser(u))
+
+	fakeUser, ok := backend.users["harry"]
+	require.True(t, ok)
+	require.Empty(t, fakeUser.groups)
+}
+
+func TestReconcileStaticHostUserAppliesHomeDirMode(t *testing.T) {
+	backend := NewFakeHostUsersBackend()
+	m := newTestHostUsersManagement(backend, nil)
+
+	u, err := types.NewStaticHostUser("ivy", types.StaticHostUserSpecV1{
+		Login:       "ivy",
+		HomeDirMode: "0700",
+	})
+	require.NoError(t, err)
+	require.NoError(t, m.ReconcileStaticHostUser(u))
+
+	fakeUser, ok := backend.users["ivy"]
+	require.True(t, ok)
+	require.Equal(t, "0700", fakeUser.homeDirMode)
+}
+
+func TestReconcileStaticHostUserFallsBackToLoginDefaultsHomeMode(t *testing.T) {
+	backend := NewFakeHostUsersBackend()
+	m := newTestHostUsersManagement(backend, nil)
+	m.loginDefaults.homeMode = "0750"
+
+	u, err := types.NewStaticHostUser("jack", types.StaticHostUserSpecV1{Login: "jack"})
+	require.NoError(t, err)
+	require.NoError(t, m.ReconcileStaticHostUser(u))
+
+	fakeUser, ok := backend.users["jack"]
+	require.True(t, ok)
+	require.Equal(t, "0750", fakeUser.homeDirMode)
+}
+
+func TestReconcileStaticHostUserLocksPasswordAndAppliesAgingPolicy(t *testing.T) {
+	backend := NewFakeHostUsersBackend()
+	m := newTestHostUsersManagement(backend, nil)
+
+	u, err := types.NewStaticHostUser("kate", types.StaticHostUserSpecV1{
+		Login:              "kate",
+		MaxPasswordAgeDays: 30,
+	})
+	require.NoError(t, err)
+	require.NoError(t, m.ReconcileStaticHostUser(u))
+
+	require.True(t, backend.LockedPasswords["kate"])
+	require.Equal(t, 30, backend.MaxPasswordAge["kate"])
+}
+
+func TestReconcileStaticHostUserSkipsAgingPolicyForSystemAccounts(t *testing.T) {
+	backend := NewFakeHostUsersBackend()
+	m := newTestHostUsersManagement(backend, nil)
+
+	u, err := types.NewStaticHostUser("svc", types.StaticHostUserSpecV1{
+		Login:              "svc",
+		SystemAccount:      true,
+		MaxPasswordAgeDays: 30,
+	})
+	require.NoError(t, err)
+	require.NoError(t, m.ReconcileStaticHostUser(u))
+
+	require.True(t, backend.LockedPasswords["svc"])
+	require.NotContains(t, backend.MaxPasswordAge, "svc")
+}
+
+func TestReconcileStaticHostUserCreatesSystemAccount(t *testing.T) {
+	backend := NewFakeHostUsersBackend()
+	m := newTestHostUsersManagement(backend, nil)
+
+	u, err := types.NewStaticHostUser("automation", types.StaticHostUserSpecV1{
+		Login:         "automation",
+		SystemAccount: true,
+	})
+	require.NoError(t, err)
+	require.NoError(t, m.ReconcileStaticHostUser(u))
+
+	fakeUser, ok := backend.users["automation"]
+	require.True(t, ok)
+	require.True(t, fakeUser.system)
+}
+
+func TestReconcileStaticHostUserAdoptsPartiallyProvisionedAccount(t *testing.T) {
+	backend := NewFakeHostUsersBackend()
+	m := newTestHostUsersManagement(backend, []string{"docker"})
+
+	// Simulate an account left behind by a crash between useradd and the
+	// rest of reconciliation: the login exists, but none of the follow-up
+	// steps (group assignment, password lock, sudoers) ever ran.
+	require.NoError(t, backend.CreateUser("crashed", nil, "", false, ""))
+	require.False(t, backend.LockedPasswords["crashed"])
+
+	u, err := types.NewStaticHostUser("crashed", types.StaticHostUserSpecV1{
+		Login:   "crashed",
+		Groups:  []string{"docker"},
+		Sudoers: []string{"crashed ALL=(ALL) NOPASSWD: ALL"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, m.ReconcileStaticHostUser(u))
+
+	fakeUser, ok := backend.users["crashed"]
+	require.True(t, ok)
+	require.Equal(t, []string{"docker"}, fakeUser.groups)
+	require.True(t, backend.LockedPasswords["crashed"])
+	require.Equal(t, []string{"crashed ALL=(ALL) NOPASSWD: ALL"}, backend.Sudoers["crashed"])
+}
+
+func TestAcquireSessionEnforcesMaxSessionsPerUser(t *testing.T) {
+	backend := NewFakeHostUsersBackend()
+	m := newTestHostUsersManagement(backend, nil)
+	m.maxSessionsPerUser = 2
+
+	release1, err := m.AcquireSession("liam")
+	require.NoError(t, err)
+	_, err = m.AcquireSession("liam")
+	require.NoError(t, err)
+
+	_, err = m.AcquireSession("liam")
+	require.True(t, trace.IsLimitExceeded(err))
+
+	// Releasing a slot makes room for a new session.
+	release1()
+	_, err = m.AcquireSession("liam")
+	require.NoError(t, err)
+}
+
+func TestAcquireSessionNoLimitWhenUnset(t *testing.T) {
+	backend := NewFakeHostUsersBackend()
+	m := newTestHostUsersManagement(backend, nil)
+
+	for i := 0; i < 5; i++ {
+		_, err := m.AcquireSession("noah")
+		require.NoError(t, err)
+	}
+}
+
+func TestProvisionedLoginAppliesUsernameTemplate(t *testing.T) {
+	backend := NewFakeHostUsersBackend()
+	m := newTestHostUsersManagement(backend, nil)
+	m.usernameTemplate = "tp-{{username}}"
+
+	require.Equal(t, "tp-alice", m.ProvisionedLogin("alice"))
+}
+
+func TestProvisionedLoginNoTemplateReturnsRequestedLogin(t *testing.T) {
+	backend := NewFakeHostUsersBackend()
+	m := newTestHostUsersManagement(backend, nil)
+
+	require.Equal(t, "alice", m.ProvisionedLogin("alice"))
+}
+
+func TestCreateTemporaryUserWritesMOTDWhenConfigured(t *testing.T) {
+	backend := NewFakeHostUsersBackend()
+	m := newTestHostUsersManagement(backend, nil)
+	m.ephemeralUserMOTD = true
+
+	release, err := m.CreateTemporaryUser("oscar", nil)
+	require.NoError(t, err)
+	t.Cleanup(release)
+
+	require.Contains(t, backend.MOTD, "oscar")
+}
+
+func TestCreateTemporaryUserSkipsMOTDByDefault(t *testing.T) {
+	backend := NewFakeHostUsersBackend()
+	m := newTestHostUsersManagement(backend, nil)
+
+	release, err := m.CreateTemporaryUser("penny", nil)
+	require.NoError(t, err)
+	t.Cleanup(release)
+
+	require.NotContains(t, backend.MOTD, "penny")
+}
+
+func TestReportHostUserRecordsIncludesUIDAndGroups(t *testing.T) {
+	backend := NewFakeHostUsersBackend()
+	m := newTestHostUsersManagement(backend, []string{"docker"})
+
+	u, err := types.NewStaticHostUser("erin", types.StaticHostUserSpecV1{
+		Login:  "erin",
+		Groups: []string{"docker"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, m.ReconcileStaticHostUser(u))
+
+	records := newFakeHostUserRecords()
+	m.ReportHostUserRecords(context.Background(), records, "test-host")
+
+	records.mu.Lock()
+	record, ok := records.records["erin"]
+	records.mu.Unlock()
+	require.True(t, ok)
+	require.Equal(t, "test-host", record.GetHostID())
+
+	fakeUser, ok := backend.users["erin"]
+	require.True(t, ok)
+	require.Equal(t, fakeUser.uid, record.GetUID())
+	require.Equal(t, []string{"docker"}, record.GetGroups())
+}
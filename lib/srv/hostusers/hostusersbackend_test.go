@@ -0,0 +1,66 @@
+/*
+Copyright 2022 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostusers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCheckTool writes an executable script at dir/name that exits with
+// exitCode, for exercising checkShadowFileIntegrity without real pwck/grpck.
+func fakeCheckTool(t *testing.T, dir, name string, exitCode int) string {
+	path := filepath.Join(dir, name)
+	script := fmt.Sprintf("#!/bin/sh\nexit %d\n", exitCode)
+	require.NoError(t, os.WriteFile(path, []byte(script), 0755))
+	return path
+}
+
+func TestCheckShadowFileIntegrityNoOpWhenDisabled(t *testing.T) {
+	b := &hostUsersBackend{verifyShadowIntegrity: false}
+	require.NoError(t, b.checkShadowFileIntegrity())
+}
+
+func TestCheckShadowFileIntegrityPassesWhenToolsReportConsistent(t *testing.T) {
+	dir := t.TempDir()
+	b := &hostUsersBackend{
+		verifyShadowIntegrity: true,
+		toolPaths: map[string]string{
+			"pwck":  fakeCheckTool(t, dir, "pwck", 0),
+			"grpck": fakeCheckTool(t, dir, "grpck", 0),
+		},
+	}
+	require.NoError(t, b.checkShadowFileIntegrity())
+}
+
+func TestCheckShadowFileIntegrityRefusesOnInconsistentDatabase(t *testing.T) {
+	dir := t.TempDir()
+	b := &hostUsersBackend{
+		verifyShadowIntegrity: true,
+		toolPaths: map[string]string{
+			"pwck":  fakeCheckTool(t, dir, "pwck", 1),
+			"grpck": fakeCheckTool(t, dir, "grpck", 0),
+		},
+	}
+	err := b.checkShadowFileIntegrity()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "pwck")
+}
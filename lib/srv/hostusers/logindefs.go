@@ -0,0 +1,151 @@
+/*
+Copyright 2022 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostusers
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+const loginDefsPath = "/etc/login.defs"
+
+// Fallback values used when neither /etc/login.defs nor `useradd -D` define
+// a setting, matching the defaults shipped by most distros' shadow-utils.
+const (
+	fallbackUIDMin     = 1000
+	fallbackUIDMax     = 60000
+	fallbackSysUIDMin  = 100
+	fallbackSysUIDMax  = 999
+	fallbackHomeMode   = "0700"
+	fallbackCreateHome = true
+)
+
+// loginDefaults holds the subset of /etc/login.defs and `useradd -D`
+// settings HostUsersManagement needs in order to avoid hardcoding
+// distro-specific assumptions about new accounts.
+type loginDefaults struct {
+	// uidMin and uidMax bound the range of UIDs useradd assigns to regular
+	// accounts, and are used as a guard against removing accounts
+	// Teleport didn't provision.
+	uidMin, uidMax int
+	// sysUIDMin and sysUIDMax bound the range of UIDs useradd -r assigns
+	// to system accounts, used by the same removal guard for accounts
+	// created with SystemAccount set.
+	sysUIDMin, sysUIDMax int
+	// homeMode is the permission mode new home directories are created
+	// with when a StaticHostUser doesn't specify its own.
+	homeMode string
+	// createHome reports whether useradd creates a home directory by
+	// default on this system.
+	createHome bool
+}
+
+// readLoginDefaults reads /etc/login.defs and `useradd -D`, falling back to
+// common distro defaults for any setting neither source defines.
+func readLoginDefaults() loginDefaults {
+	d := loginDefaults{
+		uidMin:     fallbackUIDMin,
+		uidMax:     fallbackUIDMax,
+		sysUIDMin:  fallbackSysUIDMin,
+		sysUIDMax:  fallbackSysUIDMax,
+		homeMode:   fallbackHomeMode,
+		createHome: fallbackCreateHome,
+	}
+	if f, err := os.Open(loginDefsPath); err == nil {
+		defer f.Close()
+		d.applyOverrides(parseDefaultsFile(f))
+	}
+	if out, err := exec.Command("useradd", "-D").Output(); err == nil {
+		d.applyOverrides(parseDefaultsFile(strings.NewReader(string(out))))
+	}
+	return d
+}
+
+// applyOverrides updates d with any of the recognized settings present in
+// kv, leaving unrecognized or unparseable entries untouched.
+func (d *loginDefaults) applyOverrides(kv map[string]string) {
+	if v, ok := kv["UID_MIN"]; ok {
+		if n, err := strconv.Atoi(v); err == nil {
+			d.uidMin = n
+		}
+	}
+	if v, ok := kv["UID_MAX"]; ok {
+		if n, err := strconv.Atoi(v); err == nil {
+			d.uidMax = n
+		}
+	}
+	if v, ok := kv["SYS_UID_MIN"]; ok {
+		if n, err := strconv.Atoi(v); err == nil {
+			d.sysUIDMin = n
+		}
+	}
+	if v, ok := kv["SYS_UID_MAX"]; ok {
+		if n, err := strconv.Atoi(v); err == nil {
+			d.sysUIDMax = n
+		}
+	}
+	if v, ok := kv["HOME_MODE"]; ok && v != "" {
+		d.homeMode = v
+	}
+	if v, ok := kv["CREATE_HOME"]; ok {
+		d.createHome = strings.EqualFold(v, "yes")
+	}
+}
+
+// isManagedUID reports whether uid falls within the regular [uidMin, uidMax]
+// range or the system [sysUIDMin, sysUIDMax] range, guarding against
+// removing accounts Teleport didn't provision. A zero-value loginDefaults
+// (every bound unset) disables the guard.
+func (d loginDefaults) isManagedUID(uid string) bool {
+	if d.uidMin == 0 && d.uidMax == 0 && d.sysUIDMin == 0 && d.sysUIDMax == 0 {
+		return true
+	}
+	n, err := strconv.Atoi(uid)
+	if err != nil {
+		return true
+	}
+	inRegularRange := n >= d.uidMin && n <= d.uidMax
+	inSystemRange := n >= d.sysUIDMin && n <= d.sysUIDMax
+	return inRegularRange || inSystemRange
+}
+
+// parseDefaultsFile parses both /etc/login.defs's "KEY value" lines and
+// `useradd -D`'s "KEY=value" lines, skipping blanks and comments.
+func parseDefaultsFile(r io.Reader) map[string]string {
+	kv := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var key, value string
+		if idx := strings.Index(line, "="); idx > 0 {
+			key, value = line[:idx], line[idx+1:]
+		} else if fields := strings.Fields(line); len(fields) >= 2 {
+			key, value = fields[0], fields[1]
+		} else {
+			continue
+		}
+		kv[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return kv
+}
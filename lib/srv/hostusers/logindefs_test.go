@@ -0,0 +1,95 @@
+/*
+Copyright 2022 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostusers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gravitational/teleport/api/types"
+)
+
+func TestParseDefaultsFileHandlesBothFormats(t *testing.T) {
+	// /etc/login.defs uses "KEY value", `useradd -D` uses "KEY=value".
+	kv := parseDefaultsFile(strings.NewReader(`
+# a comment
+UID_MIN 1000
+UID_MAX=60000
+
+HOME_MODE   0755
+`))
+	require.Equal(t, map[string]string{
+		"UID_MIN":   "1000",
+		"UID_MAX":   "60000",
+		"HOME_MODE": "0755",
+	}, kv)
+}
+
+func TestApplyOverridesOnlyTouchesRecognizedKeys(t *testing.T) {
+	d := loginDefaults{
+		uidMin:     fallbackUIDMin,
+		uidMax:     fallbackUIDMax,
+		sysUIDMin:  fallbackSysUIDMin,
+		sysUIDMax:  fallbackSysUIDMax,
+		homeMode:   fallbackHomeMode,
+		createHome: fallbackCreateHome,
+	}
+	d.applyOverrides(map[string]string{
+		"UID_MIN":      "2000",
+		"CREATE_HOME":  "no",
+		"SOME_UNKNOWN": "ignored",
+	})
+	require.Equal(t, 2000, d.uidMin)
+	require.Equal(t, fallbackUIDMax, d.uidMax)
+	require.False(t, d.createHome)
+}
+
+func TestIsManagedUIDGuardsRegularAndSystemRanges(t *testing.T) {
+	d := loginDefaults{uidMin: 1000, uidMax: 60000, sysUIDMin: 100, sysUIDMax: 999}
+
+	require.True(t, d.isManagedUID("1000"))
+	require.True(t, d.isManagedUID("500"))
+	require.False(t, d.isManagedUID("0"))
+	require.False(t, d.isManagedUID("99"))
+}
+
+func TestApplyPendingRemovalsRefusesUnmanagedUID(t *testing.T) {
+	backend := NewFakeHostUsersBackend()
+	m := newTestHostUsersManagement(backend, nil)
+	m.loginDefaults.uidMin, m.loginDefaults.uidMax = 1000, 60000
+	m.loginDefaults.sysUIDMin, m.loginDefaults.sysUIDMax = 100, 999
+
+	backend.NextUID = 50
+	require.NoError(t, backend.CreateUser("root-ish", nil, "0700", false, ""))
+
+	record, err := types.NewHostUserRecord("test-host", types.HostUserRecordSpecV1{
+		HostID: "test-host",
+		Login:  "root-ish",
+	})
+	require.NoError(t, err)
+	record.SetDeletionRequested(true)
+	records := newFakeHostUserRecords()
+	records.records["root-ish"] = record
+
+	m.ApplyPendingRemovals(context.Background(), records, "test-host")
+
+	_, ok := backend.users["root-ish"]
+	require.True(t, ok, "ApplyPendingRemovals must not remove an account outside the managed UID range")
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2022 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostusers
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/gravitational/trace"
+
+	"github.com/gravitational/teleport/api/types"
+)
+
+// requiredHostUserBinaries are the binaries hostUsersBackend shells out to.
+// Some distros ship alternatives (e.g. busybox's `adduser`/`deluser`), but
+// this node only supports the shadow-utils names used by hostUsersBackend.
+var requiredHostUserBinaries = []string{"useradd", "usermod", "userdel", "passwd", "chage"}
+
+const nsswitchPath = "/etc/nsswitch.conf"
+
+// PreflightHostUserProvisioning probes this node for everything
+// HostUsersManagement needs at startup: the required shadow-utils binaries,
+// permission to run them, and an NSS configuration that actually consults
+// the files created by useradd. toolPaths overrides the path checked for a
+// given tool name, for distros that don't install it under a directory
+// covered by the agent's inherited PATH; a tool not present in the map is
+// located via PATH as usual. It's meant to be run once at agent startup so
+// that provisioning failures are reported in the node's heartbeat and
+// surfaced before a session ever attempts to create a host user.
+func PreflightHostUserProvisioning(toolPaths map[string]string) types.HostUserProvisioningCapability {
+	var missing []string
+	for _, bin := range requiredHostUserBinaries {
+		path := bin
+		if override, ok := toolPaths[bin]; ok && override != "" {
+			path = override
+		}
+		if _, err := exec.LookPath(path); err != nil {
+			missing = append(missing, path)
+		}
+	}
+	if len(missing) > 0 {
+		return types.HostUserProvisioningCapability{
+			Capable: false,
+			Error:   "missing required binaries: " + strings.Join(missing, ", "),
+		}
+	}
+
+	if os.Geteuid() != 0 {
+		return types.HostUserProvisioningCapability{
+			Capable: false,
+			Error:   "teleport is not running as root, cannot manage host users",
+		}
+	}
+
+	if err := checkNSSConfiguredForFiles(); err != nil {
+		return types.HostUserProvisioningCapability{
+			Capable: false,
+			Error:   err.Error(),
+		}
+	}
+
+	return types.HostUserProvisioningCapability{Capable: true}
+}
+
+// checkNSSConfiguredForFiles returns an error if /etc/nsswitch.conf exists
+// and its passwd database doesn't consult "files", meaning accounts
+// created by useradd would never be resolvable via user.Lookup.
+func checkNSSConfiguredForFiles() error {
+	data, err := os.ReadFile(nsswitchPath)
+	if os.IsNotExist(err) {
+		// No nsswitch.conf means glibc falls back to its compiled-in
+		// default, which consults files; nothing to check.
+		return nil
+	}
+	if err != nil {
+		return trace.Wrap(err, "reading %s", nsswitchPath)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "passwd:") {
+			continue
+		}
+		sources := strings.Fields(strings.TrimPrefix(line, "passwd:"))
+		for _, source := range sources {
+			if source == "files" {
+				return nil
+			}
+		}
+		return trace.BadParameter("%s: passwd database does not include \"files\", host users created by teleport would be unresolvable", nsswitchPath)
+	}
+	// No passwd line at all; treat the same as file-not-found.
+	return nil
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2022 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostusers
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/gravitational/teleport/api/types"
+	"github.com/gravitational/teleport/lib/services"
+)
+
+// reconcileInterval is how often RunReconciler polls for StaticHostUser
+// resources and reports/garbage-collects host users. There is no watch
+// event for StaticHostUser, so this has to poll.
+const reconcileInterval = 10 * time.Second
+
+// ReconcilerConfig configures RunReconciler.
+type ReconcilerConfig struct {
+	// HostID identifies this node's host user records to the auth server,
+	// typically the node's ServerIdentity.ID.HostUUID.
+	HostID string
+	// Labels returns this node's current labels, used to match against each
+	// StaticHostUser's NodeLabels selector. Called once per reconcile pass,
+	// so callers can return a live view of dynamic labels.
+	Labels func() map[string]string
+	// StaticHostUsers lists the StaticHostUser resources to reconcile
+	// against. Satisfied by auth.ClientI.
+	StaticHostUsers services.StaticHostUsers
+	// Reporter reports this node's managed host users to the auth server.
+	// Satisfied by auth.ClientI.
+	Reporter HostUserReporter
+	// RemovalChecker fetches and clears this node's host user records
+	// marked for removal. Satisfied by auth.ClientI.
+	RemovalChecker HostUserRemovalChecker
+	// GCPolicySource fetches the cluster's host user garbage collection
+	// policy. Satisfied by auth.ClientI.
+	GCPolicySource HostUserGCPolicySource
+}
+
+// HostUserGCPolicySource fetches the cluster-level HostUserGCPolicy
+// singleton. It is satisfied by auth.ClientI.
+type HostUserGCPolicySource interface {
+	GetHostUserGCPolicy(ctx context.Context) (types.HostUserGCPolicy, error)
+}
+
+// RunReconciler periodically reconciles m against the StaticHostUser
+// resources matching cfg's labels, reports the resulting managed host
+// users to the auth server, applies any pending removals, and runs
+// ApplyHostUserGC, until ctx is canceled. It blocks and should be run in
+// its own goroutine.
+func RunReconciler(ctx context.Context, m *HostUsersManagement, cfg ReconcilerConfig) {
+	ticker := time.NewTicker(reconcileInterval)
+	defer ticker.Stop()
+
+	reconcileOnce(ctx, m, cfg)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reconcileOnce(ctx, m, cfg)
+		}
+	}
+}
+
+func reconcileOnce(ctx context.Context, m *HostUsersManagement, cfg ReconcilerConfig) {
+	users, err := cfg.StaticHostUsers.GetStaticHostUsers(ctx)
+	if err != nil {
+		log.WithError(err).Warn("Failed to fetch static host users for reconciliation.")
+	} else {
+		m.ReconcileStaticHostUsers(users, cfg.Labels())
+	}
+	m.ReportHostUserRecords(ctx, cfg.Reporter, cfg.HostID)
+	m.ApplyPendingRemovals(ctx, cfg.RemovalChecker, cfg.HostID)
+	if cfg.GCPolicySource != nil {
+		if policy, err := cfg.GCPolicySource.GetHostUserGCPolicy(ctx); err != nil {
+			log.WithError(err).Warn("Failed to fetch host user GC policy for reconciliation.")
+		} else {
+			m.SetHostUserGCPolicy(policy)
+		}
+	}
+	m.ApplyHostUserGC()
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2022 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostusers
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gravitational/teleport/api/types"
+)
+
+// fakeStaticHostUsers is a minimal services.StaticHostUsers for exercising
+// reconcileOnce without a real auth client.
+type fakeStaticHostUsers struct {
+	users []types.StaticHostUser
+}
+
+func (f *fakeStaticHostUsers) GetStaticHostUser(ctx context.Context, name string) (types.StaticHostUser, error) {
+	panic("not implemented")
+}
+
+func (f *fakeStaticHostUsers) GetStaticHostUsers(ctx context.Context) ([]types.StaticHostUser, error) {
+	return f.users, nil
+}
+
+func (f *fakeStaticHostUsers) CreateStaticHostUser(ctx context.Context, user types.StaticHostUser) error {
+	panic("not implemented")
+}
+
+func (f *fakeStaticHostUsers) UpdateStaticHostUser(ctx context.Context, user types.StaticHostUser) error {
+	panic("not implemented")
+}
+
+func (f *fakeStaticHostUsers) UpsertStaticHostUser(ctx context.Context, user types.StaticHostUser) error {
+	panic("not implemented")
+}
+
+func (f *fakeStaticHostUsers) DeleteStaticHostUser(ctx context.Context, name string) error {
+	panic("not implemented")
+}
+
+func (f *fakeStaticHostUsers) DeleteAllStaticHostUsers(ctx context.Context) error {
+	panic("not implemented")
+}
+
+// fakeHostUserRecords is a minimal HostUserReporter/HostUserRemovalChecker
+// for exercising reconcileOnce without a real auth client.
+type fakeHostUserRecords struct {
+	mu      sync.Mutex
+	records map[string]types.HostUserRecord
+}
+
+func newFakeHostUserRecords() *fakeHostUserRecords {
+	return &fakeHostUserRecords{records: make(map[string]types.HostUserRecord)}
+}
+
+func (f *fakeHostUserRecords) UpsertHostUserRecord(ctx context.Context, record types.HostUserRecord) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.records[record.GetLogin()] = record
+	return nil
+}
+
+func (f *fakeHostUserRecords) GetHostUserRecords(ctx context.Context, hostID string) ([]types.HostUserRecord, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []types.HostUserRecord
+	for _, r := range f.records {
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+func (f *fakeHostUserRecords) DeleteHostUserRecord(ctx context.Context, hostID, login string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.records, login)
+	return nil
+}
+
+// fakeGCPolicySource is a minimal HostUserGCPolicySource for exercising
+// reconcileOnce without a real auth client.
+type fakeGCPolicySource struct {
+	policy types.HostUserGCPolicy
+}
+
+func (f *fakeGCPolicySource) GetHostUserGCPolicy(ctx context.Context) (types.HostUserGCPolicy, error) {
+	return f.policy, nil
+}
+
+func TestReconcileOnceAppliesFetchedGCPolicy(t *testing.T) {
+	backend := NewFakeHostUsersBackend()
+	m := newTestHostUsersManagement(backend, nil)
+
+	policy, err := types.NewHostUserGCPolicy(types.HostUserGCPolicySpecV1{
+		Action: types.HostUserGCActionKeep,
+	})
+	require.NoError(t, err)
+
+	records := newFakeHostUserRecords()
+	reconcileOnce(context.Background(), m, ReconcilerConfig{
+		HostID:          "test-host",
+		Labels:          func() map[string]string { return nil },
+		StaticHostUsers: &fakeStaticHostUsers{},
+		Reporter:        records,
+		RemovalChecker:  records,
+		GCPolicySource:  &fakeGCPolicySource{policy: policy},
+	})
+
+	require.Equal(t, types.HostUserGCActionKeep, m.currentGCPolicy().GetAction())
+}
+
+func TestReconcileOnceCreatesAndReportsHostUsers(t *testing.T) {
+	backend := NewFakeHostUsersBackend()
+	m := newTestHostUsersManagement(backend, []string{"docker"})
+
+	u, err := types.NewStaticHostUser("carol", types.StaticHostUserSpecV1{
+		Login:      "carol",
+		Groups:     []string{"docker"},
+		NodeLabels: types.Labels{types.Wildcard: []string{types.Wildcard}},
+	})
+	require.NoError(t, err)
+
+	staticHostUsers := &fakeStaticHostUsers{users: []types.StaticHostUser{u}}
+	records := newFakeHostUserRecords()
+
+	reconcileOnce(context.Background(), m, ReconcilerConfig{
+		HostID:          "test-host",
+		Labels:          func() map[string]string { return nil },
+		StaticHostUsers: staticHostUsers,
+		Reporter:        records,
+		RemovalChecker:  records,
+	})
+
+	_, ok := backend.users["carol"]
+	require.True(t, ok, "reconcileOnce should have provisioned the local OS user")
+
+	records.mu.Lock()
+	record, ok := records.records["carol"]
+	records.mu.Unlock()
+	require.True(t, ok, "reconcileOnce should have reported the host user record")
+	require.Equal(t, "test-host", record.GetHostID())
+}
+
+func TestReconcileOnceAppliesPendingRemovals(t *testing.T) {
+	backend := NewFakeHostUsersBackend()
+	m := newTestHostUsersManagement(backend, nil)
+
+	u, err := types.NewStaticHostUser("dave", types.StaticHostUserSpecV1{
+		Login:      "dave",
+		NodeLabels: types.Labels{types.Wildcard: []string{types.Wildcard}},
+	})
+	require.NoError(t, err)
+	staticHostUsers := &fakeStaticHostUsers{users: []types.StaticHostUser{u}}
+	records := newFakeHostUserRecords()
+
+	// First pass provisions "dave" and reports it.
+	reconcileOnce(context.Background(), m, ReconcilerConfig{
+		HostID:          "test-host",
+		Labels:          func() map[string]string { return nil },
+		StaticHostUsers: staticHostUsers,
+		Reporter:        records,
+		RemovalChecker:  records,
+	})
+	_, ok := backend.users["dave"]
+	require.True(t, ok)
+
+	// Mark the record for removal, the way `tctl hostusers rm` would.
+	records.mu.Lock()
+	record := records.records["dave"]
+	records.mu.Unlock()
+	record.SetDeletionRequested(true)
+	records.mu.Lock()
+	records.records["dave"] = record
+	records.mu.Unlock()
+
+	m.ApplyPendingRemovals(context.Background(), records, "test-host")
+
+	_, ok = backend.users["dave"]
+	require.False(t, ok, "ApplyPendingRemovals should have removed the local OS user")
+}
@@ -93,6 +93,15 @@ type ExecCommand struct {
 
 	// X11Config contains an xauth entry to be added to the command user's xauthority.
 	X11Config X11Config `json:"x11_config"`
+
+	// ChrootDir, if set, is the directory the session's shell/command is
+	// chrooted into before it runs, confining it to that filesystem view.
+	// It's expected to already exist and, if Login's home directory was
+	// provisioned there, to be usable as that home directory's new root;
+	// populating and maintaining the jail's contents (shells, libraries,
+	// device nodes) is the operator's responsibility, same as OpenSSH's
+	// ChrootDirectory.
+	ChrootDir string `json:"chroot_dir,omitempty"`
 }
 
 // PAMConfig represents all the configuration data that needs to be passed to the child.
@@ -495,11 +504,20 @@ func buildCommand(c *ExecCommand, localUser *user.User, tty *os.File, pty *os.Fi
 		cmd.Args = []string{shellPath, "-c", c.Command}
 	}
 
+	// homeDir is the value to present to the session as $HOME and its
+	// initial cwd. It's localUser.HomeDir unless the session is chrooted,
+	// in which case that path belongs to the pre-chroot filesystem and the
+	// session instead sees its home as the new root, "/".
+	homeDir := localUser.HomeDir
+	if c.ChrootDir != "" {
+		homeDir = string(os.PathSeparator)
+	}
+
 	// Create default environment for user.
 	cmd.Env = []string{
 		"LANG=en_US.UTF-8",
 		getDefaultEnvPath(localUser.Uid, defaultLoginDefsPath),
-		"HOME=" + localUser.HomeDir,
+		"HOME=" + homeDir,
 		"USER=" + c.Login,
 		"SHELL=" + shellPath,
 	}
@@ -552,7 +570,7 @@ func buildCommand(c *ExecCommand, localUser *user.User, tty *os.File, pty *os.Fi
 	if err != nil {
 		return nil, trace.Wrap(err)
 	} else if exists {
-		cmd.Dir = localUser.HomeDir
+		cmd.Dir = homeDir
 	} else if !exists {
 		// Write failure to find home dir to stdout, same as OpenSSH.
 		msg := fmt.Sprintf("Could not set shell's cwd to home directory %q, defaulting to %q\n", localUser.HomeDir, string(os.PathSeparator))
@@ -562,6 +580,13 @@ func buildCommand(c *ExecCommand, localUser *user.User, tty *os.File, pty *os.Fi
 		cmd.Dir = string(os.PathSeparator)
 	}
 
+	// Confine the session to ChrootDir, if configured. This must be set on
+	// the same SysProcAttr populated above, since Chroot is applied by the
+	// kernel at process start alongside Setsid/Credential.
+	if c.ChrootDir != "" {
+		cmd.SysProcAttr.Chroot = c.ChrootDir
+	}
+
 	// Only set process credentials if the UID/GID of the requesting user are
 	// different than the process (Teleport).
 	//
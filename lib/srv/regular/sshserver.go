@@ -48,6 +48,7 @@ import (
 	"github.com/gravitational/teleport/lib/services"
 	rsession "github.com/gravitational/teleport/lib/session"
 	"github.com/gravitational/teleport/lib/srv"
+	"github.com/gravitational/teleport/lib/srv/hostusers"
 	"github.com/gravitational/teleport/lib/sshutils"
 	"github.com/gravitational/teleport/lib/sshutils/x11"
 	"github.com/gravitational/teleport/lib/teleagent"
@@ -166,6 +167,11 @@ type Server struct {
 	// ebpf is the service used for enhanced session recording.
 	ebpf bpf.BPF
 
+	// hostUserCapability reports this node's ability to provision local OS
+	// users, determined once at startup and included in the node's
+	// heartbeat.
+	hostUserCapability types.HostUserProvisioningCapability
+
 	// restrictedMgr is the service used for restricting access to kernel objects
 	restrictedMgr restricted.Manager
 
@@ -187,6 +193,10 @@ type Server struct {
 
 	// lockWatcher is the server's lock watcher.
 	lockWatcher *services.LockWatcher
+
+	// hostUsers, if set, provisions a temporary local OS user for the
+	// lifetime of each session on this node.
+	hostUsers *hostusers.HostUsersManagement
 }
 
 // GetClock returns server clock implementation
@@ -245,6 +255,12 @@ func (s *Server) GetLockWatcher() *services.LockWatcher {
 	return s.lockWatcher
 }
 
+// GetHostUsers returns the server's host user manager, or nil if none was
+// configured via SetHostUsers.
+func (s *Server) GetHostUsers() *hostusers.HostUsersManagement {
+	return s.hostUsers
+}
+
 // isAuditedAtProxy returns true if sessions are being recorded at the proxy
 // and this is a Teleport node.
 func (s *Server) isAuditedAtProxy() bool {
@@ -554,6 +570,15 @@ func SetLockWatcher(lockWatcher *services.LockWatcher) ServerOption {
 	}
 }
 
+// SetHostUsers sets the server's host user manager, provisioning a
+// temporary local OS user for the lifetime of each session.
+func SetHostUsers(hostUsers *hostusers.HostUsersManagement) ServerOption {
+	return func(s *Server) error {
+		s.hostUsers = hostUsers
+		return nil
+	}
+}
+
 // SetX11ForwardingConfig sets the server's X11 forwarding configuration
 func SetX11ForwardingConfig(xc *x11.ServerConfig) ServerOption {
 	return func(s *Server) error {
@@ -632,6 +657,7 @@ func New(addr utils.NetAddr,
 		component = teleport.ComponentProxy
 	} else {
 		component = teleport.ComponentNode
+		s.hostUserCapability = hostusers.PreflightHostUserProvisioning(nil)
 	}
 
 	s.Entry = logrus.WithFields(logrus.Fields{
@@ -839,6 +865,9 @@ func (s *Server) getServerInfo() (types.Resource, error) {
 	}
 	server.SetExpiry(s.clock.Now().UTC().Add(apidefaults.ServerAnnounceTTL))
 	server.SetPublicAddr(s.proxyPublicAddr.String())
+	if !s.proxyMode {
+		server.SetHostUserProvisioning(s.hostUserCapability)
+	}
 	return server, nil
 }
 
@@ -1290,6 +1319,27 @@ Loop:
 	}
 }
 
+// provisionSessionHostUser provisions a temporary local OS user for the
+// lifetime of a single session, under identityContext's requested login. The
+// returned release func must be called when the session ends to tear the
+// account down (or hand it off to the configured garbage collection policy).
+func (s *Server) provisionSessionHostUser(identityContext srv.IdentityContext) (release func(), err error) {
+	releaseSession, err := s.hostUsers.AcquireSession(identityContext.Login)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	groups := s.hostUsers.FilterAllowedHostGroups(identityContext.RoleSet.HostGroups())
+	rollback, err := s.hostUsers.CreateTemporaryUser(identityContext.Login, groups)
+	if err != nil {
+		releaseSession()
+		return nil, trace.Wrap(err)
+	}
+	return func() {
+		rollback()
+		releaseSession()
+	}, nil
+}
+
 // handleSessionRequests handles out of band session requests once the session
 // channel has been created this function's loop handles all the "exec",
 // "subsystem" and "shell" requests.
@@ -1320,6 +1370,16 @@ func (s *Server) handleSessionRequests(ctx context.Context, ccx *sshutils.Connec
 	scx.ChannelType = teleport.ChanSession
 	defer scx.Close()
 
+	if s.hostUsers != nil && !s.proxyMode {
+		releaseHostUser, err := s.provisionSessionHostUser(identityContext)
+		if err != nil {
+			log.WithError(err).Error("Unable to provision host user for session.")
+			writeStderr(ch, "Unable to provision host user for session.")
+			return
+		}
+		defer releaseHostUser()
+	}
+
 	ch = scx.TrackActivity(ch)
 
 	// The keep-alive loop will keep pinging the remote server and after it has
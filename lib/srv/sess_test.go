@@ -27,6 +27,7 @@ import (
 	"github.com/gravitational/teleport/lib/pam"
 	restricted "github.com/gravitational/teleport/lib/restrictedsession"
 	"github.com/gravitational/teleport/lib/services"
+	"github.com/gravitational/teleport/lib/srv/hostusers"
 	rsession "github.com/gravitational/teleport/lib/session"
 	"github.com/gravitational/trace"
 	"github.com/jonboulle/clockwork"
@@ -173,6 +174,11 @@ func (m *mockServer) GetLockWatcher() *services.LockWatcher {
 	return nil
 }
 
+// GetHostUsers returns the server's host user manager.
+func (m *mockServer) GetHostUsers() *hostusers.HostUsersManagement {
+	return nil
+}
+
 func TestSession_newRecorder(t *testing.T) {
 	proxyRecording, err := types.NewSessionRecordingConfigFromConfigFile(types.SessionRecordingConfigSpecV2{
 		Mode: types.RecordAtProxy,
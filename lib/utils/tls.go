@@ -183,6 +183,25 @@ var cipherSuiteMapping = map[string]uint16{
 	"tls-ecdhe-ecdsa-with-chacha20-poly1305":  tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305,
 }
 
+// tlsVersionMapping is the mapping between human-readable TLS version
+// strings and the tls package's version IDs.
+var tlsVersionMapping = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// ParseTLSVersion converts a Teleport formatted TLS version string (e.g.
+// "1.2") into the tls package's numeric version ID.
+func ParseTLSVersion(version string) (uint16, error) {
+	v, ok := tlsVersionMapping[version]
+	if !ok {
+		return 0, trace.BadParameter("unsupported TLS version: %v", version)
+	}
+	return v, nil
+}
+
 const (
 	// DefaultLRUCapacity is a capacity for LRU session cache
 	DefaultLRUCapacity = 1024
@@ -2125,6 +2125,57 @@ func TestClusterKubesGet(t *testing.T) {
 	}, resp.Items[0])
 }
 
+func TestClusterHostUsersGet(t *testing.T) {
+	env := newWebPack(t, 1)
+
+	proxy := env.proxies[0]
+	pack := proxy.authPack(t, "test-user@example.com")
+
+	endpoint := pack.clt.Endpoint("webapi", "sites", env.server.ClusterName(), "hostusers")
+	re, err := pack.clt.Get(context.Background(), endpoint, url.Values{})
+	require.NoError(t, err)
+
+	type testResponse struct {
+		Items []ui.HostUser `json:"items"`
+	}
+
+	// No host user reported yet.
+	resp := testResponse{}
+	require.NoError(t, json.Unmarshal(re.Bytes(), &resp))
+	require.Len(t, resp.Items, 0)
+
+	// A node reports a host user it provisioned.
+	record, err := types.NewHostUserRecord("test-hostID", types.HostUserRecordSpecV1{
+		HostID: "test-hostID",
+		Login:  "alice",
+		UID:    "1001",
+		Groups: []string{"docker"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, env.server.Auth().UpsertHostUserRecord(context.Background(), record))
+
+	re, err = pack.clt.Get(context.Background(), endpoint, url.Values{})
+	require.NoError(t, err)
+
+	resp = testResponse{}
+	require.NoError(t, json.Unmarshal(re.Bytes(), &resp))
+	require.Len(t, resp.Items, 1)
+	require.EqualValues(t, ui.HostUser{
+		HostID: "test-hostID",
+		Login:  "alice",
+		UID:    "1001",
+		Groups: []string{"docker"},
+	}, resp.Items[0])
+
+	// Filtering by an unrelated host ID should return nothing.
+	re, err = pack.clt.Get(context.Background(), endpoint, url.Values{"host_id": []string{"other-hostID"}})
+	require.NoError(t, err)
+
+	resp = testResponse{}
+	require.NoError(t, json.Unmarshal(re.Bytes(), &resp))
+	require.Len(t, resp.Items, 0)
+}
+
 // TestApplicationAccessDisabled makes sure application access can be disabled
 // via modules.
 func TestApplicationAccessDisabled(t *testing.T) {
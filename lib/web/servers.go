@@ -110,3 +110,21 @@ func (h *Handler) getDesktopHandle(w http.ResponseWriter, r *http.Request, p htt
 	// to see the desktop once in the UI, so just take the first one.
 	return ui.MakeDesktop(windowsDesktops[0]), nil
 }
+
+// clusterHostUsersGet returns the Teleport-provisioned host users reported
+// by nodes in the cluster, in a form the UI can present.
+func (h *Handler) clusterHostUsersGet(w http.ResponseWriter, r *http.Request, p httprouter.Params, ctx *SessionContext, site reversetunnel.RemoteSite) (interface{}, error) {
+	clt, err := ctx.GetUserClient(site)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	records, err := clt.GetHostUserRecords(r.Context(), r.URL.Query().Get("host_id"))
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	return listResourcesGetResponse{
+		Items: ui.MakeHostUsers(records),
+	}, nil
+}
@@ -249,3 +249,36 @@ func MakeDesktops(windowsDesktops []types.WindowsDesktop) []Desktop {
 
 	return uiDesktops
 }
+
+// HostUser describes a Teleport-provisioned host user to pass to the ui.
+type HostUser struct {
+	// HostID is the ID of the node that reported this host user.
+	HostID string `json:"hostId"`
+	// Login is the OS login of the host user.
+	Login string `json:"login"`
+	// UID is the OS user ID of the host user.
+	UID string `json:"uid"`
+	// Groups are the OS groups the host user belongs to.
+	Groups []string `json:"groups"`
+	// CreatedBy identifies the static host user resource that caused the
+	// login to be created.
+	CreatedBy string `json:"createdBy"`
+}
+
+// MakeHostUsers converts host user records from their API form to a type
+// the UI can display.
+func MakeHostUsers(records []types.HostUserRecord) []HostUser {
+	uiHostUsers := make([]HostUser, 0, len(records))
+
+	for _, record := range records {
+		uiHostUsers = append(uiHostUsers, HostUser{
+			HostID:    record.GetHostID(),
+			Login:     record.GetLogin(),
+			UID:       record.GetUID(),
+			Groups:    record.GetGroups(),
+			CreatedBy: record.GetCreatedBy(),
+		})
+	}
+
+	return uiHostUsers
+}
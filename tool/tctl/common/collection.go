@@ -551,6 +551,55 @@ func (c *appCollection) writeText(w io.Writer) error {
 	return trace.Wrap(err)
 }
 
+type staticHostUserCollection struct {
+	users []types.StaticHostUser
+}
+
+func (c *staticHostUserCollection) resources() (r []types.Resource) {
+	for _, resource := range c.users {
+		r = append(r, resource)
+	}
+	return r
+}
+
+func (c *staticHostUserCollection) writeText(w io.Writer) error {
+	var rows [][]string
+	for _, u := range c.users {
+		rows = append(rows, []string{
+			u.GetName(), u.GetLogin(), strings.Join(u.GetGroups(), ","),
+		})
+	}
+	headers := []string{"Name", "Login", "Groups"}
+	t := asciitable.MakeTable(headers, rows...)
+	_, err := t.AsBuffer().WriteTo(w)
+	return trace.Wrap(err)
+}
+
+type hostUserRecordCollection struct {
+	records []types.HostUserRecord
+}
+
+func (c *hostUserRecordCollection) resources() (r []types.Resource) {
+	for _, resource := range c.records {
+		r = append(r, resource)
+	}
+	return r
+}
+
+func (c *hostUserRecordCollection) writeText(w io.Writer) error {
+	var rows [][]string
+	for _, r := range c.records {
+		rows = append(rows, []string{
+			r.GetHostID(), r.GetLogin(), r.GetUID(), strings.Join(r.GetGroups(), ","), r.GetCreatedBy(),
+			strconv.FormatBool(r.IsDeletionRequested()),
+		})
+	}
+	headers := []string{"Host ID", "Login", "UID", "Groups", "Created By", "Pending Removal"}
+	t := asciitable.MakeTable(headers, rows...)
+	_, err := t.AsBuffer().WriteTo(w)
+	return trace.Wrap(err)
+}
+
 type authPrefCollection struct {
 	authPref types.AuthPreference
 }
@@ -601,6 +650,21 @@ func (c *recConfigCollection) writeText(w io.Writer) error {
 	return trace.Wrap(err)
 }
 
+type hostUserGCPolicyCollection struct {
+	policy types.HostUserGCPolicy
+}
+
+func (c *hostUserGCPolicyCollection) resources() (r []types.Resource) {
+	return []types.Resource{c.policy}
+}
+
+func (c *hostUserGCPolicyCollection) writeText(w io.Writer) error {
+	t := asciitable.MakeTable([]string{"Max Age", "Action"})
+	t.AddRow([]string{c.policy.GetMaxAge().Duration().String(), c.policy.GetAction()})
+	_, err := t.AsBuffer().WriteTo(w)
+	return trace.Wrap(err)
+}
+
 type netRestrictionsCollection struct {
 	netRestricts types.NetworkRestrictions
 }
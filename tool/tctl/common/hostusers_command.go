@@ -0,0 +1,108 @@
+/*
+Copyright 2022 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/gravitational/kingpin"
+	"github.com/gravitational/trace"
+
+	"github.com/gravitational/teleport/api/types"
+	"github.com/gravitational/teleport/lib/auth"
+	"github.com/gravitational/teleport/lib/service"
+)
+
+// HostUsersCommand implements `tctl hostusers` group of commands.
+type HostUsersCommand struct {
+	config *service.Config
+
+	hostID string
+	login  string
+
+	hostUsersList *kingpin.CmdClause
+	hostUsersRm   *kingpin.CmdClause
+}
+
+// Initialize allows HostUsersCommand to plug itself into the CLI parser.
+func (c *HostUsersCommand) Initialize(app *kingpin.Application, config *service.Config) {
+	c.config = config
+	hostusers := app.Command("hostusers", "Manage Teleport-created host users reported by nodes.")
+
+	c.hostUsersList = hostusers.Command("ls", "List Teleport-created host users across the fleet.")
+	c.hostUsersList.Flag("host-id", "Only list host users reported by this node.").StringVar(&c.hostID)
+
+	c.hostUsersRm = hostusers.Command("rm", "Instruct a node to remove a Teleport-created host user.")
+	c.hostUsersRm.Arg("host-id", "ID of the node that reported the host user.").Required().StringVar(&c.hostID)
+	c.hostUsersRm.Arg("login", "OS login to remove.").Required().StringVar(&c.login)
+}
+
+// TryRun attempts to run subcommands.
+func (c *HostUsersCommand) TryRun(cmd string, client auth.ClientI) (match bool, err error) {
+	ctx := context.TODO()
+	switch cmd {
+	case c.hostUsersList.FullCommand():
+		err = c.List(ctx, client)
+	case c.hostUsersRm.FullCommand():
+		err = c.Remove(ctx, client)
+	default:
+		return false, nil
+	}
+	return true, trace.Wrap(err)
+}
+
+// hostUserRecordsClient is the subset of auth.ClientI that List and Remove
+// need, kept narrow so the commands can be exercised against a fake in
+// tests instead of a full auth server.
+type hostUserRecordsClient interface {
+	GetHostUserRecords(ctx context.Context, hostID string) ([]types.HostUserRecord, error)
+	UpsertHostUserRecord(ctx context.Context, record types.HostUserRecord) error
+}
+
+// List prints every host user record known to the auth server, optionally
+// filtered down to a single reporting node.
+func (c *HostUsersCommand) List(ctx context.Context, client hostUserRecordsClient) error {
+	records, err := client.GetHostUserRecords(ctx, c.hostID)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	collection := &hostUserRecordCollection{records: records}
+	return trace.Wrap(collection.writeText(os.Stdout))
+}
+
+// Remove marks a reported host user for removal. The owning node deletes
+// the local account and clears the record the next time it checks in.
+func (c *HostUsersCommand) Remove(ctx context.Context, client hostUserRecordsClient) error {
+	records, err := client.GetHostUserRecords(ctx, c.hostID)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	for _, record := range records {
+		if record.GetLogin() != c.login {
+			continue
+		}
+		record.SetDeletionRequested(true)
+		if err := client.UpsertHostUserRecord(ctx, record); err != nil {
+			return trace.Wrap(err)
+		}
+		fmt.Printf("Requested removal of host user %q on node %q.\n", c.login, c.hostID)
+		return nil
+	}
+	return trace.NotFound("no host user %q reported by node %q", c.login, c.hostID)
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2022 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gravitational/trace"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gravitational/teleport/api/types"
+)
+
+type fakeHostUserRecordsClient struct {
+	records map[string]types.HostUserRecord
+}
+
+func (f *fakeHostUserRecordsClient) GetHostUserRecords(ctx context.Context, hostID string) ([]types.HostUserRecord, error) {
+	var out []types.HostUserRecord
+	for _, r := range f.records {
+		if hostID == "" || r.GetHostID() == hostID {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeHostUserRecordsClient) UpsertHostUserRecord(ctx context.Context, record types.HostUserRecord) error {
+	f.records[record.GetLogin()] = record
+	return nil
+}
+
+func TestHostUsersCommandRemoveMarksRecordForDeletion(t *testing.T) {
+	record, err := types.NewHostUserRecord("node-1", types.HostUserRecordSpecV1{
+		HostID: "node-1",
+		Login:  "frank",
+	})
+	require.NoError(t, err)
+	client := &fakeHostUserRecordsClient{records: map[string]types.HostUserRecord{"frank": record}}
+
+	cmd := &HostUsersCommand{hostID: "node-1", login: "frank"}
+	require.NoError(t, cmd.Remove(context.Background(), client))
+
+	require.True(t, client.records["frank"].IsDeletionRequested())
+}
+
+func TestHostUsersCommandRemoveNotFound(t *testing.T) {
+	client := &fakeHostUserRecordsClient{records: map[string]types.HostUserRecord{}}
+
+	cmd := &HostUsersCommand{hostID: "node-1", login: "frank"}
+	err := cmd.Remove(context.Background(), client)
+	require.True(t, trace.IsNotFound(err))
+}
@@ -103,6 +103,8 @@ func (rc *ResourceCommand) Initialize(app *kingpin.Application, config *service.
 		types.KindApp:                     rc.createApp,
 		types.KindDatabase:                rc.createDatabase,
 		types.KindToken:                   rc.createToken,
+		types.KindStaticHostUser:          rc.createStaticHostUser,
+		types.KindHostUserGCPolicy:        rc.createHostUserGCPolicy,
 	}
 	rc.config = config
 
@@ -505,6 +507,22 @@ func (rc *ResourceCommand) createSessionRecordingConfig(client auth.ClientI, raw
 	return nil
 }
 
+// createHostUserGCPolicy implements `tctl create host_user_gc_policy.yaml` command.
+func (rc *ResourceCommand) createHostUserGCPolicy(client auth.ClientI, raw services.UnknownResource) error {
+	ctx := context.TODO()
+
+	policy, err := services.UnmarshalHostUserGCPolicy(raw.Raw)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	if err := client.SetHostUserGCPolicy(ctx, policy); err != nil {
+		return trace.Wrap(err)
+	}
+	fmt.Printf("host user GC policy has been updated\n")
+	return nil
+}
+
 // createLock implements `tctl create lock.yaml` command.
 func (rc *ResourceCommand) createLock(client auth.ClientI, raw services.UnknownResource) error {
 	ctx := context.TODO()
@@ -602,6 +620,21 @@ func (rc *ResourceCommand) createToken(client auth.ClientI, raw services.Unknown
 	return trace.Wrap(err)
 }
 
+// createStaticHostUser implements `tctl create host_user.yaml` command.
+func (rc *ResourceCommand) createStaticHostUser(client auth.ClientI, raw services.UnknownResource) error {
+	user, err := services.UnmarshalStaticHostUser(raw.Raw)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	if rc.force {
+		err = client.UpsertStaticHostUser(context.Background(), user)
+	} else {
+		err = client.CreateStaticHostUser(context.Background(), user)
+	}
+	return trace.Wrap(err)
+}
+
 // Delete deletes resource by name
 func (rc *ResourceCommand) Delete(client auth.ClientI) (err error) {
 	singletonResources := []string{
@@ -727,6 +760,11 @@ func (rc *ResourceCommand) Delete(client auth.ClientI) (err error) {
 			return trace.Wrap(err)
 		}
 		fmt.Printf("network restrictions have been reset to defaults (allow all)\n")
+	case types.KindStaticHostUser:
+		if err = client.DeleteStaticHostUser(ctx, rc.ref.Name); err != nil {
+			return trace.Wrap(err)
+		}
+		fmt.Printf("static host user %q has been deleted\n", rc.ref.Name)
 	case types.KindApp:
 		if err = client.DeleteApp(ctx, rc.ref.Name); err != nil {
 			return trace.Wrap(err)
@@ -1136,6 +1174,15 @@ func (rc *ResourceCommand) getCollection(client auth.ClientI) (ResourceCollectio
 			return nil, trace.Wrap(err)
 		}
 		return &recConfigCollection{recConfig}, nil
+	case types.KindHostUserGCPolicy:
+		if rc.ref.Name != "" {
+			return nil, trace.BadParameter("only simple `tctl get %v` can be used", types.KindHostUserGCPolicy)
+		}
+		policy, err := client.GetHostUserGCPolicy(ctx)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		return &hostUserGCPolicyCollection{policy}, nil
 	case types.KindLock:
 		if rc.ref.Name == "" {
 			locks, err := client.GetLocks(ctx, false)
@@ -1178,6 +1225,25 @@ func (rc *ResourceCommand) getCollection(client auth.ClientI) (ResourceCollectio
 			return nil, trace.Wrap(err)
 		}
 		return &netRestrictionsCollection{nr}, nil
+	case types.KindStaticHostUser:
+		if rc.ref.Name == "" {
+			users, err := client.GetStaticHostUsers(ctx)
+			if err != nil {
+				return nil, trace.Wrap(err)
+			}
+			return &staticHostUserCollection{users: users}, nil
+		}
+		user, err := client.GetStaticHostUser(ctx, rc.ref.Name)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		return &staticHostUserCollection{users: []types.StaticHostUser{user}}, nil
+	case types.KindHostUserRecord:
+		records, err := client.GetHostUserRecords(ctx, rc.ref.Name)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		return &hostUserRecordCollection{records: records}, nil
 	case types.KindApp:
 		if rc.ref.Name == "" {
 			apps, err := client.GetApps(ctx)
@@ -38,6 +38,7 @@ func main() {
 		&common.AccessCommand{},
 		&common.LockCommand{},
 		&common.BotsCommand{},
+		&common.HostUsersCommand{},
 	}
 	common.Run(commands)
 }
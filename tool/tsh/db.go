@@ -17,19 +17,30 @@ limitations under the License.
 package main
 
 import (
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
 	"fmt"
 	"net"
 	"os"
+	"os/user"
+	"path/filepath"
 	"sort"
 	"strings"
 
 	"github.com/gravitational/teleport/api/client/proto"
+	apiprofile "github.com/gravitational/teleport/api/profile"
 	"github.com/gravitational/teleport/api/types"
+	"github.com/gravitational/teleport/lib/asciitable"
 	"github.com/gravitational/teleport/lib/client"
 	dbprofile "github.com/gravitational/teleport/lib/client/db"
+	"github.com/gravitational/teleport/lib/client/db/dbcmd"
 	"github.com/gravitational/teleport/lib/defaults"
+	"github.com/gravitational/teleport/lib/services"
 	"github.com/gravitational/teleport/lib/tlsca"
 	"github.com/gravitational/teleport/lib/utils"
+	"github.com/gravitational/teleport/lib/utils/prompt"
 
 	"github.com/gravitational/trace"
 )
@@ -61,7 +72,15 @@ func onListDatabases(cf *CLIConf) error {
 	if err != nil {
 		return trace.Wrap(err)
 	}
-	showDatabases(cf.SiteName, databases, activeDatabases, cf.Verbose)
+
+	var health map[string]client.DatabaseConnectivityStatus
+	if cf.Verbose {
+		health = make(map[string]client.DatabaseConnectivityStatus, len(databases))
+		for _, status := range tc.ProbeDatabasesConnectivity(cf.Context, databases) {
+			health[status.Database.GetName()] = status
+		}
+	}
+	showDatabases(cf.SiteName, databases, activeDatabases, health, cf.Verbose)
 	return nil
 }
 
@@ -104,6 +123,9 @@ func databaseLogin(cf *CLIConf, tc *client.TeleportClient, db tlsca.RouteToDatab
 	if err != nil {
 		return trace.Wrap(err)
 	}
+	if err := checkDatabaseRoute(profile, db); err != nil {
+		return trace.Wrap(err)
+	}
 
 	var key *client.Key
 	if err = client.RetryWithRelogin(cf.Context, tc, func() error {
@@ -143,6 +165,26 @@ func databaseLogin(cf *CLIConf, tc *client.TeleportClient, db tlsca.RouteToDatab
 	return nil
 }
 
+// checkDatabaseRoute validates the requested database user and name against
+// the allow-lists the auth server granted this session at login time (if
+// any were granted), so an unauthorized request fails fast with a clear
+// message instead of a server-side handshake failure.
+func checkDatabaseRoute(profile *client.ProfileStatus, db tlsca.RouteToDatabase) error {
+	if db.Username != "" && len(profile.DatabaseUsers) > 0 {
+		if ok, _ := services.MatchDatabaseUser(profile.DatabaseUsers, db.Username); !ok {
+			return trace.BadParameter("access to database user %q denied, allowed database users for this session: %v",
+				db.Username, profile.DatabaseUsers)
+		}
+	}
+	if db.Database != "" && len(profile.DatabaseNames) > 0 {
+		if ok, _ := services.MatchDatabaseName(profile.DatabaseNames, db.Database); !ok {
+			return trace.BadParameter("access to database name %q denied, allowed database names for this session: %v",
+				db.Database, profile.DatabaseNames)
+		}
+	}
+	return nil
+}
+
 // onDatabaseLogout implements "tsh db logout" command.
 func onDatabaseLogout(cf *CLIConf) error {
 	tc, err := makeClient(cf, false)
@@ -220,6 +262,40 @@ func onDatabaseEnv(cf *CLIConf) error {
 	return nil
 }
 
+// onDatabaseActivate implements "tsh db activate" command, writing a
+// virtualenv-style activation script (and a PATH shim for the database's
+// CLI client) that a user can source to make the bare client command
+// connect to this database with no flags, and "deactivate" to restore
+// their shell afterwards.
+func onDatabaseActivate(cf *CLIConf) error {
+	tc, err := makeClient(cf, false)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	profile, err := client.StatusCurrent(cf.HomePath, cf.Proxy)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	database, err := pickActiveDatabase(cf)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	rootCluster, err := tc.RootClusterName()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	dir := cf.DatabaseActivateDir
+	if dir == "" {
+		dir = "teleport-" + database.ServiceName
+	}
+	if err := dbcmd.NewCmdBuilder(tc, profile, database, rootCluster).WriteActivationScripts(dir); err != nil {
+		return trace.Wrap(err)
+	}
+	fmt.Printf("Wrote activation script. To use it:\n\n  source %v\n", filepath.Join(dir, "activate"))
+	return nil
+}
+
 // onDatabaseConfig implements "tsh db config" command.
 func onDatabaseConfig(cf *CLIConf) error {
 	tc, err := makeClient(cf, false)
@@ -254,41 +330,180 @@ func onDatabaseConfig(cf *CLIConf) error {
 	}
 	switch cf.Format {
 	case dbFormatCommand:
-		cmd, err := newCmdBuilder(tc, profile, database, rootCluster).getConnectCommand()
+		// Domain-joined SQL Server databases authenticate via Kerberos
+		// rather than a SQL auth fixture password; detect that from the
+		// database's Active Directory metadata the same way "tsh db
+		// connect" does, so the printed command doesn't show a misleading
+		// -U/-P pair for a database that actually expects -E.
+		var opts []dbcmd.ConnectCommandFunc
+		if database.Protocol == defaults.ProtocolSQLServer {
+			if sqlServerDB, err := getDatabase(cf, tc, database.ServiceName); err != nil {
+				log.WithError(err).Debugf("Failed to look up database %q for Active Directory authentication detection.", database.ServiceName)
+			} else if sqlServerDB.GetAD().Domain != "" {
+				opts = append(opts, dbcmd.WithADKerberosAuth(krb5CCachePath()))
+			}
+		}
+		cmd, err := dbcmd.NewCmdBuilder(tc, profile, database, rootCluster, opts...).GetConnectCommand()
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		fmt.Println(dbcmd.RedactCommand(database.Protocol, cmd))
+	case dbFormatURI:
+		uri, err := dbcmd.NewCmdBuilder(tc, profile, database, rootCluster).GetConnectionURI()
 		if err != nil {
 			return trace.Wrap(err)
 		}
-		fmt.Println(cmd.Path, strings.Join(cmd.Args[1:], " "))
+		fmt.Println(uri)
+	case dbFormatJSON:
+		params := dbcmd.NewCmdBuilder(tc, profile, database, rootCluster).ConnectionParams()
+		out, err := json.MarshalIndent(params, "", "  ")
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		fmt.Println(string(out))
+	case dbFormatJDBC:
+		jdbcURL, err := dbcmd.NewCmdBuilder(tc, profile, database, rootCluster).GetJDBCConnectionURL()
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		fmt.Println(jdbcURL)
+	case dbFormatODBC:
+		odbcString, err := dbcmd.NewCmdBuilder(tc, profile, database, rootCluster).GetODBCConnectionString()
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		fmt.Println(odbcString)
 	default:
 		fmt.Printf(`Name:      %v
 Host:      %v
 Port:      %v
 User:      %v
 Database:  %v
+Cluster:   %v
 CA:        %v
 Cert:      %v
 Key:       %v
 `,
 			database.ServiceName, host, port, database.Username,
-			database.Database, profile.CACertPathForCluster(rootCluster),
+			database.Database, formatClusterChain(rootCluster, tc.SiteName),
+			profile.CACertPathForCluster(rootCluster),
 			profile.DatabaseCertPathForCluster(tc.SiteName, database.ServiceName), profile.KeyPath())
 	}
 	return nil
 }
 
+// formatClusterChain describes the routing path a database connection takes
+// to reach siteName: just the cluster name when connecting directly to the
+// root cluster, or "root -> leaf" when siteName is a trusted leaf cluster
+// reached through the root proxy, so it's clear at a glance which cluster's
+// role/auth policy actually applies to the connection.
+func formatClusterChain(rootCluster, siteName string) string {
+	if siteName == "" || siteName == rootCluster {
+		return rootCluster
+	}
+	return fmt.Sprintf("%v -> %v", rootCluster, siteName)
+}
+
+// onDatabaseSetLocalProxy implements "tsh db set-local-proxy" command,
+// persisting local proxy settings for a database in the user's profile so
+// tools that are configured once (e.g. GUI database clients) keep working
+// across tsh sessions.
+func onDatabaseSetLocalProxy(cf *CLIConf) error {
+	tc, err := makeClient(cf, false)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	profileName := tc.WebProxyHost()
+	savedProfile, err := apiprofile.FromDir(cf.HomePath, profileName)
+	if err != nil {
+		return trace.Wrap(err, "loading profile for %v", profileName)
+	}
+	if savedProfile.DatabaseLocalProxies == nil {
+		savedProfile.DatabaseLocalProxies = make(map[string]apiprofile.DatabaseLocalProxyProfile)
+	}
+	savedProfile.DatabaseLocalProxies[cf.DatabaseService] = apiprofile.DatabaseLocalProxyProfile{
+		Port:      cf.DatabaseLocalProxyPort,
+		NoTLS:     cf.DatabaseLocalProxyNoTLS,
+		AutoStart: cf.DatabaseLocalProxyAutoStart,
+	}
+	if err := savedProfile.SaveToDir(cf.HomePath, false); err != nil {
+		return trace.Wrap(err, "saving profile for %v", profileName)
+	}
+	fmt.Printf("Local proxy settings for %q saved.\n", cf.DatabaseService)
+	return nil
+}
+
+// onDatabaseDoctor reports, for every supported database protocol, whether
+// its native client is installed on this machine and what version it
+// reports, so users can fix their toolchain before running "tsh db connect".
+func onDatabaseDoctor(cf *CLIConf) error {
+	checks := dbcmd.Doctor(dbcmd.SystemExecer{})
+
+	t := asciitable.MakeTable([]string{"Protocol", "Client", "Found", "Path", "Version"})
+	for _, check := range checks {
+		found := "no"
+		if check.Found {
+			found = "yes"
+		}
+		t.AddRow([]string{check.Protocol, check.Binary, found, check.Path, check.Version})
+	}
+	fmt.Println(t.AsBuffer().String())
+	return nil
+}
+
+// rememberLocalProxyPort persists port as the local proxy port to reuse for
+// serviceName the next time a command starts a local proxy for it without
+// an explicit --port, the same way "tsh db set-local-proxy" does for a
+// user-chosen port, so ports stay stable across invocations without
+// requiring that explicit step. It's best-effort: a failure to persist
+// doesn't affect the connection already in progress.
+func rememberLocalProxyPort(cf *CLIConf, profileName, serviceName string, port int) error {
+	savedProfile, err := apiprofile.FromDir(cf.HomePath, profileName)
+	if err != nil {
+		return trace.Wrap(err, "loading profile for %v", profileName)
+	}
+	if savedProfile.DatabaseLocalProxies == nil {
+		savedProfile.DatabaseLocalProxies = make(map[string]apiprofile.DatabaseLocalProxyProfile)
+	}
+	persisted := savedProfile.DatabaseLocalProxies[serviceName]
+	persisted.Port = port
+	savedProfile.DatabaseLocalProxies[serviceName] = persisted
+	return trace.Wrap(savedProfile.SaveToDir(cf.HomePath, false), "saving profile for %v", profileName)
+}
+
 // maybeStartLocalProxy starts local TLS ALPN proxy if needed depending on the
 // connection scenario and returns a list of options to use in the connect
 // command.
-func maybeStartLocalProxy(cf *CLIConf, tc *client.TeleportClient, profile *client.ProfileStatus, db *tlsca.RouteToDatabase, cluster string) ([]ConnectCommandFunc, error) {
+func maybeStartLocalProxy(cf *CLIConf, tc *client.TeleportClient, profile *client.ProfileStatus, db *tlsca.RouteToDatabase, cluster string) ([]dbcmd.ConnectCommandFunc, error) {
 	// Local proxy is started if TLS routing is enabled, or if this is a SQL
 	// Server connection which always requires a local proxy.
 	if !tc.TLSRoutingEnabled && db.Protocol != defaults.ProtocolSQLServer {
-		return []ConnectCommandFunc{}, nil
+		return []dbcmd.ConnectCommandFunc{}, nil
+	}
+
+	// If the user has persisted a local proxy configuration for this
+	// database (e.g. for a GUI client that expects a fixed port), honor it
+	// so the endpoint stays stable across tsh sessions.
+	var persisted apiprofile.DatabaseLocalProxyProfile
+	if rawProfile, err := apiprofile.FromDir(cf.HomePath, tc.WebProxyHost()); err == nil {
+		persisted = rawProfile.DatabaseLocalProxies[db.ServiceName]
+	}
+
+	port := persisted.Port
+	// An admin-configured fixed port from tsh config takes precedence over a
+	// user-persisted one, so a managed workstation's firewall rules can't be
+	// bypassed by a stale or locally edited profile.
+	if configuredPort, ok := cf.DatabaseLocalProxyPorts[db.ServiceName]; ok {
+		port = configuredPort
 	}
 
-	listener, err := net.Listen("tcp", "localhost:0")
+	listenAddr := "localhost:0"
+	if port != 0 {
+		listenAddr = fmt.Sprintf("localhost:%d", port)
+	}
+	listener, err := net.Listen("tcp", listenAddr)
 	if err != nil {
-		return nil, trace.Wrap(err)
+		return nil, trace.Wrap(err, "starting local proxy for %q on port %d", db.ServiceName, port)
 	}
 
 	opts := localProxyOpts{
@@ -298,13 +513,44 @@ func maybeStartLocalProxy(cf *CLIConf, tc *client.TeleportClient, profile *clien
 		insecure:  cf.InsecureSkipVerify,
 	}
 
+	if cf.DatabaseTLSMinVersion != "" {
+		minVersion, err := utils.ParseTLSVersion(cf.DatabaseTLSMinVersion)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		opts.minTLSVersion = minVersion
+	}
+	if len(cf.DatabaseTLSCipherSuites) > 0 {
+		cipherSuites, err := utils.CipherSuiteMapping(cf.DatabaseTLSCipherSuites)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		opts.cipherSuites = cipherSuites
+	}
+
 	// For SQL Server connections, local proxy must be configured with the
-	// client certificate that will be used to route connections.
+	// client certificate that will be used to route connections. Use
+	// tc.SiteName rather than the profile's default cluster, since this
+	// command may be targeting a different (e.g. leaf) cluster for this
+	// invocation only.
 	if db.Protocol == defaults.ProtocolSQLServer {
-		opts.certFile = profile.DatabaseCertPathForCluster("", db.ServiceName)
+		opts.certFile = profile.DatabaseCertPathForCluster(tc.SiteName, db.ServiceName)
 		opts.keyFile = profile.KeyPath()
 	}
 
+	// Domain-joined SQL Server databases authenticate via Kerberos rather
+	// than a SQL auth fixture password; the native client needs to be told
+	// to use integrated auth and given a credential cache to authenticate
+	// with.
+	var adOpts []dbcmd.ConnectCommandFunc
+	if db.Protocol == defaults.ProtocolSQLServer {
+		if sqlServerDB, err := getDatabase(cf, tc, db.ServiceName); err != nil {
+			log.WithError(err).Debugf("Failed to look up database %q for Active Directory authentication detection.", db.ServiceName)
+		} else if sqlServerDB.GetAD().Domain != "" {
+			adOpts = append(adOpts, dbcmd.WithADKerberosAuth(krb5CCachePath()))
+		}
+	}
+
 	lp, err := mkLocalProxy(cf.Context, opts)
 	if err != nil {
 		return nil, trace.Wrap(err)
@@ -326,9 +572,40 @@ func maybeStartLocalProxy(cf *CLIConf, tc *client.TeleportClient, profile *clien
 	// certificate's DNS names. As such, connecting to 127.0.0.1 will fail
 	// validation, so connect to localhost.
 	host := "localhost"
-	return []ConnectCommandFunc{
-		WithLocalProxy(host, addr.Port(0), profile.CACertPathForCluster(cluster)),
-	}, nil
+	cmdOpts := []dbcmd.ConnectCommandFunc{
+		// cluster is the root cluster name; dbprofile.CACertPath prefers a
+		// trusted leaf cluster's own CA bundle over the root's when tc is
+		// currently targeting a leaf cluster database.
+		dbcmd.WithLocalProxy(host, addr.Port(0), dbprofile.CACertPath(tc, *profile, cluster)),
+	}
+	if cf.DatabaseTLSMinVersion != "" {
+		cmdOpts = append(cmdOpts, dbcmd.WithTLSMinVersion(cf.DatabaseTLSMinVersion))
+	}
+	if cf.DatabaseCRLPath != "" {
+		cmdOpts = append(cmdOpts, dbcmd.WithCRLPath(cf.DatabaseCRLPath))
+	}
+	if persisted.NoTLS {
+		cmdOpts = append(cmdOpts, dbcmd.WithNoTLS())
+	}
+	if cf.DatabasePostgresDisableGSSAPI {
+		cmdOpts = append(cmdOpts, dbcmd.WithGSSAPIDisabled())
+	}
+	cmdOpts = append(cmdOpts, adOpts...)
+	return cmdOpts, nil
+}
+
+// krb5CCachePath returns the path of the Kerberos credential cache to use
+// for integrated auth against a domain-joined database, honoring KRB5CCNAME
+// if the user already has one configured (e.g. via kinit) and otherwise
+// falling back to the MIT Kerberos default location for the current user.
+func krb5CCachePath() string {
+	if path := os.Getenv("KRB5CCNAME"); path != "" {
+		return strings.TrimPrefix(path, "FILE:")
+	}
+	if u, err := user.Current(); err == nil {
+		return filepath.Join(os.TempDir(), "krb5cc_"+u.Uid)
+	}
+	return filepath.Join(os.TempDir(), "krb5cc")
 }
 
 // onDatabaseConnect implements "tsh db connect" command.
@@ -369,21 +646,161 @@ func onDatabaseConnect(cf *CLIConf) error {
 	if err != nil {
 		return trace.Wrap(err)
 	}
-	cmd, err := newCmdBuilder(tc, profile, database, rootClusterName, opts...).getConnectCommand()
+
+	var keyPassphrase string
+	if cf.AskDatabaseKeyPassphrase {
+		keyPassphrase, err = prompt.Password(cf.Context, os.Stderr, prompt.Stdin(), "Enter a passphrase to protect the database client key with")
+		if err != nil {
+			return trace.Wrap(err)
+		}
+	}
+
+	// When the agent is configured to keep keys in memory only (no-disk
+	// mode), or the client key needs to be passphrase-protected, the
+	// profile directory doesn't hold a key the native client can use as-is.
+	// Materialize one into a short-lived temp directory for the lifetime of
+	// this command instead.
+	if tc.AddKeysToAgent == client.AddKeysToAgentOnly || keyPassphrase != "" {
+		keyPath, certPath, cleanup, err := writeEphemeralDatabaseCerts(key, database.ServiceName, keyPassphrase)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		defer cleanup()
+		opts = append(opts, dbcmd.WithEphemeralCerts(keyPath, certPath))
+	}
+	if keyPassphrase != "" {
+		opts = append(opts, dbcmd.WithKeyPassphrase(keyPassphrase))
+	}
+
+	// Explicit path overrides take precedence over any ephemeral certs
+	// written above, for users whose certs are issued by external tooling
+	// (e.g. Machine ID, Vault) into custom locations.
+	if cf.DatabaseCAPath != "" {
+		opts = append(opts, dbcmd.WithCAPath(cf.DatabaseCAPath))
+	}
+	if cf.DatabaseCertPath != "" || cf.DatabaseKeyPath != "" {
+		opts = append(opts, dbcmd.WithEphemeralCerts(cf.DatabaseKeyPath, cf.DatabaseCertPath))
+	}
+	if len(cf.DatabaseFlags) > 0 {
+		opts = append(opts, dbcmd.WithExtraArgs(cf.DatabaseFlags))
+	}
+	if len(cf.DatabaseContainerImages) > 0 {
+		opts = append(opts, dbcmd.WithContainerImages(cf.DatabaseContainerImages))
+	}
+	for protocol, path := range cf.DatabaseClientBinaries {
+		opts = append(opts, dbcmd.WithCustomBinary(protocol, path))
+	}
+	if cf.DatabaseEndpoint != "" {
+		opts = append(opts, dbcmd.WithEndpoint(cf.DatabaseEndpoint))
+	}
+	if len(cf.DatabaseCommandArgs) > 0 {
+		opts = append(opts, dbcmd.WithPassthroughArgs(cf.DatabaseCommandArgs))
+	}
+	if cf.DatabaseMySQLLoginPath {
+		opts = append(opts, dbcmd.WithMySQLLoginPath())
+	}
+	if cf.DatabasePostgresServiceFile {
+		opts = append(opts, dbcmd.WithPostgresServiceFile())
+	}
+	if cf.DatabaseMySQLOptionFile {
+		optionFilePath, cleanup, err := writeEphemeralMySQLOptionFilePath()
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		defer cleanup()
+		opts = append(opts, dbcmd.WithMySQLOptionFile(optionFilePath))
+	}
+	if cf.DatabaseExecQuery != "" {
+		opts = append(opts, dbcmd.WithExecQuery(cf.DatabaseExecQuery))
+	}
+
+	if dbInfo, err := getDatabase(cf, tc, database.ServiceName); err == nil {
+		if status := dbcmd.GetAutoUserProvisioningStatus(dbInfo); status != "" {
+			fmt.Println(status)
+		}
+		if dbcmd.IsRedisClusterMode(dbInfo) {
+			opts = append(opts, dbcmd.WithRedisClusterMode(true))
+		}
+	}
+
+	cmd, err := dbcmd.NewCmdBuilder(tc, profile, database, rootClusterName, opts...).GetConnectCommand()
 	if err != nil {
 		return trace.Wrap(err)
 	}
-	log.Debug(cmd.String())
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	cmd.Stdin = os.Stdin
+	log.Debug(dbcmd.RedactCommand(database.Protocol, cmd))
+	endSpan := dbcmd.TraceSpan(log, "dbcmd.client_launch")
 	err = cmd.Run()
+	endSpan()
 	if err != nil {
 		return trace.Wrap(err)
 	}
 	return nil
 }
 
+// writeEphemeralDatabaseCerts writes the given key's database private key
+// and certificate for serviceName to a temp directory only readable by the
+// current user, for use with native database clients that require a file
+// path. Callers must invoke the returned cleanup function once the command
+// using the files has finished running. If passphrase is non-empty, the
+// private key is PEM-encrypted with it before being written out.
+func writeEphemeralDatabaseCerts(key *client.Key, serviceName, passphrase string) (keyPath, certPath string, cleanup func(), err error) {
+	dir, err := os.MkdirTemp("", "tsh-db-*")
+	if err != nil {
+		return "", "", nil, trace.Wrap(err)
+	}
+	cleanup = func() { os.RemoveAll(dir) }
+
+	priv := key.Priv
+	if dbPriv, ok := key.DBTLSKeys[serviceName]; ok {
+		priv = dbPriv
+	}
+	if passphrase != "" {
+		priv, err = encryptPEMKey(priv, passphrase)
+		if err != nil {
+			cleanup()
+			return "", "", nil, trace.Wrap(err)
+		}
+	}
+	keyPath = filepath.Join(dir, "key")
+	if err := os.WriteFile(keyPath, priv, 0600); err != nil {
+		cleanup()
+		return "", "", nil, trace.Wrap(err)
+	}
+	certPath = filepath.Join(dir, "cert")
+	if err := os.WriteFile(certPath, key.DBTLSCerts[serviceName], 0600); err != nil {
+		cleanup()
+		return "", "", nil, trace.Wrap(err)
+	}
+	return keyPath, certPath, cleanup, nil
+}
+
+// writeEphemeralMySQLOptionFilePath returns the path of a temporary,
+// not-yet-created MySQL option file suitable for passing to the mysql
+// client via --defaults-extra-file. The caller is responsible for
+// populating it, e.g. via dbcmd.WithMySQLOptionFile.
+func writeEphemeralMySQLOptionFilePath() (path string, cleanup func(), err error) {
+	dir, err := os.MkdirTemp("", "tsh-db-*")
+	if err != nil {
+		return "", nil, trace.Wrap(err)
+	}
+	return filepath.Join(dir, "my.cnf"), func() { os.RemoveAll(dir) }, nil
+}
+
+// encryptPEMKey re-encodes a PEM-encoded private key with passphrase
+// protection so that clients supporting encrypted keys can prompt for it.
+func encryptPEMKey(keyPEM []byte, passphrase string) ([]byte, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, trace.BadParameter("invalid PEM-encoded private key")
+	}
+	//nolint:staticcheck // SA1019 dbcmd talks to native clients that only understand the legacy encrypted PEM format.
+	encBlock, err := x509.EncryptPEMBlock(rand.Reader, block.Type, block.Bytes, []byte(passphrase), x509.PEMCipherAES256)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return pem.EncodeToMemory(encBlock), nil
+}
+
 // getDatabaseInfo fetches information about the database from tsh profile is DB is active in profile. Otherwise,
 // the ListDatabases endpoint is called.
 func getDatabaseInfo(cf *CLIConf, tc *client.TeleportClient, dbName string) (*tlsca.RouteToDatabase, error) {
@@ -573,34 +990,6 @@ func pickActiveDatabase(cf *CLIConf) (*tlsca.RouteToDatabase, error) {
 	return nil, trace.NotFound("Not logged into database %q", name)
 }
 
-type connectionCommandOpts struct {
-	localProxyPort int
-	localProxyHost string
-	caPath         string
-	noTLS          bool
-}
-
-type ConnectCommandFunc func(*connectionCommandOpts)
-
-func WithLocalProxy(host string, port int, caPath string) ConnectCommandFunc {
-	return func(opts *connectionCommandOpts) {
-		opts.localProxyPort = port
-		opts.localProxyHost = host
-		opts.caPath = caPath
-	}
-}
-
-// WithNoTLS is the connect command option that makes the command connect
-// without TLS.
-//
-// It is used when connecting through the local proxy that was started in
-// mutual TLS mode (i.e. with a client certificate).
-func WithNoTLS() ConnectCommandFunc {
-	return func(opts *connectionCommandOpts) {
-		opts.noTLS = true
-	}
-}
-
 func formatDatabaseListCommand(clusterFlag string) string {
 	if clusterFlag == "" {
 		return "tsh db ls"
@@ -641,4 +1030,17 @@ const (
 	dbFormatText = "text"
 	// dbFormatCommand prints database connection command.
 	dbFormatCommand = "cmd"
+	// dbFormatURI prints a database connection URI, e.g. for pasting into a
+	// GUI client. Only supported for MongoDB.
+	dbFormatURI = "uri"
+	// dbFormatJSON prints the resolved connection parameters (host, port,
+	// cert paths, TLS mode, ...) as JSON, for GUI callers that want to
+	// build their own UI instead of re-parsing a generated command line.
+	dbFormatJSON = "json"
+	// dbFormatJDBC prints a JDBC connection URL, for pasting into JDBC-based
+	// GUI clients such as DataGrip or DBeaver.
+	dbFormatJDBC = "jdbc"
+	// dbFormatODBC prints an ODBC DSN-style connection string, for pasting
+	// into ODBC-based GUI clients.
+	dbFormatODBC = "odbc"
 )
@@ -32,9 +32,11 @@ import (
 	"github.com/gravitational/trace"
 
 	"github.com/gravitational/teleport/api/profile"
+	apiprofile "github.com/gravitational/teleport/api/profile"
 	"github.com/gravitational/teleport/api/utils/keypaths"
 	"github.com/gravitational/teleport/lib/client"
 	libclient "github.com/gravitational/teleport/lib/client"
+	"github.com/gravitational/teleport/lib/client/db/dbcmd"
 	"github.com/gravitational/teleport/lib/defaults"
 	"github.com/gravitational/teleport/lib/srv/alpnproxy"
 	alpncommon "github.com/gravitational/teleport/lib/srv/alpnproxy/common"
@@ -143,6 +145,17 @@ func sshProxy(cf *CLIConf, tc *libclient.TeleportClient, targetHost, targetPort
 }
 
 func onProxyCommandDB(cf *CLIConf) error {
+	if cf.LocalProxyRemoteHost != "" {
+		// The local proxy terminates TLS using the client key/cert that live
+		// on this device; running the listener on a remote host would
+		// require forwarding those signing operations over the SSH agent
+		// channel the way "tsh ssh" forwards the user's SSH key today, which
+		// dbcmd and the local proxy don't yet support.
+		return trace.NotImplemented("running the database local proxy on a remote host (%q) is not yet supported; "+
+			"signing stays on this device, so run \"tsh proxy db\" here and reach it from the remote host over the network instead",
+			cf.LocalProxyRemoteHost)
+	}
+
 	client, err := makeClient(cf, false)
 	if err != nil {
 		return trace.Wrap(err)
@@ -160,14 +173,37 @@ func onProxyCommandDB(cf *CLIConf) error {
 		return trace.Wrap(err)
 	}
 
+	// Reuse whatever port was last assigned to this database, same as
+	// maybeStartLocalProxy does for "tsh db connect", so a saved GUI client
+	// config pointing at a fixed port keeps working across invocations.
+	var persistedProxy apiprofile.DatabaseLocalProxyProfile
+	if rawProfile, err := apiprofile.FromDir(cf.HomePath, client.WebProxyHost()); err == nil {
+		persistedProxy = rawProfile.DatabaseLocalProxies[database.ServiceName]
+	}
+
 	addr := "localhost:0"
 	if cf.LocalProxyPort != "" {
 		addr = fmt.Sprintf("127.0.0.1:%s", cf.LocalProxyPort)
+	} else if persistedProxy.Port != 0 {
+		addr = fmt.Sprintf("127.0.0.1:%d", persistedProxy.Port)
 	}
 	listener, err := net.Listen("tcp", addr)
+	if err != nil && cf.LocalProxyPort == "" && persistedProxy.Port != 0 {
+		// The remembered port may have been taken by something else since
+		// last time; fall back to a fresh ephemeral one rather than failing
+		// the whole command.
+		listener, err = net.Listen("tcp", "localhost:0")
+	}
 	if err != nil {
 		return trace.Wrap(err)
 	}
+	if cf.LocalProxyPort == "" {
+		if tcpAddr, ok := listener.Addr().(*net.TCPAddr); ok && tcpAddr.Port != persistedProxy.Port {
+			if err := rememberLocalProxyPort(cf, client.WebProxyHost(), database.ServiceName, tcpAddr.Port); err != nil {
+				log.WithError(err).Debugf("Failed to remember local proxy port for %q.", database.ServiceName)
+			}
+		}
+	}
 	defer func() {
 		if err := listener.Close(); err != nil {
 			log.WithError(err).Warnf("Failed to close listener.")
@@ -206,9 +242,9 @@ func onProxyCommandDB(cf *CLIConf) error {
 		if err != nil {
 			return trace.Wrap(err)
 		}
-		cmd, err := newCmdBuilder(client, profile, database, cf.SiteName,
-			WithLocalProxy("localhost", addr.Port(0), ""),
-			WithNoTLS()).getConnectCommand()
+		cmd, err := dbcmd.NewCmdBuilder(client, profile, database, cf.SiteName,
+			dbcmd.WithLocalProxy("localhost", addr.Port(0), ""),
+			dbcmd.WithNoTLS()).GetConnectCommand()
 		if err != nil {
 			return trace.Wrap(err)
 		}
@@ -216,7 +252,7 @@ func onProxyCommandDB(cf *CLIConf) error {
 			"database": database.ServiceName,
 			"type":     dbProtocolToText(database.Protocol),
 			"cluster":  profile.Cluster,
-			"command":  cmd.String(),
+			"command":  dbcmd.RedactCommand(database.Protocol, cmd),
 			"address":  listener.Addr().String(),
 		})
 		if err != nil {
@@ -243,12 +279,14 @@ func onProxyCommandDB(cf *CLIConf) error {
 }
 
 type localProxyOpts struct {
-	proxyAddr string
-	listener  net.Listener
-	protocol  string
-	insecure  bool
-	certFile  string
-	keyFile   string
+	proxyAddr     string
+	listener      net.Listener
+	protocol      string
+	insecure      bool
+	certFile      string
+	keyFile       string
+	minTLSVersion uint16
+	cipherSuites  []uint16
 }
 
 func mkLocalProxy(ctx context.Context, opts localProxyOpts) (*alpnproxy.LocalProxy, error) {
@@ -272,6 +310,8 @@ func mkLocalProxy(ctx context.Context, opts localProxyOpts) (*alpnproxy.LocalPro
 		ParentContext:      ctx,
 		SNI:                address.Host(),
 		Certs:              certs,
+		MinTLSVersion:      opts.minTLSVersion,
+		CipherSuites:       opts.cipherSuites,
 	})
 	if err != nil {
 		return nil, trace.Wrap(err)
@@ -49,6 +49,7 @@ import (
 	"github.com/gravitational/teleport/lib/benchmark"
 	"github.com/gravitational/teleport/lib/client"
 	dbprofile "github.com/gravitational/teleport/lib/client/db"
+	"github.com/gravitational/teleport/lib/client/db/dbcmd"
 	"github.com/gravitational/teleport/lib/client/identityfile"
 	"github.com/gravitational/teleport/lib/defaults"
 	"github.com/gravitational/teleport/lib/events"
@@ -148,6 +149,71 @@ type CLIConf struct {
 	DatabaseUser string
 	// DatabaseName specifies database name to embed in the certificate.
 	DatabaseName string
+	// DatabaseTLSMinVersion specifies the minimum TLS version the database
+	// client should accept when connecting through the local proxy.
+	DatabaseTLSMinVersion string
+	// DatabaseTLSCipherSuites specifies the cipher suites the database
+	// client should allow when connecting through the local proxy.
+	DatabaseTLSCipherSuites []string
+	// DatabaseCRLPath specifies the path to a certificate revocation list
+	// the database client should check server certificates against.
+	DatabaseCRLPath string
+	// DatabasePostgresDisableGSSAPI disables GSS/SSPI encryption negotiation
+	// in generated psql connection strings, which can otherwise stall in
+	// environments where the proxy or server doesn't support it.
+	DatabasePostgresDisableGSSAPI bool
+	// AskDatabaseKeyPassphrase prompts for a passphrase to protect the
+	// database client key with, for clients that support encrypted keys.
+	AskDatabaseKeyPassphrase bool
+	// DatabaseLocalProxyPort is the fixed local port to persist for the
+	// database's local proxy, so it can be set up once in a GUI client and
+	// keep working across tsh sessions.
+	DatabaseLocalProxyPort int
+	// DatabaseLocalProxyNoTLS persists a preference to start the database's
+	// local proxy without TLS termination.
+	DatabaseLocalProxyNoTLS bool
+	// DatabaseLocalProxyAutoStart persists a preference to automatically
+	// start the database's local proxy on connect.
+	DatabaseLocalProxyAutoStart bool
+	// DatabaseCAPath overrides the CA certificate path presented to the
+	// database client, for certificates issued by external tooling (e.g.
+	// Machine ID, Vault) into a custom location.
+	DatabaseCAPath string
+	// DatabaseCertPath overrides the certificate path presented to the
+	// database client, for certificates issued by external tooling into a
+	// custom location.
+	DatabaseCertPath string
+	// DatabaseKeyPath overrides the private key path presented to the
+	// database client, for keys issued by external tooling into a custom
+	// location.
+	DatabaseKeyPath string
+	// DatabaseEndpoint selects an alternate database endpoint (e.g. a reader
+	// endpoint) for the generated client command to target.
+	DatabaseEndpoint string
+	// DatabaseCommandArgs are extra arguments passed through verbatim to the
+	// generated database client command, e.g. "tsh db connect mydb -- -v FOO=bar".
+	DatabaseCommandArgs []string
+	// DatabaseMySQLLoginPath stores password-auth MySQL/MariaDB credentials
+	// in an encrypted mysql_config_editor login-path entry instead of a
+	// plaintext --defaults-extra-file.
+	DatabaseMySQLLoginPath bool
+	// DatabasePostgresServiceFile makes the generated psql command connect
+	// via "service=<name>", referencing the pg_service.conf entry "tsh db
+	// login" already wrote, instead of a connection string with cert paths
+	// on the command line.
+	DatabasePostgresServiceFile bool
+	// DatabaseMySQLOptionFile makes the generated mysql/mariadb command
+	// (Oracle client only) connect via an isolated --defaults-extra-file
+	// instead of a --defaults-group-suffix section in the user's shared
+	// ~/.my.cnf.
+	DatabaseMySQLOptionFile bool
+	// DatabaseActivateDir overrides the directory "tsh db activate" writes
+	// its activation script and PATH shims to.
+	DatabaseActivateDir string
+	// DatabaseExecQuery makes the generated database client command run
+	// this query non-interactively and exit, instead of starting an
+	// interactive session.
+	DatabaseExecQuery string
 	// AppName specifies proxied application name.
 	AppName string
 	// Interactive, when set to true, launches remote command with the terminal attached
@@ -283,6 +349,12 @@ type CLIConf struct {
 	LocalProxyKeyFile string
 	// LocalProxyTunnel specifies whether local proxy will open auth'd tunnel.
 	LocalProxyTunnel bool
+	// LocalProxyRemoteHost, if set, requests that the database local proxy
+	// listener be reachable from the named remote host instead of only from
+	// localhost. Not yet supported: the local proxy terminates TLS using the
+	// key material on the local device, and there is currently no mechanism
+	// to forward those signing operations to a remote listener.
+	LocalProxyRemoteHost string
 
 	// ConfigProxyTarget is the node which should be connected to in `tsh config-proxy`.
 	ConfigProxyTarget string
@@ -309,6 +381,27 @@ type CLIConf struct {
 
 	// ExtraProxyHeaders is configuration read from the .tsh/config/config.yaml file.
 	ExtraProxyHeaders []ExtraProxyHeaders
+
+	// DatabaseFlags are extra database client flags read from the
+	// .tsh/config/config.yaml file, keyed by database protocol.
+	DatabaseFlags map[string][]string
+
+	// DatabaseContainerImages are the admin-approved container images for
+	// running database clients in a container, read from the
+	// .tsh/config/config.yaml file, keyed by database protocol.
+	DatabaseContainerImages map[string]string
+
+	// DatabaseClientBinaries overrides the native database client
+	// executable, read from the .tsh/config/config.yaml file, keyed by
+	// database protocol.
+	DatabaseClientBinaries map[string]string
+
+	// DatabaseLocalProxyPorts are admin-configured fixed local proxy ports,
+	// read from the .tsh/config/config.yaml file, keyed by database service
+	// name. They take precedence over a port persisted by "tsh db
+	// set-local-proxy" or picked at random, so firewall rules on managed
+	// workstations can target a stable port.
+	DatabaseLocalProxyPorts map[string]int
 }
 
 // Stdout returns the stdout writer.
@@ -489,6 +582,7 @@ func Run(args []string, opts ...cliOption) error {
 	proxyDB.Flag("cert-file", "Certificate file for proxy client TLS configuration").StringVar(&cf.LocalProxyCertFile)
 	proxyDB.Flag("key-file", "Key file for proxy client TLS configuration").StringVar(&cf.LocalProxyKeyFile)
 	proxyDB.Flag("tunnel", "Open authenticated tunnel using database's client certificate so clients don't need to authenticate").BoolVar(&cf.LocalProxyTunnel)
+	proxyDB.Flag("remote-host", "Run the local proxy listener on the named remote host instead of localhost, signing operations staying on this device. Not yet supported.").Hidden().StringVar(&cf.LocalProxyRemoteHost)
 	proxyApp := proxy.Command("app", "Start local TLS proxy for app connection when using Teleport in single-port mode")
 	proxyApp.Arg("app", "The name of the application to start local proxy for").Required().StringVar(&cf.AppName)
 	proxyApp.Flag("port", "Specifies the source port used by by the proxy app listener").Short('p').StringVar(&cf.LocalProxyPort)
@@ -511,15 +605,38 @@ func Run(args []string, opts ...cliOption) error {
 	dbEnv.Arg("db", "Print environment for the specified database").StringVar(&cf.DatabaseService)
 	// --db flag is deprecated in favor of positional argument for consistency with other commands.
 	dbEnv.Flag("db", "Print environment for the specified database.").Hidden().StringVar(&cf.DatabaseService)
+	dbActivate := db.Command("activate", "Write a virtualenv-style activation script for the configured database.")
+	dbActivate.Arg("db", "Write an activation script for the specified database.").StringVar(&cf.DatabaseService)
+	dbActivate.Flag("dir", "Directory to write the activation script and its PATH shims to. Defaults to \"teleport-<db>\" in the current directory.").StringVar(&cf.DatabaseActivateDir)
 	dbConfig := db.Command("config", "Print database connection information. Useful when configuring GUI clients.")
 	dbConfig.Arg("db", "Print information for the specified database.").StringVar(&cf.DatabaseService)
 	// --db flag is deprecated in favor of positional argument for consistency with other commands.
 	dbConfig.Flag("db", "Print information for the specified database.").Hidden().StringVar(&cf.DatabaseService)
-	dbConfig.Flag("format", fmt.Sprintf("Print format: %q to print in table format (default), %q to print connect command.", dbFormatText, dbFormatCommand)).StringVar(&cf.Format)
+	dbConfig.Flag("format", fmt.Sprintf("Print format: %q to print in table format (default), %q to print connect command, %q to print a connection URI (MongoDB only), %q to print resolved connection parameters, %q to print a JDBC connection URL, %q to print an ODBC connection string.", dbFormatText, dbFormatCommand, dbFormatURI, dbFormatJSON, dbFormatJDBC, dbFormatODBC)).StringVar(&cf.Format)
 	dbConnect := db.Command("connect", "Connect to a database.")
 	dbConnect.Arg("db", "Database service name to connect to.").StringVar(&cf.DatabaseService)
 	dbConnect.Flag("db-user", "Optional database user to log in as.").StringVar(&cf.DatabaseUser)
 	dbConnect.Flag("db-name", "Optional database name to log in to.").StringVar(&cf.DatabaseName)
+	dbConnect.Flag("tls-min-version", "Minimum TLS version accepted by the client ('1.0', '1.1', '1.2' or '1.3').").StringVar(&cf.DatabaseTLSMinVersion)
+	dbConnect.Flag("tls-cipher-suite", "TLS cipher suite allowed by the client. Can be specified multiple times.").StringsVar(&cf.DatabaseTLSCipherSuites)
+	dbConnect.Flag("crl-path", "Path to a certificate revocation list to check server certificates against, for clients that support revocation checking.").StringVar(&cf.DatabaseCRLPath)
+	dbConnect.Flag("no-gssapi", "Disable GSS/SSPI encryption negotiation in the generated psql command, for Postgres connections.").BoolVar(&cf.DatabasePostgresDisableGSSAPI)
+	dbConnect.Flag("ask-key-passphrase", "Prompt for a passphrase to protect the database client key with, for clients that support encrypted keys.").BoolVar(&cf.AskDatabaseKeyPassphrase)
+	dbConnect.Flag("ca-path", "Path to a CA certificate to present to the database client, overriding the one derived from the profile layout (e.g. for certificates issued by Machine ID or Vault).").StringVar(&cf.DatabaseCAPath)
+	dbConnect.Flag("cert-path", "Path to a certificate to present to the database client, overriding the one derived from the profile layout.").StringVar(&cf.DatabaseCertPath)
+	dbConnect.Flag("key-path", "Path to a private key to present to the database client, overriding the one derived from the profile layout.").StringVar(&cf.DatabaseKeyPath)
+	dbConnect.Flag("db-endpoint", "Alternate database endpoint to connect to (e.g. a reader endpoint), for databases that expose more than one.").StringVar(&cf.DatabaseEndpoint)
+	dbConnect.Flag("mysql-login-path", "Store password-auth MySQL/MariaDB credentials in an encrypted mysql_config_editor login-path entry instead of a plaintext options file. Requires mysql_config_editor.").BoolVar(&cf.DatabaseMySQLLoginPath)
+	dbConnect.Flag("service-file", "Connect psql using the pg_service.conf entry written by \"tsh db login\" instead of passing connection parameters on the command line. Postgres only.").BoolVar(&cf.DatabasePostgresServiceFile)
+	dbConnect.Flag("option-file", "Connect mysql/mariadb using an isolated option file instead of a --defaults-group-suffix section in the shared ~/.my.cnf. MySQL only, Oracle client only.").BoolVar(&cf.DatabaseMySQLOptionFile)
+	dbConnect.Flag("exec", "Run the given query non-interactively and exit, instead of starting an interactive session. Useful for scripting and health checks.").StringVar(&cf.DatabaseExecQuery)
+	dbConnect.Arg("args", "Extra arguments to pass through to the native database client, e.g. '-- -v FOO=bar' for psql.").StringsVar(&cf.DatabaseCommandArgs)
+	dbSetLocalProxy := db.Command("set-local-proxy", "Persist local proxy settings for a database so GUI clients keep working across tsh sessions.")
+	dbSetLocalProxy.Arg("db", "Database service name to configure.").Required().StringVar(&cf.DatabaseService)
+	dbSetLocalProxy.Flag("port", "Fixed local port for the database's local proxy. Use 0 to clear and pick a random port again.").IntVar(&cf.DatabaseLocalProxyPort)
+	dbSetLocalProxy.Flag("no-tls", "Start the local proxy without TLS termination.").BoolVar(&cf.DatabaseLocalProxyNoTLS)
+	dbSetLocalProxy.Flag("auto-start", "Automatically start the local proxy when connecting to this database.").BoolVar(&cf.DatabaseLocalProxyAutoStart)
+	dbDoctor := db.Command("doctor", "Report which native database client binaries are installed and their versions, to fix your toolchain before connecting.")
 
 	// join
 	join := app.Command("join", "Join the active SSH session")
@@ -598,6 +715,7 @@ func Run(args []string, opts ...cliOption) error {
 	// The status command shows which proxy the user is logged into and metadata
 	// about the certificate.
 	status := app.Command("status", "Display the list of proxy servers and retrieved certificates")
+	status.Flag("format", "Format output (text, json)").Short('f').Default(teleport.Text).StringVar(&cf.Format)
 
 	// The environment command prints out environment variables for the configured
 	// proxy and cluster. Can be used to create sessions "sticky" to a terminal
@@ -708,6 +826,13 @@ func Run(args []string, opts ...cliOption) error {
 		return trace.Wrap(err, "failed to load tsh config from %s", fullConfigPath)
 	}
 	cf.ExtraProxyHeaders = confOptions.ExtraHeaders
+	cf.DatabaseFlags = confOptions.DatabaseFlags
+	cf.DatabaseContainerImages = confOptions.DatabaseContainerImages
+	cf.DatabaseClientBinaries = confOptions.DatabaseClientBinaries
+	if err := validateDatabaseLocalProxyPorts(confOptions.DatabaseLocalProxyPorts); err != nil {
+		return trace.Wrap(err, "invalid database_local_proxy_ports in %s", fullConfigPath)
+	}
+	cf.DatabaseLocalProxyPorts = confOptions.DatabaseLocalProxyPorts
 
 	switch command {
 	case ver.FullCommand():
@@ -773,10 +898,16 @@ func Run(args []string, opts ...cliOption) error {
 		err = onDatabaseLogout(&cf)
 	case dbEnv.FullCommand():
 		err = onDatabaseEnv(&cf)
+	case dbActivate.FullCommand():
+		err = onDatabaseActivate(&cf)
 	case dbConfig.FullCommand():
 		err = onDatabaseConfig(&cf)
 	case dbConnect.FullCommand():
 		err = onDatabaseConnect(&cf)
+	case dbSetLocalProxy.FullCommand():
+		err = onDatabaseSetLocalProxy(&cf)
+	case dbDoctor.FullCommand():
+		err = onDatabaseDoctor(&cf)
 	case environment.FullCommand():
 		err = onEnvironment(&cf)
 	case mfa.ls.FullCommand():
@@ -1247,6 +1378,12 @@ func onLogout(cf *CLIConf) error {
 					return trace.Wrap(err)
 				}
 			}
+			if err := dbcmd.CleanupGeneratedFiles(profile); err != nil {
+				log.WithError(err).Warnf("Failed to clean up database helper files for %v.", profile.Name)
+			}
+		}
+		if err := dbprofile.CleanupStale(); err != nil {
+			log.WithError(err).Warn("Failed to clean up stale database connection profiles.")
 		}
 
 		// Remove keys for this user from disk and running agent.
@@ -1304,6 +1441,12 @@ func onLogout(cf *CLIConf) error {
 					return trace.Wrap(err)
 				}
 			}
+			if err := dbcmd.CleanupGeneratedFiles(profile); err != nil {
+				log.WithError(err).Warnf("Failed to clean up database helper files for %v.", profile.Name)
+			}
+		}
+		if err := dbprofile.CleanupStale(); err != nil {
+			log.WithError(err).Warn("Failed to clean up stale database connection profiles.")
 		}
 
 		// Remove all keys from disk and the running agent.
@@ -1570,9 +1713,9 @@ func showApps(apps []types.Application, active []tlsca.RouteToApp, verbose bool)
 	}
 }
 
-func showDatabases(clusterFlag string, databases []types.Database, active []tlsca.RouteToDatabase, verbose bool) {
+func showDatabases(clusterFlag string, databases []types.Database, active []tlsca.RouteToDatabase, health map[string]client.DatabaseConnectivityStatus, verbose bool) {
 	if verbose {
-		t := asciitable.MakeTable([]string{"Name", "Description", "Protocol", "Type", "URI", "Labels", "Connect", "Expires"})
+		t := asciitable.MakeTable([]string{"Name", "Description", "Protocol", "Type", "URI", "Labels", "Connect", "Expires", "Health"})
 		for _, database := range databases {
 			name := database.GetName()
 			var connect string
@@ -1591,6 +1734,7 @@ func showDatabases(clusterFlag string, databases []types.Database, active []tlsc
 				database.LabelsString(),
 				connect,
 				database.Expiry().Format(constants.HumanDateFormatSeconds),
+				formatDatabaseHealth(health[database.GetName()]),
 			})
 		}
 		fmt.Println(t.AsBuffer().String())
@@ -1617,6 +1761,21 @@ func showDatabases(clusterFlag string, databases []types.Database, active []tlsc
 	}
 }
 
+// formatDatabaseHealth renders a database's connectivity probe result for
+// the verbose "tsh db ls" table. An empty status (no probe performed, e.g.
+// the cluster has no databases to probe) renders as a blank cell rather
+// than a misleading "unreachable".
+func formatDatabaseHealth(status client.DatabaseConnectivityStatus) string {
+	switch {
+	case status.Database == nil:
+		return ""
+	case status.Reachable:
+		return fmt.Sprintf("reachable (%v)", status.Latency.Round(time.Millisecond))
+	default:
+		return fmt.Sprintf("unreachable: %v", status.Error)
+	}
+}
+
 func formatDatabaseLabels(database types.Database) string {
 	labels := database.GetAllLabels()
 	// Hide the origin label unless printing verbose table.
@@ -2350,6 +2509,12 @@ func printStatus(debug bool, p *client.ProfileStatus, isActive bool) {
 	if len(p.Databases) != 0 {
 		fmt.Printf("  Databases:          %v\n", strings.Join(p.DatabaseServices(), ", "))
 	}
+	if len(p.DatabaseUsers) > 0 {
+		fmt.Printf("  Database users:     %v\n", strings.Join(p.DatabaseUsers, ", "))
+	}
+	if len(p.DatabaseNames) > 0 {
+		fmt.Printf("  Database names:     %v\n", strings.Join(p.DatabaseNames, ", "))
+	}
 	fmt.Printf("  Valid until:        %v [%v]\n", p.ValidUntil, humanDuration)
 	fmt.Printf("  Extensions:         %v\n", strings.Join(p.Extensions, ", "))
 
@@ -2368,7 +2533,13 @@ func onStatus(cf *CLIConf) error {
 		return trace.Wrap(err)
 	}
 
-	printProfiles(cf.Debug, profile, profiles)
+	if cf.Format == teleport.JSON {
+		if err := printProfilesJSON(profile, profiles); err != nil {
+			return trace.Wrap(err)
+		}
+	} else {
+		printProfiles(cf.Debug, profile, profiles)
+	}
 
 	if profile == nil {
 		return trace.NotFound("Not logged in.")
@@ -2398,6 +2569,25 @@ func printProfiles(debug bool, profile *client.ProfileStatus, profiles []*client
 	}
 }
 
+// printProfilesJSON prints the active and other profiles as JSON so that
+// external tooling (e.g. a script driving "tsh db connect" indirectly via
+// the profile's saved database routes) can consume the status without
+// scraping the human-readable output.
+func printProfilesJSON(profile *client.ProfileStatus, profiles []*client.ProfileStatus) error {
+	out, err := json.MarshalIndent(struct {
+		Active   *client.ProfileStatus   `json:"active"`
+		Profiles []*client.ProfileStatus `json:"profiles"`
+	}{
+		Active:   profile,
+		Profiles: profiles,
+	}, "", "  ")
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	fmt.Println(string(out))
+	return nil
+}
+
 // host is a utility function that extracts
 // host from the host:port pair, in case of any error
 // returns the original value
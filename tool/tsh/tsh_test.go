@@ -770,6 +770,41 @@ func TestFormatConnectCommand(t *testing.T) {
 	}
 }
 
+func TestFormatDatabaseHealth(t *testing.T) {
+	database, err := types.NewDatabaseV3(types.Metadata{Name: "test"}, types.DatabaseSpecV3{
+		Protocol: defaults.ProtocolPostgres,
+		URI:      "localhost:5432",
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		comment string
+		status  client.DatabaseConnectivityStatus
+		want    string
+	}{
+		{
+			comment: "no probe performed",
+			status:  client.DatabaseConnectivityStatus{},
+			want:    "",
+		},
+		{
+			comment: "reachable",
+			status:  client.DatabaseConnectivityStatus{Database: database, Reachable: true, Latency: 42 * time.Millisecond},
+			want:    "reachable (42ms)",
+		},
+		{
+			comment: "unreachable",
+			status:  client.DatabaseConnectivityStatus{Database: database, Error: trace.ConnectionProblem(nil, "connection refused")},
+			want:    "unreachable: connection refused",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.comment, func(t *testing.T) {
+			require.Equal(t, test.want, formatDatabaseHealth(test.status))
+		})
+	}
+}
+
 func TestEnvFlags(t *testing.T) {
 	type testCase struct {
 		inCLIConf  CLIConf
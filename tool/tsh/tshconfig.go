@@ -35,6 +35,41 @@ type TshConfig struct {
 	// ExtraHeaders are additional http headers to be included in
 	// webclient requests.
 	ExtraHeaders []ExtraProxyHeaders `yaml:"add_headers"`
+	// DatabaseFlags are extra command-line flags appended to the native
+	// database client command generated by "tsh db connect", keyed by
+	// database protocol (e.g. "postgres", "mysql").
+	DatabaseFlags map[string][]string `yaml:"database_flags,omitempty"`
+	// DatabaseContainerImages are the admin-approved container images used
+	// when a database client is run inside a container instead of on the
+	// host, keyed by database protocol. Images should be pinned by digest
+	// (e.g. "postgres:15@sha256:...") to keep the container fallback mode
+	// usable under supply-chain policies that forbid floating tags.
+	DatabaseContainerImages map[string]string `yaml:"database_container_images,omitempty"`
+	// DatabaseClientBinaries overrides the native database client executable
+	// used by "tsh db connect", keyed by database protocol (e.g. "mysql").
+	// Useful when a client is kept in a non-PATH location or a specific
+	// version is required, skipping the usual PATH lookup and any
+	// client-flavor version detection for that protocol.
+	DatabaseClientBinaries map[string]string `yaml:"database_client_binaries,omitempty"`
+	// DatabaseLocalProxyPorts fixes the local proxy port used when
+	// connecting to a database, keyed by database service name, instead of
+	// picking a random free port. Useful for firewall rules on managed
+	// workstations that need a deterministic port per database.
+	DatabaseLocalProxyPorts map[string]int `yaml:"database_local_proxy_ports,omitempty"`
+}
+
+// validateDatabaseLocalProxyPorts checks that ports doesn't assign the same
+// fixed local proxy port to two different databases, which would make them
+// unusable at the same time no matter which one grabs the port first.
+func validateDatabaseLocalProxyPorts(ports map[string]int) error {
+	byPort := make(map[int]string, len(ports))
+	for service, port := range ports {
+		if conflict, ok := byPort[port]; ok {
+			return trace.BadParameter("database_local_proxy_ports assigns port %d to both %q and %q", port, conflict, service)
+		}
+		byPort[port] = service
+	}
+	return nil
 }
 
 // ExtraProxyHeaders represents the headers to include with the
@@ -43,3 +43,86 @@ func TestLoadConfigEmptyFile(t *testing.T) {
 	require.NoError(t, gotErr)
 	require.Equal(t, &TshConfig{}, gotConfig)
 }
+
+func TestLoadConfigWithDatabaseFlags(t *testing.T) {
+	file, err := os.CreateTemp("", "test-telelport")
+	require.NoError(t, err)
+	defer os.Remove(file.Name())
+
+	_, err = file.Write([]byte(`
+database_flags:
+  postgres:
+    - "--pager=pspg"
+  mysql:
+    - "--prompt=tsh> "
+`))
+	require.NoError(t, err)
+
+	gotConfig, gotErr := loadConfig(file.Name())
+	require.NoError(t, gotErr)
+	require.Equal(t, &TshConfig{
+		DatabaseFlags: map[string][]string{
+			"postgres": {"--pager=pspg"},
+			"mysql":    {"--prompt=tsh> "},
+		},
+	}, gotConfig)
+}
+
+func TestLoadConfigWithDatabaseLocalProxyPorts(t *testing.T) {
+	file, err := os.CreateTemp("", "test-telelport")
+	require.NoError(t, err)
+	defer os.Remove(file.Name())
+
+	_, err = file.Write([]byte(`
+database_local_proxy_ports:
+  postgres-prod: 12345
+  mysql-prod: 12346
+`))
+	require.NoError(t, err)
+
+	gotConfig, gotErr := loadConfig(file.Name())
+	require.NoError(t, gotErr)
+	require.Equal(t, &TshConfig{
+		DatabaseLocalProxyPorts: map[string]int{
+			"postgres-prod": 12345,
+			"mysql-prod":    12346,
+		},
+	}, gotConfig)
+}
+
+func TestValidateDatabaseLocalProxyPorts(t *testing.T) {
+	require.NoError(t, validateDatabaseLocalProxyPorts(nil))
+	require.NoError(t, validateDatabaseLocalProxyPorts(map[string]int{
+		"postgres-prod": 12345,
+		"mysql-prod":    12346,
+	}))
+
+	err := validateDatabaseLocalProxyPorts(map[string]int{
+		"postgres-prod": 12345,
+		"mysql-prod":    12345,
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "12345")
+}
+
+func TestLoadConfigWithDatabaseContainerImages(t *testing.T) {
+	file, err := os.CreateTemp("", "test-telelport")
+	require.NoError(t, err)
+	defer os.Remove(file.Name())
+
+	_, err = file.Write([]byte(`
+database_container_images:
+  postgres: "postgres:15@sha256:1234"
+  mysql: "mysql:8@sha256:5678"
+`))
+	require.NoError(t, err)
+
+	gotConfig, gotErr := loadConfig(file.Name())
+	require.NoError(t, gotErr)
+	require.Equal(t, &TshConfig{
+		DatabaseContainerImages: map[string]string{
+			"postgres": "postgres:15@sha256:1234",
+			"mysql":    "mysql:8@sha256:5678",
+		},
+	}, gotConfig)
+}